@@ -235,7 +235,7 @@ func main() {
 	// Вывод начальной информации о параметрах кода
 	fmt.Printf("k = %d, подобрано p = %d, Длина кода Хэмминга = %d\n", k, p, n)
 	fmt.Println("SEC:  исправление 1 ошибки (d_min = 3)")
-	fmt.Println("SECDED: обнаружение 2-х ошибок (d_min = 4)\n")
+	fmt.Print("SECDED: обнаружение 2-х ошибок (d_min = 4)\n\n")
 
 	// Построение проверочной матрицы H для кода Хэмминга
 	H := buildH(n, p)
@@ -335,7 +335,7 @@ func main() {
 			synStr += fmt.Sprintf("%d", syndrome[i])
 		}
 		fmt.Printf("Синдром (SEC): %s -> %d\n", synStr, synIndex)
-		printH()
+		printH(H)
 
 		fmt.Printf("\nРешение: %s\n", verdict)
 
@@ -394,8 +394,9 @@ func bitsToSpacedString(bits []int) string {
 	}
 	return strings.Join(strs, " ")
 }
-func printH() {
-	H := buildH(n, p)
+
+// printH выводит проверочную матрицу H, построенную один раз в main
+func printH(H [][]int) {
 	fmt.Printf("\n             ПРОВЕРОЧНАЯ МАТРИЦА H  (%d × %d)\n", p, n)
 	fmt.Print("Позиции → ")
 	for i := 1; i <= n; i++ {
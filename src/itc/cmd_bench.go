@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"itc/coding"
+	"itc/report"
+)
+
+// benchWords — число заранее подготовленных слов, по которым идут измерения,
+// чтобы генерация случайных данных не попадала в замер
+const benchWords = 1024
+
+// benchOp — измеряемая операция в представлениях []int и BitVec и, если
+// для неё есть таблицы, на BitVec с таблицами
+type benchOp struct {
+	name, title           string
+	slice, packed, tables func(b *testing.B)
+}
+
+// benchOps строит операции кодирования, вычисления синдрома, внесения ошибок
+// и декодирования над подготовленными словами
+func benchOps(code coding.LinearCode, pc *coding.PackedCode, t *coding.Tables, errors int, rng *rand.Rand) ([]benchOp, error) {
+	n, k := code.Length(), code.Dimension()
+	data := make([][]int, benchWords)
+	words := make([][]int, benchWords)
+	received := make([][]int, benchWords)
+	pdata := make([]coding.BitVec, benchWords)
+	pwords := make([]coding.BitVec, benchWords)
+	preceived := make([]coding.BitVec, benchWords)
+	for i := range data {
+		data[i] = coding.RandomBits(k, rng)
+		word, err := code.Encode(data[i])
+		if err != nil {
+			return nil, err
+		}
+		words[i] = word
+		if received[i], _, err = coding.InjectErrors(word, errors, rng); err != nil {
+			return nil, err
+		}
+		pdata[i] = coding.BitVecFromBits(data[i])
+		pwords[i] = coding.BitVecFromBits(words[i])
+		preceived[i] = coding.BitVecFromBits(received[i])
+	}
+	H := code.ParityCheckMatrix()
+	word, scratch, out := coding.NewBitVec(n), coding.NewBitVec(n), coding.NewBitVec(k)
+
+	return []benchOp{
+		{
+			name: "encode", title: "кодирование",
+			slice: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					code.Encode(data[i%benchWords])
+				}
+			},
+			packed: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					pc.EncodeTo(word, pdata[i%benchWords])
+				}
+			},
+			tables: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					t.EncodeTo(word, pdata[i%benchWords])
+				}
+			},
+		},
+		{
+			name: "syndrome", title: "синдром",
+			slice: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					coding.Multiply(H, received[i%benchWords])
+				}
+			},
+			packed: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					pc.Syndrome(preceived[i%benchWords])
+				}
+			},
+			tables: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					t.Syndrome(preceived[i%benchWords])
+				}
+			},
+		},
+		{
+			name: "inject", title: "внесение ошибок",
+			slice: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					coding.InjectErrors(words[i%benchWords], errors, rng)
+				}
+			},
+			packed: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					coding.InjectErrorsVec(pwords[i%benchWords], errors, rng)
+				}
+			},
+		},
+		{
+			name: "decode", title: "декодирование",
+			slice: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					code.Decode(received[i%benchWords])
+				}
+			},
+			packed: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					copy(scratch.Words(), preceived[i%benchWords].Words())
+					pc.Correct(scratch)
+					pc.ExtractTo(out, scratch)
+				}
+			},
+			tables: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					copy(scratch.Words(), preceived[i%benchWords].Words())
+					t.Correct(scratch)
+					t.ExtractTo(out, scratch)
+				}
+			},
+		},
+	}, nil
+}
+
+// throughput возвращает скорость обработки информационных бит в МБ/с
+func throughput(r testing.BenchmarkResult, k int) float64 {
+	if r.T <= 0 {
+		return 0
+	}
+	return float64(k) / 8 * float64(r.N) / r.T.Seconds() / 1e6
+}
+
+func runBench(path string, args []string) error {
+	fs := newFlagSet(path, "Скорость кодирования и декодирования: операции над словами []int (один бит в машинном\n"+
+		"слове) и над упакованными векторами BitVec (64 бита в слове) измеряются testing.Benchmark;\n"+
+		"кодирование, синдром и декодирование измеряются также с таблицами по байтам (команда tables).\n"+
+		"Скорость считается по информационным битам; по умолчанию — код Хэмминга (72,64) SECDED.")
+	codeSpec := fs.String("code", "secded:64", "код: hamming:k (систематический), positional:k или secded:k")
+	opsList := fs.String("ops", "encode,syndrome,inject,decode", "операции через запятую: encode, syndrome, inject, decode")
+	errors := fs.Int("errors", 1, "число ошибок в принятых словах")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	code, err := parseCodeSpec(*codeSpec)
+	if err != nil {
+		return fmt.Errorf("--code: %w", err)
+	}
+	if *errors < 0 || *errors > code.Length() {
+		return fmt.Errorf("--errors: ожидается от 0 до %d, получено %d", code.Length(), *errors)
+	}
+	pc, err := coding.NewPackedCode(code)
+	if err != nil {
+		return err
+	}
+	t, err := coding.NewTables(code)
+	if err != nil {
+		return err
+	}
+	ops, err := benchOps(code, pc, t, *errors, newRand())
+	if err != nil {
+		return err
+	}
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(*opsList, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, op := range ops {
+			found = found || op.name == name
+		}
+		if !found {
+			return fmt.Errorf("--ops: неизвестная операция %q (ожидается encode, syndrome, inject или decode)", name)
+		}
+		selected[name] = true
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "bench",
+		Title: fmt.Sprintf("%s: n = %d, k = %d, ошибок в слове: %d", code.Name(), code.Length(), code.Dimension(), *errors),
+		Columns: []report.Column{
+			{Key: "operation", Title: "Операция", Width: 15},
+			{Key: "representation", Title: "Представление", Width: 13},
+			{Key: "ns_per_op", Title: "нс/слово", Format: "%.1f", Width: 9},
+			{Key: "mb_per_s", Title: "МБ/с", Format: "%.2f", Width: 8},
+			{Key: "allocs_per_op", Title: "Выделений/слово", Format: "%d"},
+			{Key: "speedup", Title: "Ускорение", Format: "%.2f"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	k := code.Dimension()
+	for _, op := range ops {
+		if !selected[op.name] {
+			continue
+		}
+		slice := testing.Benchmark(func(b *testing.B) { b.ReportAllocs(); op.slice(b) })
+		packed := testing.Benchmark(func(b *testing.B) { b.ReportAllocs(); op.packed(b) })
+		sliceNs, packedNs := float64(slice.T.Nanoseconds())/float64(slice.N), float64(packed.T.Nanoseconds())/float64(packed.N)
+		if err := out.WriteRow(op.title, "[]int", sliceNs, throughput(slice, k), slice.AllocsPerOp(), 1.0); err != nil {
+			return err
+		}
+		if err := out.WriteRow(op.title, "BitVec", packedNs, throughput(packed, k), packed.AllocsPerOp(), sliceNs/packedNs); err != nil {
+			return err
+		}
+		if op.tables == nil {
+			continue
+		}
+		tables := testing.Benchmark(func(b *testing.B) { b.ReportAllocs(); op.tables(b) })
+		tablesNs := float64(tables.T.Nanoseconds()) / float64(tables.N)
+		if err := out.WriteRow(op.title, "таблицы", tablesNs, throughput(tables, k), tables.AllocsPerOp(), sliceNs/tablesNs); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"itc/coding"
+	"itc/plot"
+	"itc/report"
+)
+
+// boundsCommands — подкоманды группы bounds
+var boundsCommands = []*command{
+	{name: "table", summary: "границы Хэмминга, Синглтона, Плоткина, Элайеса и Гилберта — Варшамова для n, d, q", run: runBoundsTable},
+	{name: "plot", summary: "границы числа информационных символов в зависимости от d при фиксированной длине", run: runBoundsPlot},
+	{name: "redundancy", summary: "наименьшее число проверочных символов для исправления t ошибок", run: runBoundsRedundancy},
+	{name: "check", summary: "существует ли код (n,k,d) над алфавитом из q символов", run: runBoundsCheck},
+}
+
+// boundKind — вид границы для таблиц
+func boundKind(b coding.Bound) string {
+	if b.Upper {
+		return "верхняя"
+	}
+	return "нижняя"
+}
+
+// boundSize записывает размер границы целиком, если он не длиннее 15
+// цифр, иначе — степенью q
+func boundSize(b coding.Bound, q int) string {
+	if s := b.Size.String(); len(s) <= 15 {
+		return s
+	}
+	return fmt.Sprintf("%d^%.3f", q, b.Log(q))
+}
+
+func runBoundsTable(path string, args []string) error {
+	fs := newFlagSet(path, "Границы наибольшего числа слов A_q(n,d) кода длины n с минимальным расстоянием d над\n"+
+		"алфавитом из q символов. Верхние границы: код большего размера не существует; нижние:\n"+
+		"код такого размера существует. Столбец k — соответствующее число информационных символов.")
+	n := fs.Int("n", 7, "длина кода")
+	d := fs.Int("d", 3, "минимальное расстояние")
+	q := fs.Int("q", 2, "размер алфавита")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	bounds, err := coding.CodeBounds(*n, *d, *q)
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "bounds",
+		Title: fmt.Sprintf("Границы A_%d(%d,%d), исправляется ошибок: %d", *q, *n, *d, (*d-1)/2),
+		Columns: []report.Column{
+			{Key: "bound", Title: "Граница", Width: 24},
+			{Key: "kind", Title: "Вид", Width: 7},
+			{Key: "size", Title: "A_q(n,d)", Width: 16},
+			{Key: "log", Title: "log_q A", Format: "%.3f", Width: 8},
+			{Key: "k", Title: "k", Width: 4},
+			{Key: "rate", Title: "R = k/n", Format: "%.3f"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, b := range bounds {
+		k := b.Dimension(*q)
+		if err := out.WriteRow(b.Name, boundKind(b), boundSize(b, *q), b.Log(*q), k, float64(k)/float64(*n)); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runBoundsPlot(path string, args []string) error {
+	fs := newFlagSet(path, "Границы числа информационных символов k = log_q A_q(n,d) в зависимости от минимального\n"+
+		"расстояния d = 1..n при фиксированных длине кода n и размере алфавита q.")
+	n := fs.Int("n", 31, "длина кода")
+	q := fs.Int("q", 2, "размер алфавита")
+	save := addPlotFlags(fs, "bounds.svg", false, false)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", *n); err != nil {
+		return err
+	}
+	var rows [][]coding.Bound
+	for d := 1; d <= *n; d++ {
+		bounds, err := coding.CodeBounds(*n, d, *q)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, bounds)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	columns := []report.Column{{Key: "d", Title: "d", Width: 4}}
+	series := make([]plot.Series, len(rows[0]))
+	for i, b := range rows[0] {
+		columns = append(columns, report.Column{Key: fmt.Sprintf("b%d", i+1), Title: b.Name, Format: "%.3f"})
+		series[i].Name = b.Name
+	}
+	err = out.BeginTable(&report.Table{
+		Name:    "bounds_plot",
+		Title:   fmt.Sprintf("log_q A_%d(%d,d)", *q, *n),
+		Columns: columns,
+	})
+	if err != nil {
+		return err
+	}
+	for i, bounds := range rows {
+		d := i + 1
+		values := []any{d}
+		for j, b := range bounds {
+			values = append(values, b.Log(*q))
+			series[j].X = append(series[j].X, float64(d))
+			series[j].Y = append(series[j].Y, b.Log(*q))
+		}
+		if err := out.WriteRow(values...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  fmt.Sprintf("Границы для кодов длины %d над алфавитом из %d символов", *n, *q),
+		XLabel: "Минимальное расстояние d",
+		YLabel: "log_q A_q(n,d)",
+		Series: series,
+	})
+}
+
+func runBoundsRedundancy(path string, args []string) error {
+	fs := newFlagSet(path, "Наименьшее число проверочных символов r = n - k кода с k информационными символами,\n"+
+		"исправляющего t ошибок: необходимое по границам Хэмминга и Синглтона и достаточное для\n"+
+		"линейного кода по границе Варшамова. При q = 2 и t = 1 граница Хэмминга совпадает с\n"+
+		"расчётом длины кода в лабораторных работах 4 и 5.")
+	kList := fs.String("k", "4,11,26,57", "числа информационных символов через запятую")
+	tMax := fs.Int("t", 3, "наибольшее число исправляемых ошибок: выводятся t = 1..t")
+	q := fs.Int("q", 2, "размер алфавита")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("t", *tMax); err != nil {
+		return err
+	}
+	var ks []int
+	for _, s := range strings.Split(*kList, ",") {
+		k, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("--k: %q не целое число", s)
+		}
+		ks = append(ks, k)
+	}
+	type row struct {
+		k, t int
+		r    coding.Redundancy
+	}
+	var rows []row
+	for _, k := range ks {
+		for t := 1; t <= *tMax; t++ {
+			r, err := coding.MinRedundancy(k, t, *q)
+			if err != nil {
+				return fmt.Errorf("--k и --q: %w", err)
+			}
+			rows = append(rows, row{k, t, r})
+		}
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "redundancy",
+		Title: fmt.Sprintf("Число проверочных символов, алфавит из %d символов", *q),
+		Columns: []report.Column{
+			{Key: "k", Title: "k", Width: 4},
+			{Key: "t", Title: "t", Width: 3},
+			{Key: "hamming", Title: "r >= (Хэмминг)", Width: 14},
+			{Key: "singleton", Title: "r >= (Синглтон)", Width: 15},
+			{Key: "lower", Title: "r >=", Width: 4},
+			{Key: "varshamov", Title: "r достаточно (Варшамов)", Width: 23},
+			{Key: "n", Title: "n", Width: 9},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		r := row.r
+		n := fmt.Sprintf("%d..%d", row.k+r.Lower(), row.k+r.Varshamov)
+		if err := out.WriteRow(row.k, row.t, r.Hamming, r.Singleton, r.Lower(), r.Varshamov, n); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runBoundsCheck(path string, args []string) error {
+	fs := newFlagSet(path, "Проверка существования кода (n,k,d) из q^k слов: код невозможен, если q^k больше\n"+
+		"какой-либо верхней границы, и заведомо существует, если q^k не больше нижней границы.")
+	n := fs.Int("n", 7, "длина кода")
+	k := fs.Int("k", 4, "число информационных символов")
+	d := fs.Int("d", 3, "минимальное расстояние")
+	q := fs.Int("q", 2, "размер алфавита")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	verdict, by, err := coding.CheckCode(*n, *k, *d, *q)
+	if err != nil {
+		return err
+	}
+	bounds, err := coding.CodeBounds(*n, *d, *q)
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "check",
+		Title: fmt.Sprintf("Код (%d,%d,%d) над алфавитом из %d символов", *n, *k, *d, *q),
+		Columns: []report.Column{
+			{Key: "bound", Title: "Граница", Width: 24},
+			{Key: "kind", Title: "Вид", Width: 7},
+			{Key: "k", Title: "k", Width: 4},
+			{Key: "satisfied", Title: "Условие выполнено"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, b := range bounds {
+		// Для верхней границы условие — k не больше её, для нижней — k не больше гарантированного
+		if err := out.WriteRow(b.Name, boundKind(b), b.Dimension(*q), *k <= b.Dimension(*q)); err != nil {
+			return err
+		}
+	}
+	if by == "" {
+		by = "-"
+	}
+	err = out.EndTable(
+		report.Field{Key: "verdict", Title: "Вывод", Value: verdict.String()},
+		report.Field{Key: "by", Title: "По границе", Value: by},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+
+	"itc/infotheory"
+	"itc/report"
+)
+
+// randomSymmetricChannel строит симметричный канал из n символов, в котором
+// вероятности безошибочной передачи равномерно распределены в [pMin, pMax]
+func randomSymmetricChannel(n int, pMin, pMax float64, rng *rand.Rand) (infotheory.StochasticMatrix, error) {
+	probsRight := make([]float64, n)
+	for i := range probsRight {
+		probsRight[i] = pMin + rng.Float64()*(pMax-pMin)
+	}
+	return infotheory.SymmetricChannel(probsRight)
+}
+
+// addChannelFlags добавляет флаги диапазона вероятностей безошибочной передачи
+func addChannelFlags(fs *flag.FlagSet, pMin, pMax float64) (*float64, *float64) {
+	return fs.Float64("p-min", pMin, "нижняя граница вероятности безошибочной передачи"),
+		fs.Float64("p-max", pMax, "верхняя граница вероятности безошибочной передачи")
+}
+
+// checkRange проверяет диапазон вероятностей безошибочной передачи
+func checkRange(pMin, pMax float64) error {
+	if pMin < 0 || pMax > 1 || pMin > pMax {
+		return fmt.Errorf("--p-min и --p-max: ожидается 0 <= p-min <= p-max <= 1, получено [%g, %g]", pMin, pMax)
+	}
+	return nil
+}
+
+// channelStats — энтропии одного эксперимента с каналом
+type channelStats struct {
+	Entropy, Equivocation, Information float64
+	Output                             infotheory.Distribution
+}
+
+// analyzeChannel вычисляет H(X), H(X|Y) и I(X;Y) для источника input и канала ch
+func analyzeChannel(input infotheory.Distribution, ch infotheory.StochasticMatrix, unit infotheory.Unit) (channelStats, error) {
+	output, err := infotheory.OutputDistribution(input, ch)
+	if err != nil {
+		return channelStats{}, err
+	}
+	equivocation, err := infotheory.Equivocation(input, ch, unit)
+	if err != nil {
+		return channelStats{}, err
+	}
+	information, err := infotheory.MutualInformation(input, ch, unit)
+	if err != nil {
+		return channelStats{}, err
+	}
+	return channelStats{
+		Entropy:      input.Entropy(unit),
+		Equivocation: equivocation,
+		Information:  information,
+		Output:       output,
+	}, nil
+}
+
+func runChannel(path string, args []string) error {
+	fs := newFlagSet(path, "Энтропия источника, ненадёжность H(X|Y) и количество информации I(X;Y) для канала с помехами.")
+	n := fs.Int("n", 53, "число символов алфавита")
+	itr := fs.Int("iterations", 6, "число итераций")
+	matrix := fs.Bool("matrix", false, "вывести матрицу канала первой итерации")
+	pMin, pMax := addChannelFlags(fs, 0.7, 1)
+	source := addSourceFlag(fs)
+	base := addUnitFlag(fs, "энтропий и расхождений")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", *n); err != nil {
+		return err
+	}
+	if err := checkPositive("iterations", *itr); err != nil {
+		return err
+	}
+	if err := checkRange(*pMin, *pMax); err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+	unit := base.unit
+
+	err = out.BeginTable(&report.Table{
+		Name:  "channel",
+		Title: fmt.Sprintf("Канал с помехами: n = %d, вероятность безошибочной передачи в [%g, %g], единица: %s", *n, *pMin, *pMax, unit),
+		Columns: []report.Column{
+			{Key: "iteration", Title: "Итерация", Width: 8},
+			{Key: "entropy", Title: "Энтропия H(X)", Format: "%.4f", Width: 19},
+			{Key: "equivocation", Title: "Ненадёжность H(X|Y)", Format: "%.4f", Width: 29},
+			{Key: "information", Title: "Количество информации I(X;Y)", Format: "%.4f", Width: 34},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	allDivergences := make([][]any, 0, *itr)
+	var first infotheory.StochasticMatrix
+	for i := 0; i < *itr; i++ {
+		probs, err := source.spec.Generate(*n, rng)
+		if err != nil {
+			return fmt.Errorf("итерация %d: %w", i+1, err)
+		}
+		input, err := infotheory.NewDistribution(probs)
+		if err != nil {
+			return fmt.Errorf("итерация %d: %w", i+1, err)
+		}
+		ch, err := randomSymmetricChannel(*n, *pMin, *pMax, rng)
+		if err != nil {
+			return fmt.Errorf("итерация %d: %w", i+1, err)
+		}
+		if i == 0 {
+			first = ch
+		}
+		stats, err := analyzeChannel(input, ch, unit)
+		if err != nil {
+			return fmt.Errorf("итерация %d: %w", i+1, err)
+		}
+		divs, err := divergenceRow(i+1, probs, stats.Output.Probs(), unit)
+		if err != nil {
+			return fmt.Errorf("итерация %d: %w", i+1, err)
+		}
+		allDivergences = append(allDivergences, divs)
+
+		if err := out.WriteRow(i+1, stats.Entropy, stats.Equivocation, stats.Information); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "divergences",
+		Title: fmt.Sprintf("Расхождения между распределениями X и Y (%s)", unit),
+		Columns: []report.Column{
+			{Key: "iteration", Title: "Итерация", Width: 8},
+			{Key: "kl", Title: "D(X||Y)", Format: "%.4f", Width: 10},
+			{Key: "cross_entropy", Title: "CE(X||Y)", Format: "%.4f", Width: 10},
+			{Key: "jensen_shannon", Title: "JS(X,Y)", Format: "%.4f", Width: 10},
+			{Key: "total_variation", Title: "TV", Format: "%.4f", Width: 10},
+			{Key: "hellinger", Title: "Хеллингер", Format: "%.4f", Width: 10},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, row := range allDivergences {
+		if err := out.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if *matrix {
+		if err := writeMatrix(out, "channel_matrix", "Матрица канала P(y|x), итерация 1", first.Matrix(), "%.4f"); err != nil {
+			return err
+		}
+	}
+	return out.Close()
+}
+
+// divergenceRow вычисляет расхождения между распределениями входных и
+// выходных символов канала
+func divergenceRow(iteration int, inputProbs, outputProbs []float64, unit infotheory.Unit) ([]any, error) {
+	kl, err := infotheory.KLDivergence(inputProbs, outputProbs, unit)
+	if err != nil {
+		return nil, err
+	}
+	cross, err := infotheory.CrossEntropy(inputProbs, outputProbs, unit)
+	if err != nil {
+		return nil, err
+	}
+	js, err := infotheory.JensenShannonDivergence(inputProbs, outputProbs, unit)
+	if err != nil {
+		return nil, err
+	}
+	tv, err := infotheory.TotalVariation(inputProbs, outputProbs)
+	if err != nil {
+		return nil, err
+	}
+	hellinger, err := infotheory.HellingerDistance(inputProbs, outputProbs)
+	if err != nil {
+		return nil, err
+	}
+	return []any{iteration, kl, cross, js, tv, hellinger}, nil
+}
+
+// capacityResult — результаты одного эксперимента с каналом и длительностями символов
+type capacityResult struct {
+	channelStats
+	Duration float64 // средняя длительность символа T
+	Capacity float64 // пропускная способность C = max I(X;Y) / T
+	Rate     float64 // скорость передачи R = I(X;Y) / T
+}
+
+// runCapacityTrial выполняет один эксперимент: символы источника имеют
+// случайные длительности из [0, durationMax], канал задаётся вероятностями
+// безошибочной передачи из [pMin, pMax]
+func runCapacityTrial(n int, source infotheory.SourceSpec, pMin, pMax, durationMax float64, rng *rand.Rand) (capacityResult, error) {
+	probs, err := source.Generate(n, rng)
+	if err != nil {
+		return capacityResult{}, err
+	}
+	input, err := infotheory.NewDistribution(probs)
+	if err != nil {
+		return capacityResult{}, err
+	}
+	ch, err := randomSymmetricChannel(n, pMin, pMax, rng)
+	if err != nil {
+		return capacityResult{}, err
+	}
+	stats, err := analyzeChannel(input, ch, infotheory.Bits)
+	if err != nil {
+		return capacityResult{}, err
+	}
+	capacity, err := infotheory.ChannelCapacity(ch, infotheory.Bits, 0)
+	if err != nil {
+		return capacityResult{}, err
+	}
+
+	duration := 0.0
+	for _, p := range probs {
+		duration += p * rng.Float64() * durationMax
+	}
+	if duration <= 0 {
+		return capacityResult{}, infotheory.NewValidationError("средняя длительность", infotheory.ErrOutOfRange,
+			fmt.Sprintf("должна быть больше 0, получено %g", duration))
+	}
+	return capacityResult{
+		channelStats: stats,
+		Duration:     duration,
+		Capacity:     capacity.Value / duration,
+		Rate:         stats.Information / duration,
+	}, nil
+}
+
+// writeCapacityTable выводит таблицу экспериментов и средние значения C и R
+func writeCapacityTable(out report.Writer, name, title string, results []capacityResult) error {
+	err := out.BeginTable(&report.Table{
+		Name:  name,
+		Title: title,
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Эксперимент", Width: 11},
+			{Key: "entropy", Title: "Энтропия H(X)", Format: "%.4f", Width: 13},
+			{Key: "equivocation", Title: "Ненадёжность H(X|Y)", Format: "%.4f", Width: 19},
+			{Key: "duration", Title: "Средняя длительность T (с)", Format: "%.4f", Width: 26},
+			{Key: "capacity", Title: "Пропускная способность C (бит/с)", Format: "%.4f", Width: 32},
+			{Key: "rate", Title: "Скорость передачи R (бит/с)", Format: "%.4f", Width: 27},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var avgCapacity, avgRate float64
+	for i, res := range results {
+		if err := out.WriteRow(i+1, res.Entropy, res.Equivocation, res.Duration, res.Capacity, res.Rate); err != nil {
+			return err
+		}
+		avgCapacity += res.Capacity
+		avgRate += res.Rate
+	}
+	avgCapacity /= float64(len(results))
+	avgRate /= float64(len(results))
+	return out.EndTable(
+		report.Field{Key: "mean_capacity", Title: "Средняя пропускная способность C (бит/с)", Value: avgCapacity, Format: "%.4f"},
+		report.Field{Key: "mean_rate", Title: "Средняя скорость передачи R (бит/с)", Value: avgRate, Format: "%.4f"},
+	)
+}
+
+func runCapacity(path string, args []string) error {
+	fs := newFlagSet(path, "Пропускная способность C (алгоритм Блейхута — Аримото) и скорость передачи R для каналов с помехами и без помех.")
+	n := fs.Int("n", 16, "число символов алфавита")
+	experiments := fs.Int("experiments", 6, "число экспериментов для каждого канала")
+	q := fs.Float64("q", 0, "верхняя граница вероятности безошибочной передачи в канале с помехами (0 — 1 - 1/(2n))")
+	durationMax := fs.Float64("duration-max", 0, "максимальная длительность символа, с (0 — n)")
+	source := addSourceFlag(fs)
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", *n); err != nil {
+		return err
+	}
+	if err := checkPositive("experiments", *experiments); err != nil {
+		return err
+	}
+	if *q == 0 {
+		*q = 1 - 1/float64(2**n)
+	}
+	if err := checkRange(0, *q); err != nil {
+		return err
+	}
+	if *durationMax == 0 {
+		*durationMax = float64(*n)
+	}
+	if *durationMax < 0 {
+		return fmt.Errorf("--duration-max: значение должно быть больше 0, получено %g", *durationMax)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	withNoise := make([]capacityResult, *experiments)
+	noNoise := make([]capacityResult, *experiments)
+	for i := range withNoise {
+		res, err := runCapacityTrial(*n, source.spec, 0, *q, *durationMax, rng)
+		if err != nil {
+			return fmt.Errorf("тест с помехами, эксперимент %d: %w", i+1, err)
+		}
+		withNoise[i] = res
+	}
+	for i := range noNoise {
+		res, err := runCapacityTrial(*n, source.spec, 1, 1, *durationMax, rng)
+		if err != nil {
+			return fmt.Errorf("тест без помех, эксперимент %d: %w", i+1, err)
+		}
+		noNoise[i] = res
+	}
+
+	err = writeCapacityTable(out, "capacity_noise",
+		fmt.Sprintf("Тест с помехами (вероятность безошибочной передачи: [0, %.5f])", *q), withNoise)
+	if err != nil {
+		return err
+	}
+	err = writeCapacityTable(out, "capacity_noiseless",
+		"Тест без помех (вероятность безошибочной передачи: 1.0)", noNoise)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
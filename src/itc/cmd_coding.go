@@ -0,0 +1,343 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"itc/coding"
+	"itc/report"
+)
+
+// codeFamily описывает семейство кодов, для которого строятся подкоманды
+// encode, decode и simulate
+type codeFamily struct {
+	name       string
+	addFlags   func(fs *flag.FlagSet) func() (coding.LinearCode, error)
+	minErrors  int // число ошибок на слово в simulate по умолчанию
+	maxErrors  int
+	experiment int  // число экспериментов по умолчанию
+	streams    bool // encode и decode умеют кодировать файлы (флаги --in и --out)
+}
+
+// hammingFamily — коды Хэмминга: систематический (лабораторная работа 4)
+// и позиционный (лабораторная работа 5)
+var hammingFamily = codeFamily{
+	name: "код Хэмминга",
+	addFlags: func(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+		k := fs.Int("k", 4, "число информационных бит")
+		form := fs.String("form", "systematic", "форма кода: systematic (проверочные биты в конце) или positional (на позициях 2^i)")
+		return func() (coding.LinearCode, error) {
+			switch *form {
+			case "systematic":
+				return coding.NewSystematicHamming(*k)
+			case "positional":
+				return coding.NewPositionalHamming(*k)
+			}
+			return nil, fmt.Errorf("--form: неизвестная форма кода %q (ожидается systematic или positional)", *form)
+		}
+	},
+	minErrors:  1,
+	maxErrors:  1,
+	experiment: 8,
+}
+
+// secdedFamily — расширенный код Хэмминга с общим паритетным битом
+var secdedFamily = codeFamily{
+	name: "код SECDED",
+	addFlags: func(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+		k := fs.Int("k", 4, "число информационных бит")
+		return func() (coding.LinearCode, error) {
+			return coding.NewSECDED(*k)
+		}
+	},
+	minErrors:  0,
+	maxErrors:  2,
+	experiment: 10,
+	streams:    true,
+}
+
+// reedMullerFamily — коды Рида — Маллера RM(r,m)
+var reedMullerFamily = codeFamily{
+	name: "код Рида — Маллера",
+	addFlags: func(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+		r := fs.Int("r", 1, "порядок кода: наибольшая степень мономов")
+		m := fs.Int("m", 4, "число переменных, длина слова 2^m")
+		return func() (coding.LinearCode, error) {
+			return coding.NewReedMuller(*r, *m)
+		}
+	},
+	minErrors:  0,
+	maxErrors:  3,
+	experiment: 10,
+}
+
+// codeCommands строит подкоманды encode, decode и simulate для семейства кодов
+func codeCommands(f codeFamily) []*command {
+	return []*command{
+		{name: "encode", summary: "кодирование информационных бит", run: f.runEncode},
+		{name: "decode", summary: "декодирование принятого слова с исправлением ошибок", run: f.runDecode},
+		{name: "simulate", summary: "эксперименты с внесением случайных ошибок", run: f.runSimulate},
+	}
+}
+
+// statusWidth — ширина столбца с итогом декодирования в текстовой таблице
+var statusWidth = utf8.RuneCountInString(coding.StatusDetected.String())
+
+// writeMatrix выводит матрицу M таблицей со столбцами по номерам (позициям
+// кодового слова или символам выхода канала); format — формат элементов
+func writeMatrix[T int | float64](out report.Writer, name, title string, M [][]T, format string) error {
+	cols := 0
+	if len(M) > 0 {
+		cols = len(M[0])
+	}
+	columns := make([]report.Column, cols)
+	for j := range columns {
+		columns[j] = report.Column{Key: fmt.Sprintf("c%d", j+1), Title: fmt.Sprint(j + 1), Format: format}
+	}
+	if err := out.BeginTable(&report.Table{Name: name, Title: fmt.Sprintf("%s (%d x %d)", title, len(M), cols), Columns: columns}); err != nil {
+		return err
+	}
+	for _, row := range M {
+		values := make([]any, len(row))
+		for j, v := range row {
+			values[j] = v
+		}
+		if err := out.WriteRow(values...); err != nil {
+			return err
+		}
+	}
+	return out.EndTable()
+}
+
+// joinInts перечисляет числа через запятую
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = fmt.Sprint(v)
+	}
+	return strings.Join(strs, ",")
+}
+
+func (f codeFamily) runEncode(path string, args []string) error {
+	fs := newFlagSet(path, "Кодирование информационных бит: "+f.name+".")
+	newCode := f.addFlags(fs)
+	data := fs.String("data", "", "информационные биты, например 1011 (пусто — случайные)")
+	matrices := fs.Bool("matrices", false, "вывести производящую и проверочную матрицы")
+	in, outPath := f.addStreamFlags(fs)
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	if *outPath != "" {
+		return encodeFile(out, code.Dimension(), *in, *outPath)
+	}
+
+	var msg []int
+	if *data == "" {
+		msg = coding.RandomBits(code.Dimension(), newRand())
+	} else if msg, err = coding.ParseBits(*data); err != nil {
+		return fmt.Errorf("--data: %w", err)
+	}
+	word, err := code.Encode(msg)
+	if err != nil {
+		return err
+	}
+
+	if *matrices {
+		if err := writeMatrix(out, "generator_matrix", "Производящая матрица G", code.GeneratorMatrix(), "%d"); err != nil {
+			return err
+		}
+		if err := writeMatrix(out, "parity_check_matrix", "Проверочная матрица H", code.ParityCheckMatrix(), "%d"); err != nil {
+			return err
+		}
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "encode",
+		Title: fmt.Sprintf("%s: n = %d, k = %d", code.Name(), code.Length(), code.Dimension()),
+		Columns: []report.Column{
+			{Key: "data", Title: "Информационные биты"},
+			{Key: "codeword", Title: "Кодовое слово"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := out.WriteRow(coding.BitsToString(msg), coding.BitsToString(word)); err != nil {
+		return err
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func (f codeFamily) runDecode(path string, args []string) error {
+	fs := newFlagSet(path, "Декодирование принятого слова: "+f.name+".")
+	newCode := f.addFlags(fs)
+	wordText := fs.String("word", "", "принятое слово из n бит (обязательный флаг, если не задан --out)")
+	in, outPath := f.addStreamFlags(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *outPath != "" {
+		out, err := newOutput()
+		if err != nil {
+			return err
+		}
+		return decodeFile(out, *in, *outPath)
+	}
+	if *wordText == "" {
+		fs.Usage()
+		return fmt.Errorf("не задан флаг --word")
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	received, err := coding.ParseBits(*wordText)
+	if err != nil {
+		return fmt.Errorf("--word: %w", err)
+	}
+	res, err := code.Decode(received)
+	if err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "decode",
+		Title: fmt.Sprintf("%s: n = %d, k = %d", code.Name(), code.Length(), code.Dimension()),
+		Columns: []report.Column{
+			{Key: "received", Title: "Принятое слово"},
+			{Key: "syndrome", Title: "Синдром"},
+			{Key: "status", Title: "Решение"},
+			{Key: "error_position", Title: "Позиция ошибки"},
+			{Key: "corrected", Title: "Исправленное слово"},
+			{Key: "data", Title: "Информационные биты"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var errorPos any = res.ErrorPos
+	if len(res.ErrorPositions) > 1 {
+		errorPos = joinInts(res.ErrorPositions)
+	}
+	err = out.WriteRow(coding.BitsToString(received), coding.BitsToString(res.Syndrome), res.Status,
+		errorPos, coding.BitsToString(res.Codeword), coding.BitsToString(res.Data))
+	if err != nil {
+		return err
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func (f codeFamily) runSimulate(path string, args []string) error {
+	fs := newFlagSet(path, "Эксперименты: случайные данные кодируются, в слово вносятся ошибки, затем оно декодируется ("+f.name+").")
+	newCode := f.addFlags(fs)
+	experiments := fs.Int("experiments", f.experiment, "число экспериментов")
+	minErrors := fs.Int("min-errors", f.minErrors, "наименьшее число ошибок в слове")
+	maxErrors := fs.Int("max-errors", f.maxErrors, "наибольшее число ошибок в слове")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("experiments", *experiments); err != nil {
+		return err
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	if *minErrors < 0 || *minErrors > *maxErrors || *maxErrors > code.Length() {
+		return fmt.Errorf("--min-errors и --max-errors: ожидается 0 <= min <= max <= %d, получено [%d, %d]",
+			code.Length(), *minErrors, *maxErrors)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "simulate",
+		Title: fmt.Sprintf("%s: n = %d, k = %d, ошибок в слове: %d..%d", code.Name(), code.Length(), code.Dimension(), *minErrors, *maxErrors),
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "data", Title: "Данные", Width: code.Dimension()},
+			{Key: "codeword", Title: "Кодовое слово", Width: code.Length()},
+			{Key: "errors", Title: "Ошибки", Width: 6},
+			{Key: "received", Title: "Принятое слово", Width: code.Length()},
+			{Key: "syndrome", Title: "Синдром", Width: code.Length() - code.Dimension()},
+			{Key: "status", Title: "Решение", Width: statusWidth},
+			{Key: "data_ok", Title: "Данные верны"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[coding.DecodeStatus]int)
+	correct := 0
+	for exp := 1; exp <= *experiments; exp++ {
+		data := coding.RandomBits(code.Dimension(), rng)
+		word, err := code.Encode(data)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", exp, err)
+		}
+		count := *minErrors + rng.Intn(*maxErrors-*minErrors+1)
+		received, positions, err := coding.InjectErrors(word, count, rng)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", exp, err)
+		}
+		res, err := code.Decode(received)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", exp, err)
+		}
+
+		errStr := "-"
+		if len(positions) > 0 {
+			errStr = joinInts(positions)
+		}
+		ok := coding.BitsEqual(data, res.Data)
+		statuses[res.Status]++
+		if ok {
+			correct++
+		}
+		err = out.WriteRow(exp, coding.BitsToString(data), coding.BitsToString(word), errStr,
+			coding.BitsToString(received), coding.BitsToString(res.Syndrome), res.Status, ok)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = out.EndTable(
+		report.Field{Key: "no_errors", Title: "Ошибок нет", Value: statuses[coding.StatusOK]},
+		report.Field{Key: "corrected", Title: "Исправлено", Value: statuses[coding.StatusCorrected]},
+		report.Field{Key: "detected", Title: "Обнаружено без исправления", Value: statuses[coding.StatusDetected]},
+		report.Field{Key: "data_ok", Title: "Данные восстановлены корректно", Value: correct},
+		report.Field{Key: "experiments", Title: "Экспериментов", Value: *experiments},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
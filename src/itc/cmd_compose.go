@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"itc/coding"
+	"itc/plot"
+	"itc/report"
+)
+
+// composeCommands — подкоманды группы compose
+var composeCommands = []*command{
+	{name: "product", summary: "произведение двух линейных кодов с итеративным декодированием строк и столбцов", subcommands: codeCommands(productFamily)},
+	{name: "concat", summary: "конкатенация: внешний код Рида — Соломона, внутренний код Хэмминга или свёрточный", subcommands: codeCommands(concatFamily)},
+	{name: "ber", summary: "BER и FER составных и составляющих кодов в каналах AWGN и ДСК", run: runComposeBER},
+}
+
+// productFamily — произведения кодов, заданных как во флаге --codes
+var productFamily = codeFamily{
+	name: "код-произведение",
+	addFlags: func(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+		row := fs.String("row", "hamming:4", "код строк: "+codeSpecHelp)
+		col := fs.String("col", "hamming:4", "код столбцов: "+codeSpecHelp)
+		iterations := fs.Int("iterations", productIterations, "наибольшее число итераций «строки, затем столбцы»")
+		return func() (coding.LinearCode, error) {
+			r, err := parseCodeSpec(*row)
+			if err != nil {
+				return nil, fmt.Errorf("--row: %w", err)
+			}
+			c, err := parseCodeSpec(*col)
+			if err != nil {
+				return nil, fmt.Errorf("--col: %w", err)
+			}
+			return coding.NewProduct(r, c, *iterations)
+		}
+	},
+	minErrors:  0,
+	maxErrors:  4,
+	experiment: 10,
+}
+
+// concatFamily — конкатенации внешнего кода RS и внутреннего двоичного кода
+var concatFamily = codeFamily{
+	name: "конкатенированный код",
+	addFlags: func(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+		outer := fs.String("outer", "rs:4:15:11", "внешний код Рида — Соломона rs:m:n:k")
+		inner := fs.String("inner", "hamming:4", "внутренний код: hamming:k, secded:k, golay:23 и т. п. или свёрточный conv:g1:g2")
+		return func() (coding.LinearCode, error) { return parseConcatenated(*outer, *inner) }
+	},
+	minErrors:  0,
+	maxErrors:  4,
+	experiment: 10,
+}
+
+func runComposeBER(path string, args []string) error {
+	fs := newFlagSet(path, "Моделирование составных кодов и их составляющих: доля ошибочных бит (BER) и слов (FER)\n"+
+		"после декодирования в гауссовском канале с двоичной фазовой модуляцией (по Eb/N0, мягкие\n"+
+		"решения для кодов с мягким декодером) или в двоичном симметричном канале (по p).")
+	codes := fs.String("codes", "hamming:4,hamming:4*hamming:4,rs:4:15:11+hamming:4,rs:4:15:11+conv:7:5", "коды через запятую: "+codeSpecHelp)
+	channel := fs.String("channel", "awgn", "канал: awgn или bsc")
+	ebn0Min := fs.Float64("ebn0-min", 0, "awgn: наименьшее Eb/N0, дБ")
+	ebn0Max := fs.Float64("ebn0-max", 8, "awgn: наибольшее Eb/N0, дБ")
+	ebn0Step := fs.Float64("ebn0-step", 1, "awgn: шаг по Eb/N0, дБ")
+	pMin := fs.Float64("p-min", 1e-3, "bsc: наименьшая вероятность ошибки")
+	pMax := fs.Float64("p-max", 0.1, "bsc: наибольшая вероятность ошибки")
+	points := fs.Int("points", 8, "bsc: число точек (равномерно по логарифмической шкале)")
+	words := fs.Int("words", 2000, "число слов в каждой точке")
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "compose_ber.svg", false, true)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("words", *words); err != nil {
+		return err
+	}
+	var list []coding.LinearCode
+	nameWidth := 0
+	for _, spec := range strings.Split(*codes, ",") {
+		code, err := parseCodeSpec(spec)
+		if err != nil {
+			return fmt.Errorf("--codes: %w", err)
+		}
+		list = append(list, code)
+		nameWidth = max(nameWidth, utf8.RuneCountInString(code.Name()))
+	}
+
+	var xs []float64
+	var err error
+	var xKey, xTitle, xLabel string
+	var uncoded func(x float64) float64
+	switch *channel {
+	case "awgn":
+		if xs, err = grid(*ebn0Min, *ebn0Max, *ebn0Step); err != nil {
+			return fmt.Errorf("--ebn0-min, --ebn0-max и --ebn0-step: %w", err)
+		}
+		xKey, xTitle, xLabel = "ebn0", "Eb/N0, дБ", "Eb/N0, дБ"
+		uncoded = coding.UncodedAWGNBER
+	case "bsc":
+		if err := checkRange(*pMin, *pMax); err != nil {
+			return fmt.Errorf("--p-min и --p-max: %w", err)
+		}
+		if *pMax >= 0.5 {
+			return fmt.Errorf("--p-max: вероятность ошибки должна быть меньше 0.5, получено %g", *pMax)
+		}
+		if xs, err = logGrid(*pMin, *pMax, *points); err != nil {
+			return fmt.Errorf("--p-min, --p-max и --points: %w", err)
+		}
+		xKey, xTitle, xLabel = "p", "p", "Вероятность ошибки в канале p"
+		uncoded = func(p float64) float64 { return p }
+	default:
+		return fmt.Errorf("--channel: неизвестный канал %q (ожидается awgn или bsc)", *channel)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "compose_ber",
+		Title: fmt.Sprintf("Канал %s, %d слов в каждой точке", strings.ToUpper(*channel), *words),
+		Columns: []report.Column{
+			{Key: "code", Title: "Код", Width: nameWidth},
+			{Key: "rate", Title: "R", Format: "%.3f", Width: 5},
+			{Key: xKey, Title: xTitle, Format: "%.3g", Width: 9},
+			{Key: "ber", Title: "BER", Format: "%.3e", Width: 9},
+			{Key: "fer", Title: "FER", Format: "%.3e", Width: 9},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	reference := plot.Series{Name: "без кодирования", X: xs}
+	for _, x := range xs {
+		reference.Y = append(reference.Y, uncoded(x))
+	}
+	series := []plot.Series{reference}
+	for _, code := range list {
+		name := code.Name()
+		rate := float64(code.Dimension()) / float64(code.Length())
+		s := plot.Series{Name: name}
+		for _, x := range xs {
+			var rates coding.ErrorRates
+			if *channel == "bsc" {
+				rates, err = coding.SimulateBSC(code, x, *words, rng)
+			} else {
+				rates, err = coding.SimulateAWGN(code, x, *words, rng)
+			}
+			if err != nil {
+				return fmt.Errorf("%s, %s = %g: %w", name, xKey, x, err)
+			}
+			if err := out.WriteRow(name, rate, x, rates.BER(), rates.FER()); err != nil {
+				return err
+			}
+			s.X = append(s.X, x)
+			s.Y = append(s.Y, rates.BER())
+		}
+		series = append(series, s)
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  "Помехоустойчивость составных кодов",
+		XLabel: xLabel,
+		YLabel: "BER",
+		Series: series,
+	})
+}
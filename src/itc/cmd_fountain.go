@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+
+	"itc/coding"
+	"itc/plot"
+	"itc/report"
+)
+
+// fountainCommands — подкоманды группы fountain
+var fountainCommands = []*command{
+	{name: "distribution", summary: "идеальное и робастное распределения Солитона, распределение Raptor", run: runFountainDistribution},
+	{name: "overhead", summary: "избыток принятых символов, нужный для восстановления k исходных (LT и Raptor)", run: runFountainOverhead},
+	{name: "transmit", summary: "передача файла фонтанным кодом по каналу со стираниями", run: runFountainTransmit},
+}
+
+// addFountainFlags добавляет флаги параметров LT- и Raptor-кодов; возвращаемая
+// функция строит код с k исходными символами по названию: lt или raptor
+func addFountainFlags(fs *flag.FlagSet) func(name string, k int) (*coding.Fountain, error) {
+	c := fs.Float64("c", 0.05, "lt: параметр c робастного распределения Солитона")
+	delta := fs.Float64("delta", 0.5, "lt: допустимая вероятность неудачи δ робастного распределения Солитона")
+	checks := fs.Int("checks", 0, "raptor: число проверок предкода (0 — как в RFC 5053)")
+	seed := fs.Int64("precode-seed", 1, "raptor: зерно генератора при построении предкода")
+	return func(name string, k int) (*coding.Fountain, error) {
+		switch name {
+		case "lt":
+			dist, _, err := coding.RobustSoliton(k, *c, *delta)
+			if err != nil {
+				return nil, fmt.Errorf("--c и --delta: %w", err)
+			}
+			return coding.NewLT(k, dist)
+		case "raptor":
+			s := *checks
+			if s == 0 {
+				s = coding.RaptorChecks(k)
+			}
+			return coding.NewRaptor(k, s, coding.RaptorDistribution(), rand.New(rand.NewSource(*seed)))
+		}
+		return nil, fmt.Errorf("неизвестный фонтанный код %q (ожидается lt или raptor)", name)
+	}
+}
+
+// decoderName возвращает название декодера фонтанного кода
+func decoderName(elimination bool) string {
+	if elimination {
+		return "очистка + Гаусс"
+	}
+	return "очистка"
+}
+
+// parseFountainDecoders разбирает список декодеров: peeling и gauss
+func parseFountainDecoders(spec string) ([]bool, error) {
+	var res []bool
+	for _, s := range strings.Split(spec, ",") {
+		switch strings.TrimSpace(s) {
+		case "peeling":
+			res = append(res, false)
+		case "gauss":
+			res = append(res, true)
+		default:
+			return nil, fmt.Errorf("неизвестный декодер %q (ожидается peeling или gauss)", s)
+		}
+	}
+	return res, nil
+}
+
+func runFountainDistribution(path string, args []string) error {
+	fs := newFlagSet(path, "Распределения степеней выходных символов LT-кода: идеальное распределение Солитона,\n"+
+		"робастное распределение Солитона (Luby) с добавкой τ и пиком в степени k/R, а также\n"+
+		"распределение Ω кода Raptor из RFC 5053.")
+	k := fs.Int("k", 1000, "число исходных символов")
+	c := fs.Float64("c", 0.05, "параметр c робастного распределения")
+	delta := fs.Float64("delta", 0.5, "допустимая вероятность неудачи δ")
+	maxDegree := fs.Int("max-degree", 40, "наибольшая степень в таблице (пик выводится всегда)")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("max-degree", *maxDegree); err != nil {
+		return err
+	}
+	ideal, err := coding.IdealSoliton(*k)
+	if err != nil {
+		return err
+	}
+	robust, params, err := coding.RobustSoliton(*k, *c, *delta)
+	if err != nil {
+		return err
+	}
+	raptor := coding.RaptorDistribution()
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "fountain_distribution",
+		Title: fmt.Sprintf("Распределения степеней, k = %d, c = %g, δ = %g", *k, *c, *delta),
+		Columns: []report.Column{
+			{Key: "degree", Title: "d", Width: 5},
+			{Key: "ideal", Title: "ρ(d)", Format: "%.5f", Width: 8},
+			{Key: "robust", Title: "μ(d)", Format: "%.5f", Width: 8},
+			{Key: "raptor", Title: "Ω(d)", Format: "%.5f"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	at := func(p coding.DegreeDistribution, d int) float64 {
+		if d < len(p) {
+			return p[d]
+		}
+		return 0
+	}
+	for d := 1; d <= *k; d++ {
+		if d > *maxDegree && d != params.Spike {
+			continue
+		}
+		if err := out.WriteRow(d, ideal[d], robust[d], at(raptor, d)); err != nil {
+			return err
+		}
+	}
+	err = out.EndTable(
+		report.Field{Key: "R", Title: "Ожидаемое число символов степени 1 R = c ln(k/δ) √k", Value: params.R},
+		report.Field{Key: "spike", Title: "Пик робастного распределения k/R", Value: params.Spike},
+		report.Field{Key: "beta", Title: "Нормирующий множитель β", Value: params.Beta},
+		report.Field{Key: "symbols", Title: "Оценка числа символов для декодирования kβ", Value: float64(*k) * params.Beta},
+		report.Field{Key: "mean_ideal", Title: "Средняя степень ρ", Value: ideal.Mean()},
+		report.Field{Key: "mean_robust", Title: "Средняя степень μ", Value: robust.Mean()},
+		report.Field{Key: "mean_raptor", Title: "Средняя степень Ω", Value: raptor.Mean()},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runFountainOverhead(path string, args []string) error {
+	fs := newFlagSet(path, "Моделирование фонтанных кодов в канале со стираниями: сколько символов сверх k нужно\n"+
+		"принять, чтобы восстановить k исходных. LT-код использует робастное распределение\n"+
+		"Солитона, код Raptor — предкод и распределение Ω из RFC 5053. Декодер peeling — только\n"+
+		"очистка, gauss — очистка с решением оставшейся системы методом Гаусса при остановке.")
+	k := fs.Int("k", 1000, "число исходных символов")
+	newCode := addFountainFlags(fs)
+	codes := fs.String("codes", "lt,raptor", "коды через запятую: lt, raptor")
+	decoders := fs.String("decoders", "peeling,gauss", "декодеры через запятую: peeling, gauss")
+	erasure := fs.Float64("erasure", 0.2, "вероятность стирания символа в канале")
+	trials := fs.Int("trials", 100, "число испытаний для каждого кода и декодера")
+	maxOverhead := fs.Float64("max-overhead", 0.5, "наибольший избыток в таблице вероятности неудачи")
+	step := fs.Float64("step", 0.02, "шаг по избытку в таблице вероятности неудачи")
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "fountain_overhead.svg", false, true)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("k", *k); err != nil {
+		return err
+	}
+	if err := checkPositive("trials", *trials); err != nil {
+		return err
+	}
+	if *erasure < 0 || *erasure >= 1 {
+		return fmt.Errorf("--erasure: ожидается значение из интервала [0, 1), получено %g", *erasure)
+	}
+	overheads, err := grid(0, *maxOverhead, *step)
+	if err != nil {
+		return fmt.Errorf("--max-overhead и --step: %w", err)
+	}
+	elims, err := parseFountainDecoders(*decoders)
+	if err != nil {
+		return fmt.Errorf("--decoders: %w", err)
+	}
+	var fountains []*coding.Fountain
+	for _, name := range strings.Split(*codes, ",") {
+		f, err := newCode(strings.TrimSpace(name), *k)
+		if err != nil {
+			return fmt.Errorf("--codes: %w", err)
+		}
+		fountains = append(fountains, f)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "fountain_overhead",
+		Title: fmt.Sprintf("Избыток принятых символов (N - k)/k, канал со стираниями ε = %g, %d испытаний", *erasure, *trials),
+		Columns: []report.Column{
+			{Key: "code", Title: "Код", Width: 38},
+			{Key: "decoder", Title: "Декодер", Width: 15},
+			{Key: "mean", Title: "Среднее", Format: "%.4f", Width: 7},
+			{Key: "stddev", Title: "СКО", Format: "%.4f", Width: 6},
+			{Key: "median", Title: "Медиана", Format: "%.4f", Width: 7},
+			{Key: "p90", Title: "90%", Format: "%.4f", Width: 6},
+			{Key: "p99", Title: "99%", Format: "%.4f", Width: 6},
+			{Key: "max", Title: "Макс.", Format: "%.4f", Width: 6},
+			{Key: "failures", Title: "Неудач", Width: 6},
+			{Key: "sent", Title: "Передано на символ", Format: "%.4f"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var series []plot.Series
+	var results [][]coding.FountainTrial
+	for _, f := range fountains {
+		for _, elimination := range elims {
+			res, err := coding.SimulateFountain(f, *erasure, *trials, elimination, rng)
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.Name(), err)
+			}
+			s := coding.NewOverheadStats(res, f.SourceSymbols())
+			err = out.WriteRow(f.Name(), decoderName(elimination), s.Mean, s.StdDev, s.Median, s.P90, s.P99, s.Max,
+				s.Failures, s.SentPerSource)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+			name := strings.SplitN(f.Name(), ",", 2)[0]
+			series = append(series, plot.Series{Name: name + ", " + decoderName(elimination)})
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+
+	columns := []report.Column{{Key: "overhead", Title: "Избыток", Format: "%.3f", Width: 7}}
+	for i, s := range series {
+		columns = append(columns, report.Column{Key: fmt.Sprintf("fail%d", i+1), Title: s.Name, Format: "%.3f"})
+	}
+	err = out.BeginTable(&report.Table{
+		Name:    "fountain_failure",
+		Title:   "Вероятность того, что после приёма k(1 + избыток) символов декодирование не завершено",
+		Columns: columns,
+	})
+	if err != nil {
+		return err
+	}
+	for _, eps := range overheads {
+		row := []any{eps}
+		for i, res := range results {
+			p := coding.FailureProbability(res, fountains[i/len(elims)].SourceSymbols(), eps)
+			row = append(row, p)
+			series[i].X = append(series[i].X, eps)
+			series[i].Y = append(series[i].Y, p)
+		}
+		if err := out.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  fmt.Sprintf("Вероятность неудачи декодирования, k = %d", fountains[0].SourceSymbols()),
+		XLabel: "Избыток принятых символов (N - k)/k",
+		YLabel: "Вероятность неудачи",
+		Series: series,
+	})
+}
+
+func runFountainTransmit(path string, args []string) error {
+	fs := newFlagSet(path, "Передача файла фонтанным кодом: файл делится на k символов по --symbol-size байт,\n"+
+		"передатчик порождает выходные символы, канал стирает их с вероятностью --erasure,\n"+
+		"приёмник принимает символы, пока не восстановит все исходные, и записывает файл.")
+	code := fs.String("code", "raptor", "код: lt или raptor")
+	newCode := addFountainFlags(fs)
+	inPath := fs.String("in", "-", "входной файл (\"-\" — стандартный ввод)")
+	outPath := fs.String("out", "", "файл для восстановленных данных (пусто — только отчёт)")
+	size := fs.Int("symbol-size", 64, "длина символа, байт")
+	erasure := fs.Float64("erasure", 0.2, "вероятность стирания символа в канале")
+	gauss := fs.Bool("gauss", true, "решать оставшуюся после очистки систему методом Гаусса")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("symbol-size", *size); err != nil {
+		return err
+	}
+	if *erasure < 0 || *erasure >= 1 {
+		return fmt.Errorf("--erasure: ожидается значение из интервала [0, 1), получено %g", *erasure)
+	}
+	var in io.Reader = os.Stdin
+	if *inPath != "-" {
+		f, err := os.Open(*inPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	if len(content) == 0 {
+		return fmt.Errorf("--in: файл пуст")
+	}
+	k := (len(content) + *size - 1) / *size
+	f, err := newCode(*code, k)
+	if err != nil {
+		return fmt.Errorf("--code: %w", err)
+	}
+	padded := make([]byte, k**size)
+	copy(padded, content)
+	source := make([][]byte, k)
+	for i := range source {
+		source[i] = padded[i**size : (i+1)**size]
+	}
+	rng := newRand()
+	seed := rng.Int63()
+	enc, err := f.NewEncoder(source, seed)
+	if err != nil {
+		return err
+	}
+	dec := f.NewDecoder(seed, *size)
+	dec.Elimination = *gauss
+	sent, erased := 0, 0
+	for !dec.Done() {
+		if dec.Received() >= 10*k {
+			return fmt.Errorf("исходные символы не восстановлены после приёма %d символов", dec.Received())
+		}
+		sym := enc.Symbol(sent)
+		sent++
+		if rng.Float64() < *erasure {
+			erased++
+			continue
+		}
+		if _, err := dec.Add(sym); err != nil {
+			return err
+		}
+	}
+	recovered := bytes.Join(dec.Source(), nil)[:len(content)]
+	if *outPath != "" {
+		if err := os.WriteFile(*outPath, recovered, 0o644); err != nil {
+			return err
+		}
+	}
+
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "fountain_transmit",
+		Title: fmt.Sprintf("Передача %s: %s, символ %d байт, %s", *inPath, f.Name(), *size, decoderName(*gauss)),
+		Columns: []report.Column{
+			{Key: "property", Title: "Параметр", Width: 36},
+			{Key: "value", Title: "Значение"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	trial := coding.FountainTrial{Sent: sent, Received: dec.Received(), Decoded: true}
+	rows := [][]any{
+		{"байт в файле", len(content)},
+		{"исходных символов k", k},
+		{"передано символов", sent},
+		{"стёрто в канале", erased},
+		{"принято до восстановления N", dec.Received()},
+		{"избыток (N - k)/k", fmt.Sprintf("%.4f", trial.Overhead(k))},
+		{"передано на исходный символ", fmt.Sprintf("%.4f", float64(sent)/float64(k))},
+		{"данные совпадают", bytes.Equal(recovered, content)},
+	}
+	for _, row := range rows {
+		if err := out.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if *outPath != "" {
+		fmt.Fprintf(os.Stderr, "Восстановленные данные сохранены в %s\n", *outPath)
+	}
+	return out.Close()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"itc/coding"
+	"itc/report"
+)
+
+// golayFamily — коды Голея (23,12) и (24,12), исправляющие три ошибки
+var golayFamily = codeFamily{
+	name:       "код Голея",
+	addFlags:   addGolayFlags,
+	minErrors:  0,
+	maxErrors:  4,
+	experiment: 10,
+}
+
+// addGolayFlags добавляет флаг выбора кода Голея
+func addGolayFlags(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+	extended := fs.Bool("extended", false, "расширенный код (24,12,8) вместо совершенного (23,12,7)")
+	return func() (coding.LinearCode, error) {
+		if *extended {
+			return coding.NewExtendedGolay(), nil
+		}
+		return coding.NewGolay(), nil
+	}
+}
+
+// golayCommands — подкоманды группы golay: общие для кодов и проверка совершенности
+func golayCommands() []*command {
+	return append(codeCommands(golayFamily),
+		&command{name: "verify", summary: "перебор всех векторов ошибок веса до 3: синдромы, совершенность и декодирование", run: runGolayVerify})
+}
+
+func runGolayVerify(path string, args []string) error {
+	fs := newFlagSet(path, "Полный перебор векторов ошибок веса не больше t: синдромы должны быть различны, а для\n"+
+		"совершенного кода их число равно 2^(n-k). Каждый вектор ошибок подаётся на декодер, минимальное\n"+
+		"расстояние считается перебором всех кодовых слов.")
+	newCode := addGolayFlags(fs)
+	t := fs.Int("t", 3, "наибольший вес вектора ошибок")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	word, err := code.Encode(coding.RandomBits(code.Dimension(), newRand()))
+	if err != nil {
+		return err
+	}
+	rep, err := coding.VerifyPerfect(code, *t, word)
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "golay_verify",
+		Title: fmt.Sprintf("%s: n = %d, k = %d, векторы ошибок веса 0..%d", rep.Code, rep.N, rep.K, rep.T),
+		Columns: []report.Column{
+			{Key: "check", Title: "Проверка", Width: 36},
+			{Key: "value", Title: "Значение"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	rows := [][]any{
+		{"минимальное расстояние d", rep.MinDistance},
+		{"векторов ошибок веса <= t", rep.Patterns},
+		{"различных синдромов", rep.Syndromes},
+		{"всего синдромов 2^(n-k)", rep.Cosets},
+		{"исправлено декодером", rep.Decoded},
+	}
+	for _, row := range rows {
+		if err := out.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(report.Field{Key: "perfect", Title: "Код совершенен", Value: rep.Perfect}); err != nil {
+		return err
+	}
+	return out.Close()
+}
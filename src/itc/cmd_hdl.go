@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"itc/coding"
+)
+
+func runHDL(path string, args []string) error {
+	fs := newFlagSet(path, "Модули кодера и синдромного декодера на Verilog или VHDL: деревья XOR проверочных\n"+
+		"уравнений и сравнение синдрома со столбцами H. Вместе с модулями записывается\n"+
+		"самопроверяющийся тестовый модуль с векторами, полученными кодером и декодером на Go.")
+	codeSpec := fs.String("code", "secded:64", "код: hamming:k (систематический), positional:k или secded:k")
+	lang := fs.String("lang", "verilog", "язык: verilog или vhdl")
+	name := fs.String("name", "", "имя модулей (пусто — по коду, например secded_64)")
+	outDir := fs.String("out-dir", "hdl", "каталог для файлов <имя>.v и <имя>_tb.v (.vhd для VHDL)")
+	vectors := fs.Int("vectors", 32, "число тестовых векторов")
+	maxErrors := fs.Int("max-errors", 2, "наибольшее число ошибок в принятых словах тестовых векторов")
+	newRand := addSeedFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("vectors", *vectors); err != nil {
+		return err
+	}
+	code, err := parseCodeSpec(*codeSpec)
+	if err != nil {
+		return fmt.Errorf("--code: %w", err)
+	}
+	l, err := coding.ParseHDLLanguage(*lang)
+	if err != nil {
+		return fmt.Errorf("--lang: %w", err)
+	}
+	module := *name
+	if module == "" {
+		module = strings.ReplaceAll(strings.TrimSpace(*codeSpec), ":", "_")
+	}
+	tests, err := coding.NewHDLVectors(code, *vectors, *maxErrors, newRand())
+	if err != nil {
+		return fmt.Errorf("--max-errors: %w", err)
+	}
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		return err
+	}
+
+	files := []struct {
+		path  string
+		write func(f *os.File) error
+	}{
+		{filepath.Join(*outDir, module+"."+l.Ext()), func(f *os.File) error {
+			return coding.WriteHDL(f, code, l, module)
+		}},
+		{filepath.Join(*outDir, module+"_tb."+l.Ext()), func(f *os.File) error {
+			return coding.WriteHDLTestbench(f, code, l, module, tests)
+		}},
+	}
+	for _, file := range files {
+		f, err := os.Create(file.path)
+		if err != nil {
+			return err
+		}
+		if err := file.write(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%s: модули %s_encoder и %s_decoder сохранены в %s, тестовый модуль %s_tb (%d векторов) — в %s\n",
+		code.Name(), module, module, files[0].path, module, len(tests), files[1].path)
+	return nil
+}
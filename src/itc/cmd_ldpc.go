@@ -0,0 +1,321 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+
+	"itc/coding"
+	"itc/plot"
+	"itc/report"
+)
+
+// ldpcFamily — LDPC-коды с декодированием распространением доверия
+var ldpcFamily = codeFamily{
+	name: "LDPC-код",
+	addFlags: func(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+		newGraph := addLDPCGraphFlags(fs)
+		setDecoder := addLDPCDecoderFlags(fs, true)
+		return func() (coding.LinearCode, error) {
+			code, err := newGraph()
+			if err != nil {
+				return nil, err
+			}
+			if err := setDecoder(code); err != nil {
+				return nil, err
+			}
+			return code, nil
+		}
+	},
+	minErrors:  0,
+	maxErrors:  4,
+	experiment: 10,
+}
+
+// ldpcCommands — подкоманды группы ldpc
+func ldpcCommands() []*command {
+	cmds := []*command{{name: "generate", summary: "построение проверочной матрицы (Галлагер или PEG) и запись в формате alist", run: runLDPCGenerate}}
+	cmds = append(cmds, codeCommands(ldpcFamily)...)
+	return append(cmds, &command{name: "ber", summary: "BER и FER декодеров sum-product и min-sum в каналах ДСК и AWGN", run: runLDPCBER})
+}
+
+// addLDPCGraphFlags добавляет флаги построения проверочной матрицы
+func addLDPCGraphFlags(fs *flag.FlagSet) func() (*coding.LDPC, error) {
+	alist := fs.String("alist", "", "файл проверочной матрицы в формате alist (если задан, остальные флаги построения не используются)")
+	method := fs.String("method", "gallager", "построение: gallager (регулярный код) или peg")
+	n := fs.Int("n", 96, "длина кода")
+	wc := fs.Int("wc", 3, "gallager: число единиц в столбце")
+	wr := fs.Int("wr", 6, "gallager: число единиц в строке")
+	m := fs.Int("m", 0, "peg: число проверок (0 — n/2)")
+	degrees := fs.String("degrees", "3", "peg: степень переменных или распределение степень:доля, например 2:0.5,3:0.3,8:0.2")
+	seed := fs.Int64("graph-seed", 1, "зерно генератора при построении матрицы")
+	return func() (*coding.LDPC, error) {
+		if *alist != "" {
+			f, err := os.Open(*alist)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			code, err := coding.ReadAlist(f)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", *alist, err)
+			}
+			return code, nil
+		}
+		rng := rand.New(rand.NewSource(*seed))
+		switch *method {
+		case "gallager":
+			return coding.NewGallagerLDPC(*n, *wc, *wr, rng)
+		case "peg":
+			if err := checkPositive("n", *n); err != nil {
+				return nil, err
+			}
+			checks := *m
+			if checks == 0 {
+				checks = *n / 2
+			}
+			d, err := coding.ParseVariableDegrees(*degrees, *n)
+			if err != nil {
+				return nil, fmt.Errorf("--degrees: %w", err)
+			}
+			return coding.NewPEGLDPC(checks, d, rng)
+		}
+		return nil, fmt.Errorf("--method: неизвестное построение %q (ожидается gallager или peg)", *method)
+	}
+}
+
+// addLDPCDecoderFlags добавляет флаги параметров декодера; флаг --algorithm —
+// только если withAlgorithm
+func addLDPCDecoderFlags(fs *flag.FlagSet, withAlgorithm bool) func(code *coding.LDPC) error {
+	def := coding.DefaultLDPCDecoder()
+	var algorithm *string
+	if withAlgorithm {
+		algorithm = fs.String("algorithm", def.Algorithm.String(), "алгоритм декодирования: sum-product или min-sum")
+	}
+	iterations := fs.Int("iterations", def.MaxIterations, "наибольшее число итераций")
+	scale := fs.Float64("scale", def.Scale, "нормирующий множитель min-sum из (0, 1]")
+	crossover := fs.Float64("crossover", def.Crossover, "вероятность ошибки ДСК для перевода принятых бит в LLR")
+	return func(code *coding.LDPC) error {
+		d := coding.LDPCDecoder{MaxIterations: *iterations, Scale: *scale, Crossover: *crossover}
+		if algorithm != nil {
+			var err error
+			if d.Algorithm, err = coding.ParseLDPCAlgorithm(*algorithm); err != nil {
+				return fmt.Errorf("--algorithm: %w", err)
+			}
+		}
+		return code.SetDecoder(d)
+	}
+}
+
+func runLDPCGenerate(path string, args []string) error {
+	fs := newFlagSet(path, "Построение проверочной матрицы LDPC-кода: регулярный код Галлагера (полосы из\n"+
+		"перестановок столбцов) или PEG с регулярными либо нерегулярными степенями переменных.\n"+
+		"Матрица записывается в формате alist, сводка — в выбранном формате вывода.")
+	newGraph := addLDPCGraphFlags(fs)
+	outPath := fs.String("out", "", "файл alist (пусто — только сводка)")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	code, err := newGraph()
+	if err != nil {
+		return err
+	}
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		if err := code.WriteAlist(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "ldpc",
+		Title: code.Name(),
+		Columns: []report.Column{
+			{Key: "property", Title: "Параметр", Width: 32},
+			{Key: "value", Title: "Значение"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	colMin, colMax := degreeRange(code.Length(), func(j int) int { return len(code.VariableChecks(j)) })
+	rowMin, rowMax := degreeRange(code.Checks(), func(i int) int { return len(code.CheckVariables(i)) })
+	rows := [][]any{
+		{"длина n", code.Length()},
+		{"проверок m", code.Checks()},
+		{"информационных бит k", code.Dimension()},
+		{"скорость k/n", fmt.Sprintf("%.4f", float64(code.Dimension())/float64(code.Length()))},
+		{"единиц в H", code.Edges()},
+		{"степени столбцов", fmt.Sprintf("%d..%d", colMin, colMax)},
+		{"степени строк", fmt.Sprintf("%d..%d", rowMin, rowMax)},
+		{"обхват графа Таннера", code.Girth()},
+	}
+	for _, row := range rows {
+		if err := out.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if *outPath != "" {
+		fmt.Fprintf(os.Stderr, "Матрица сохранена в %s\n", *outPath)
+	}
+	return out.Close()
+}
+
+// degreeRange возвращает наименьшую и наибольшую из count степеней
+func degreeRange(count int, degree func(i int) int) (int, int) {
+	lo, hi := degree(0), degree(0)
+	for i := 1; i < count; i++ {
+		d := degree(i)
+		lo, hi = min(lo, d), max(hi, d)
+	}
+	return lo, hi
+}
+
+func runLDPCBER(path string, args []string) error {
+	fs := newFlagSet(path, "Моделирование LDPC-кода: доля ошибочных бит (BER) и слов (FER) после декодирования\n"+
+		"алгоритмами sum-product и min-sum в двоичном симметричном канале (по вероятности ошибки p)\n"+
+		"или в гауссовском канале с двоичной фазовой модуляцией (по Eb/N0).")
+	newGraph := addLDPCGraphFlags(fs)
+	setDecoder := addLDPCDecoderFlags(fs, false)
+	algorithms := fs.String("algorithms", "sum-product,min-sum", "алгоритмы декодирования через запятую")
+	channel := fs.String("channel", "awgn", "канал: awgn или bsc")
+	ebn0Min := fs.Float64("ebn0-min", 0, "awgn: наименьшее Eb/N0, дБ")
+	ebn0Max := fs.Float64("ebn0-max", 3, "awgn: наибольшее Eb/N0, дБ")
+	ebn0Step := fs.Float64("ebn0-step", 0.5, "awgn: шаг по Eb/N0, дБ")
+	pMin := fs.Float64("p-min", 0.01, "bsc: наименьшая вероятность ошибки")
+	pMax := fs.Float64("p-max", 0.1, "bsc: наибольшая вероятность ошибки")
+	points := fs.Int("points", 6, "bsc: число точек (равномерно по логарифмической шкале)")
+	words := fs.Int("words", 1000, "число слов в каждой точке")
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "ldpc_ber.svg", false, true)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("words", *words); err != nil {
+		return err
+	}
+	code, err := newGraph()
+	if err != nil {
+		return err
+	}
+	if err := setDecoder(code); err != nil {
+		return err
+	}
+	var algs []coding.LDPCAlgorithm
+	for _, s := range strings.Split(*algorithms, ",") {
+		a, err := coding.ParseLDPCAlgorithm(s)
+		if err != nil {
+			return fmt.Errorf("--algorithms: %w", err)
+		}
+		algs = append(algs, a)
+	}
+
+	var xs []float64
+	var xKey, xTitle, xLabel string
+	var uncoded func(x float64) float64
+	switch *channel {
+	case "awgn":
+		if xs, err = grid(*ebn0Min, *ebn0Max, *ebn0Step); err != nil {
+			return fmt.Errorf("--ebn0-min, --ebn0-max и --ebn0-step: %w", err)
+		}
+		xKey, xTitle, xLabel = "ebn0", "Eb/N0, дБ", "Eb/N0, дБ"
+		uncoded = coding.UncodedAWGNBER
+	case "bsc":
+		if err := checkRange(*pMin, *pMax); err != nil {
+			return fmt.Errorf("--p-min и --p-max: %w", err)
+		}
+		if *pMax >= 0.5 {
+			return fmt.Errorf("--p-max: вероятность ошибки должна быть меньше 0.5, получено %g", *pMax)
+		}
+		if xs, err = logGrid(*pMin, *pMax, *points); err != nil {
+			return fmt.Errorf("--p-min, --p-max и --points: %w", err)
+		}
+		xKey, xTitle, xLabel = "p", "p", "Вероятность ошибки в канале p"
+		uncoded = func(p float64) float64 { return p }
+	default:
+		return fmt.Errorf("--channel: неизвестный канал %q (ожидается awgn или bsc)", *channel)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "ldpc_ber",
+		Title: fmt.Sprintf("%s, канал %s, %d слов в каждой точке", code.Name(), strings.ToUpper(*channel), *words),
+		Columns: []report.Column{
+			{Key: "algorithm", Title: "Алгоритм", Width: 11},
+			{Key: xKey, Title: xTitle, Format: "%.3g", Width: 9},
+			{Key: "ber", Title: "BER", Format: "%.3e", Width: 9},
+			{Key: "fer", Title: "FER", Format: "%.3e", Width: 9},
+			{Key: "iterations", Title: "Итераций", Format: "%.2f"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	reference := plot.Series{Name: "без кодирования", X: xs}
+	for _, x := range xs {
+		reference.Y = append(reference.Y, uncoded(x))
+	}
+	series := []plot.Series{reference}
+	for _, a := range algs {
+		d := code.Decoder()
+		d.Algorithm = a
+		s := plot.Series{Name: a.String()}
+		for _, x := range xs {
+			var rates coding.ErrorRates
+			if *channel == "bsc" {
+				d.Crossover = x
+				if err := code.SetDecoder(d); err != nil {
+					return err
+				}
+				rates, err = coding.SimulateBSC(code, x, *words, rng)
+			} else {
+				if err := code.SetDecoder(d); err != nil {
+					return err
+				}
+				rates, err = coding.SimulateAWGN(code, x, *words, rng)
+			}
+			if err != nil {
+				return fmt.Errorf("%s, %s = %g: %w", a, xKey, x, err)
+			}
+			if err := out.WriteRow(a.String(), x, rates.BER(), rates.FER(), rates.AvgIterations()); err != nil {
+				return err
+			}
+			s.X = append(s.X, x)
+			s.Y = append(s.Y, rates.BER())
+		}
+		series = append(series, s)
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  "Помехоустойчивость " + code.Name(),
+		XLabel: xLabel,
+		YLabel: "BER",
+		Series: series,
+	})
+}
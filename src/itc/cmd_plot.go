@@ -0,0 +1,539 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"itc/coding"
+	"itc/infotheory"
+	"itc/plot"
+	"itc/report"
+)
+
+// plotCommands — подкоманды itc plot
+var plotCommands = []*command{
+	{name: "entropy", summary: "энтропия источника в зависимости от размера алфавита (лабораторная работа 1)", run: runPlotEntropy},
+	{name: "information", summary: "I(X;Y) в зависимости от вероятности безошибочной передачи (лабораторная работа 2)", run: runPlotInformation},
+	{name: "capacity", summary: "C и R в зависимости от уровня помех (лабораторная работа 3)", run: runPlotCapacity},
+	{name: "ber", summary: "BER и FER кодов Хэмминга в двоичном симметричном канале (лабораторные работы 4 и 5)", run: runPlotBER},
+}
+
+// addPlotFlags добавляет флаги файла графика; функция-результат сохраняет
+// график в файл, формат которого определяется расширением
+func addPlotFlags(fs *flag.FlagSet, out string, logX, logY bool) func(c *plot.Chart) error {
+	path := fs.String("out", out, "файл графика: .svg или .png")
+	width := fs.Int("width", plot.DefaultWidth, "ширина изображения, пикселей")
+	height := fs.Int("height", plot.DefaultHeight, "высота изображения, пикселей")
+	lx := fs.Bool("log-x", logX, "логарифмическая шкала по оси абсцисс")
+	ly := fs.Bool("log-y", logY, "логарифмическая шкала по оси ординат")
+	return func(c *plot.Chart) error {
+		format, err := plot.FormatFromPath(*path)
+		if err != nil {
+			return fmt.Errorf("--out: %w", err)
+		}
+		c.LogX, c.LogY = *lx, *ly
+		f, err := os.Create(*path)
+		if err != nil {
+			return err
+		}
+		if err := c.Write(f, format, *width, *height); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "График сохранён в %s\n", *path)
+		return nil
+	}
+}
+
+// grid возвращает точки from, from+step, ..., не превосходящие to
+func grid(from, to, step float64) ([]float64, error) {
+	if step <= 0 || from > to {
+		return nil, fmt.Errorf("ожидается from <= to и step > 0, получено [%g, %g] с шагом %g", from, to, step)
+	}
+	var points []float64
+	for i := 0; ; i++ {
+		v := from + float64(i)*step
+		if v > to+step*1e-9 {
+			break
+		}
+		points = append(points, math.Min(v, to))
+	}
+	return points, nil
+}
+
+// logGrid возвращает count точек, равномерно распределённых по логарифмической
+// шкале на отрезке [from, to]
+func logGrid(from, to float64, count int) ([]float64, error) {
+	if from <= 0 || from > to || count <= 0 {
+		return nil, fmt.Errorf("ожидается 0 < from <= to и положительное число точек, получено [%g, %g], %d точек", from, to, count)
+	}
+	if count == 1 {
+		return []float64{from}, nil
+	}
+	points := make([]float64, count)
+	for i := range points {
+		points[i] = from * math.Pow(to/from, float64(i)/float64(count-1))
+	}
+	return points, nil
+}
+
+func runPlotEntropy(path string, args []string) error {
+	fs := newFlagSet(path, "График средней энтропии источника и её максимума log2(n) в зависимости от размера алфавита n.")
+	nMin := fs.Int("n-min", 2, "наименьший размер алфавита")
+	nMax := fs.Int("n-max", 64, "наибольший размер алфавита")
+	trials := fs.Int("trials", 100, "число источников для усреднения при каждом n")
+	source := addSourceFlag(fs)
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "entropy.svg", false, false)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("trials", *trials); err != nil {
+		return err
+	}
+	if *nMin < 1 || *nMin > *nMax {
+		return fmt.Errorf("--n-min и --n-max: ожидается 1 <= n-min <= n-max, получено [%d, %d]", *nMin, *nMax)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "plot_entropy",
+		Title: fmt.Sprintf("Энтропия источника (%s), среднее по %d источникам", source.spec, *trials),
+		Columns: []report.Column{
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "entropy", Title: "Средн. H", Format: "%.4f", Width: 10},
+			{Key: "max_entropy", Title: "Макс. H", Format: "%.4f", Width: 10},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	mean := plot.Series{Name: "H, " + source.spec.String()}
+	maximum := plot.Series{Name: "log2 n"}
+	for n := *nMin; n <= *nMax; n++ {
+		sum := 0.0
+		for t := 0; t < *trials; t++ {
+			probs, err := source.spec.Generate(n, rng)
+			if err != nil {
+				return fmt.Errorf("n = %d: %w", n, err)
+			}
+			sum += infotheory.Entropy(probs)
+		}
+		h := sum / float64(*trials)
+		if err := out.WriteRow(n, h, infotheory.MaxEntropy(n)); err != nil {
+			return err
+		}
+		mean.X, mean.Y = append(mean.X, float64(n)), append(mean.Y, h)
+		maximum.X, maximum.Y = append(maximum.X, float64(n)), append(maximum.Y, infotheory.MaxEntropy(n))
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  "Энтропия источника в зависимости от размера алфавита",
+		XLabel: "Размер алфавита n",
+		YLabel: "H, бит",
+		Series: []plot.Series{mean, maximum},
+	})
+}
+
+func runPlotInformation(path string, args []string) error {
+	fs := newFlagSet(path, "График H(X), H(X|Y) и I(X;Y) для симметричного канала в зависимости от вероятности безошибочной передачи p.")
+	n := fs.Int("n", 53, "число символов алфавита")
+	pMin, pMax := addChannelFlags(fs, 0.5, 1)
+	step := fs.Float64("step", 0.05, "шаг по вероятности безошибочной передачи")
+	trials := fs.Int("trials", 20, "число источников для усреднения в каждой точке")
+	source := addSourceFlag(fs)
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "information.svg", false, false)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", *n); err != nil {
+		return err
+	}
+	if err := checkPositive("trials", *trials); err != nil {
+		return err
+	}
+	if err := checkRange(*pMin, *pMax); err != nil {
+		return err
+	}
+	ps, err := grid(*pMin, *pMax, *step)
+	if err != nil {
+		return fmt.Errorf("--p-min, --p-max и --step: %w", err)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "plot_information",
+		Title: fmt.Sprintf("Симметричный канал: n = %d, источник %s, среднее по %d источникам", *n, source.spec, *trials),
+		Columns: []report.Column{
+			{Key: "p_correct", Title: "p", Format: "%.4f", Width: 6},
+			{Key: "entropy", Title: "H(X)", Format: "%.4f", Width: 8},
+			{Key: "equivocation", Title: "H(X|Y)", Format: "%.4f", Width: 8},
+			{Key: "information", Title: "I(X;Y)", Format: "%.4f", Width: 8},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	series := []plot.Series{{Name: "H(X)"}, {Name: "H(X|Y)"}, {Name: "I(X;Y)"}}
+	for _, p := range ps {
+		ch, err := randomSymmetricChannel(*n, p, p, rng)
+		if err != nil {
+			return fmt.Errorf("p = %g: %w", p, err)
+		}
+		var sum channelStats
+		for t := 0; t < *trials; t++ {
+			probs, err := source.spec.Generate(*n, rng)
+			if err != nil {
+				return fmt.Errorf("p = %g: %w", p, err)
+			}
+			input, err := infotheory.NewDistribution(probs)
+			if err != nil {
+				return fmt.Errorf("p = %g: %w", p, err)
+			}
+			stats, err := analyzeChannel(input, ch, infotheory.Bits)
+			if err != nil {
+				return fmt.Errorf("p = %g: %w", p, err)
+			}
+			sum.Entropy += stats.Entropy
+			sum.Equivocation += stats.Equivocation
+			sum.Information += stats.Information
+		}
+		values := []float64{sum.Entropy, sum.Equivocation, sum.Information}
+		for i := range values {
+			values[i] /= float64(*trials)
+			series[i].X = append(series[i].X, p)
+			series[i].Y = append(series[i].Y, values[i])
+		}
+		if err := out.WriteRow(p, values[0], values[1], values[2]); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  fmt.Sprintf("Количество информации в симметричном канале, n = %d", *n),
+		XLabel: "Вероятность безошибочной передачи p",
+		YLabel: "бит",
+		Series: series,
+	})
+}
+
+func runPlotCapacity(path string, args []string) error {
+	fs := newFlagSet(path, "График пропускной способности C и скорости передачи R в зависимости от уровня помех ε = 1 - p.")
+	n := fs.Int("n", 16, "число символов алфавита")
+	noiseMax := fs.Float64("noise-max", 0, "наибольший уровень помех (0 — 1 - 1/n, канал без передачи информации)")
+	step := fs.Float64("step", 0.05, "шаг по уровню помех")
+	trials := fs.Int("trials", 20, "число экспериментов для усреднения в каждой точке")
+	durationMax := fs.Float64("duration-max", 0, "максимальная длительность символа, с (0 — n)")
+	source := addSourceFlag(fs)
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "capacity.svg", false, false)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", *n); err != nil {
+		return err
+	}
+	if err := checkPositive("trials", *trials); err != nil {
+		return err
+	}
+	if *noiseMax == 0 {
+		*noiseMax = 1 - 1/float64(*n)
+	}
+	if err := checkRange(0, *noiseMax); err != nil {
+		return fmt.Errorf("--noise-max: %w", err)
+	}
+	if *durationMax == 0 {
+		*durationMax = float64(*n)
+	}
+	if *durationMax < 0 {
+		return fmt.Errorf("--duration-max: значение должно быть больше 0, получено %g", *durationMax)
+	}
+	noise, err := grid(0, *noiseMax, *step)
+	if err != nil {
+		return fmt.Errorf("--noise-max и --step: %w", err)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "plot_capacity",
+		Title: fmt.Sprintf("Симметричный канал: n = %d, источник %s, среднее по %d экспериментам", *n, source.spec, *trials),
+		Columns: []report.Column{
+			{Key: "noise", Title: "ε", Format: "%.4f", Width: 6},
+			{Key: "capacity", Title: "C (бит/с)", Format: "%.4f", Width: 9},
+			{Key: "rate", Title: "R (бит/с)", Format: "%.4f", Width: 9},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	capacity := plot.Series{Name: "Пропускная способность C"}
+	rate := plot.Series{Name: "Скорость передачи R"}
+	for _, eps := range noise {
+		var c, r float64
+		for t := 0; t < *trials; t++ {
+			res, err := runCapacityTrial(*n, source.spec, 1-eps, 1-eps, *durationMax, rng)
+			if err != nil {
+				return fmt.Errorf("ε = %g: %w", eps, err)
+			}
+			c += res.Capacity
+			r += res.Rate
+		}
+		c /= float64(*trials)
+		r /= float64(*trials)
+		if err := out.WriteRow(eps, c, r); err != nil {
+			return err
+		}
+		capacity.X, capacity.Y = append(capacity.X, eps), append(capacity.Y, c)
+		rate.X, rate.Y = append(rate.X, eps), append(rate.Y, r)
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  fmt.Sprintf("Пропускная способность и скорость передачи, n = %d", *n),
+		XLabel: "Уровень помех ε = 1 - p",
+		YLabel: "бит/с",
+		Series: []plot.Series{capacity, rate},
+	})
+}
+
+// codeSpec — семейство кодов для флагов --code и --codes: число целых
+// параметров после имени семейства и конструктор
+type codeSpec struct {
+	params int
+	build  func(p []int) (coding.LinearCode, error)
+}
+
+// codeSpecs — коды, которые можно указать во флагах --code и --codes, по семействам
+var codeSpecs = map[string]codeSpec{
+	"hamming":    {1, func(p []int) (coding.LinearCode, error) { return coding.NewSystematicHamming(p[0]) }},
+	"positional": {1, func(p []int) (coding.LinearCode, error) { return coding.NewPositionalHamming(p[0]) }},
+	"secded":     {1, func(p []int) (coding.LinearCode, error) { return coding.NewSECDED(p[0]) }},
+	"golay": {1, func(p []int) (coding.LinearCode, error) {
+		switch p[0] {
+		case 23:
+			return coding.NewGolay(), nil
+		case 24:
+			return coding.NewExtendedGolay(), nil
+		}
+		return nil, fmt.Errorf("код Голея имеет длину 23 или 24, получено %d", p[0])
+	}},
+	"rm": {2, func(p []int) (coding.LinearCode, error) { return coding.NewReedMuller(p[0], p[1]) }},
+	// Полярный код polar:n:k, построенный для BEC(0.5), декодер SC
+	"polar": {2, func(p []int) (coding.LinearCode, error) {
+		return coding.NewPolar(p[0], p[1], 0, coding.PolarDesign{Channel: coding.PolarBEC, Param: 0.5})
+	}},
+	// Регулярный код Галлагера ldpc:n:wc:wr с фиксированным зерном построения
+	"ldpc": {3, func(p []int) (coding.LinearCode, error) {
+		return coding.NewGallagerLDPC(p[0], p[1], p[2], rand.New(rand.NewSource(1)))
+	}},
+	// Турбокод turbo:k с RSC (13,15) и S-случайным перемежителем, декодер Log-MAP
+	"turbo": {1, func(p []int) (coding.LinearCode, error) {
+		rsc, err := coding.NewRSC("13", "15")
+		if err != nil {
+			return nil, err
+		}
+		perm, err := coding.ParseInterleaver("srandom", p[0], rand.New(rand.NewSource(1)))
+		if err != nil {
+			return nil, err
+		}
+		return coding.NewTurbo(rsc, perm, false)
+	}},
+	// Код Рида — Соломона rs:m:n:k над GF(2^m) в двоичном образе
+	"rs": {3, func(p []int) (coding.LinearCode, error) { return coding.NewReedSolomon(p[0], p[1], p[2]) }},
+	// Свёрточный код conv:k:g1:g2 с восьмеричными полиномами, декодер Витерби
+	"conv": {3, func(p []int) (coding.LinearCode, error) {
+		return coding.NewConvolutional(p[0], strconv.Itoa(p[1]), strconv.Itoa(p[2]))
+	}},
+}
+
+// productIterations — число итераций декодера кодов-произведений из --codes
+const productIterations = 4
+
+// codeSpecHelp перечисляет допустимые значения флагов --code и --codes
+const codeSpecHelp = "hamming:k, positional:k, secded:k, golay:23, golay:24, rm:r:m, ldpc:n:wc:wr, polar:n:k, turbo:k, " +
+	"rs:m:n:k, conv:k:g1:g2, произведение A*B или конкатенация rs:m:n:k+B (внутренний свёрточный код — conv:g1:g2)"
+
+// parseCodeSpec создаёт код по описанию вида "hamming:4" или "rm:1:5"
+func parseCodeSpec(spec string) (coding.LinearCode, error) {
+	spec = strings.TrimSpace(spec)
+	if outerSpec, innerSpec, ok := strings.Cut(spec, "+"); ok {
+		return parseConcatenated(outerSpec, innerSpec)
+	}
+	if rowSpec, colSpec, ok := strings.Cut(spec, "*"); ok {
+		row, err := parseCodeSpec(rowSpec)
+		if err != nil {
+			return nil, err
+		}
+		col, err := parseCodeSpec(colSpec)
+		if err != nil {
+			return nil, err
+		}
+		return coding.NewProduct(row, col, productIterations)
+	}
+	fields := strings.Split(spec, ":")
+	s, known := codeSpecs[fields[0]]
+	if !known || len(fields) != s.params+1 {
+		return nil, fmt.Errorf("неизвестный код %q (ожидается %s)", spec, codeSpecHelp)
+	}
+	p := make([]int, s.params)
+	for i := range p {
+		var err error
+		if p[i], err = strconv.Atoi(fields[i+1]); err != nil {
+			return nil, fmt.Errorf("код %q: параметры должны быть целыми", spec)
+		}
+	}
+	return s.build(p)
+}
+
+// parseConcatenated строит конкатенацию внешнего кода RS и внутреннего кода.
+// Внутренний свёрточный код conv:g1:g2 завершается один раз на всё слово RS,
+// поэтому его длина k подставляется по внешнему коду.
+func parseConcatenated(outerSpec, innerSpec string) (coding.LinearCode, error) {
+	outerSpec, innerSpec = strings.TrimSpace(outerSpec), strings.TrimSpace(innerSpec)
+	if !strings.HasPrefix(outerSpec, "rs:") {
+		return nil, fmt.Errorf("внешний код конкатенации %q должен быть кодом Рида — Соломона rs:m:n:k", outerSpec)
+	}
+	outer, err := parseCodeSpec(outerSpec)
+	if err != nil {
+		return nil, err
+	}
+	rs := outer.(*coding.ReedSolomon)
+	if fields := strings.Split(innerSpec, ":"); fields[0] == "conv" && len(fields) == 3 {
+		innerSpec = fmt.Sprintf("conv:%d:%s:%s", rs.Length(), fields[1], fields[2])
+	}
+	inner, err := parseCodeSpec(innerSpec)
+	if err != nil {
+		return nil, err
+	}
+	return coding.NewConcatenated(rs, inner)
+}
+
+func runPlotBER(path string, args []string) error {
+	fs := newFlagSet(path, "Моделирование: доля ошибочных бит (BER) или слов (FER) после декодирования в зависимости от вероятности ошибки p в двоичном симметричном канале.")
+	codes := fs.String("codes", "hamming:4,hamming:11,secded:4", "коды через запятую: "+codeSpecHelp)
+	pMin := fs.Float64("p-min", 1e-3, "наименьшая вероятность ошибки в канале")
+	pMax := fs.Float64("p-max", 0.2, "наибольшая вероятность ошибки в канале")
+	points := fs.Int("points", 10, "число точек (равномерно по логарифмической шкале)")
+	words := fs.Int("words", 20000, "число слов в каждой точке")
+	metric := fs.String("metric", "ber", "величина на графике: ber или fer")
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "ber.svg", true, true)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("words", *words); err != nil {
+		return err
+	}
+	if *metric != "ber" && *metric != "fer" {
+		return fmt.Errorf("--metric: ожидается ber или fer, получено %q", *metric)
+	}
+	if err := checkRange(*pMin, *pMax); err != nil {
+		return fmt.Errorf("--p-min и --p-max: %w", err)
+	}
+	ps, err := logGrid(*pMin, *pMax, *points)
+	if err != nil {
+		return fmt.Errorf("--p-min, --p-max и --points: %w", err)
+	}
+	var list []coding.LinearCode
+	for _, spec := range strings.Split(*codes, ",") {
+		code, err := parseCodeSpec(spec)
+		if err != nil {
+			return fmt.Errorf("--codes: %w", err)
+		}
+		list = append(list, code)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "plot_ber",
+		Title: fmt.Sprintf("Двоичный симметричный канал, %d слов в каждой точке", *words),
+		Columns: []report.Column{
+			{Key: "code", Title: "Код", Width: 30},
+			{Key: "p", Title: "p", Format: "%.3e", Width: 9},
+			{Key: "ber", Title: "BER", Format: "%.3e", Width: 9},
+			{Key: "fer", Title: "FER", Format: "%.3e", Width: 9},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var series []plot.Series
+	if *metric == "ber" {
+		series = append(series, plot.Series{Name: "без кодирования", X: ps, Y: ps})
+	}
+	for _, code := range list {
+		name := code.Name()
+		s := plot.Series{Name: name}
+		for _, p := range ps {
+			rates, err := coding.SimulateBSC(code, p, *words, rng)
+			if err != nil {
+				return fmt.Errorf("%s, p = %g: %w", name, p, err)
+			}
+			if err := out.WriteRow(name, p, rates.BER(), rates.FER()); err != nil {
+				return err
+			}
+			s.X = append(s.X, p)
+			if *metric == "ber" {
+				s.Y = append(s.Y, rates.BER())
+			} else {
+				s.Y = append(s.Y, rates.FER())
+			}
+		}
+		series = append(series, s)
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  "Помехоустойчивость кодов в двоичном симметричном канале",
+		XLabel: "Вероятность ошибки в канале p",
+		YLabel: strings.ToUpper(*metric),
+		Series: series,
+	})
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"itc/coding"
+	"itc/infotheory"
+	"itc/report"
+)
+
+// polarFamily — полярные коды с декодерами SC и SCL
+var polarFamily = codeFamily{
+	name:       "полярный код",
+	addFlags:   addPolarFlags,
+	minErrors:  0,
+	maxErrors:  3,
+	experiment: 10,
+}
+
+// polarCommands — подкоманды группы polar
+func polarCommands() []*command {
+	cmds := []*command{
+		{name: "construct", summary: "надёжность синтезированных каналов и выбор замороженных бит", run: runPolarConstruct},
+		{name: "polarization", summary: "доля хороших и плохих каналов с ростом длины в сравнении с пропускной способностью", run: runPolarization},
+	}
+	return append(cmds, codeCommands(polarFamily)...)
+}
+
+// addPolarDesignFlags добавляет флаги канала и способа построения
+func addPolarDesignFlags(fs *flag.FlagSet) func() (coding.PolarDesign, error) {
+	channel := fs.String("channel", "bec", "канал построения: bec или bsc")
+	param := fs.Float64("param", 0.5, "вероятность стирания (bec) или ошибки (bsc)")
+	method := fs.String("method", "bhattacharyya", "оценка надёжности: bhattacharyya или de (эволюция плотностей)")
+	return func() (coding.PolarDesign, error) {
+		d, err := coding.ParsePolarDesign(*channel, *param, *method)
+		if err != nil {
+			return d, fmt.Errorf("--channel, --param и --method: %w", err)
+		}
+		return d, nil
+	}
+}
+
+// addPolarFlags добавляет флаги длины, размерности, CRC, построения и размера списка
+func addPolarFlags(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+	n := fs.Int("n", 128, "длина кода, степень двойки")
+	k := fs.Int("k", 64, "число информационных бит")
+	crc := fs.Int("crc", 0, "длина CRC: 0 (без CRC), 6, 8, 11, 16 или 24")
+	newDesign := addPolarDesignFlags(fs)
+	list := fs.Int("list", 1, "размер списка декодера: 1 — SC, больше 1 — SCL")
+	return func() (coding.LinearCode, error) {
+		d, err := newDesign()
+		if err != nil {
+			return nil, err
+		}
+		code, err := coding.NewPolar(*n, *k, *crc, d)
+		if err != nil {
+			return nil, err
+		}
+		if err := code.SetListSize(*list); err != nil {
+			return nil, fmt.Errorf("--list: %w", err)
+		}
+		return code, nil
+	}
+}
+
+// designCapacity вычисляет пропускную способность канала построения (бит на
+// символ) алгоритмом Блейхута — Аримото, как в лабораторной работе 3
+func designCapacity(d coding.PolarDesign) (float64, error) {
+	var ch infotheory.StochasticMatrix
+	var err error
+	if d.Channel == coding.PolarBSC {
+		ch, err = infotheory.SymmetricChannel([]float64{1 - d.Param, 1 - d.Param})
+	} else {
+		// Выходы: 0, стирание, 1
+		ch, err = infotheory.NewStochasticMatrix([][]float64{{1 - d.Param, d.Param, 0}, {0, d.Param, 1 - d.Param}})
+	}
+	if err != nil {
+		return 0, err
+	}
+	c, err := infotheory.ChannelCapacity(ch, infotheory.Bits, 0)
+	if err != nil {
+		return 0, err
+	}
+	return c.Value, nil
+}
+
+func runPolarConstruct(path string, args []string) error {
+	fs := newFlagSet(path, "Построение полярного кода: показатели надёжности всех синтезированных каналов u_i\n"+
+		"(параметры Бхаттачарьи Z или вероятности ошибки по эволюции плотностей) и их роли —\n"+
+		"данные, CRC или замороженный бит. Скорость кода сравнивается с пропускной способностью канала.")
+	newCode := addPolarFlags(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	lc, err := newCode()
+	if err != nil {
+		return err
+	}
+	code := lc.(*coding.Polar)
+	capacity, err := designCapacity(code.Design())
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	metric := "Z"
+	if code.Design().Method == coding.DensityEvolution {
+		metric = "P ошибки"
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "polar_construct",
+		Title: fmt.Sprintf("%s, построение: %s", code.Name(), code.Design()),
+		Columns: []report.Column{
+			{Key: "index", Title: "i", Width: 5},
+			{Key: "reliability", Title: metric, Format: "%.3e", Width: 9},
+			{Key: "rank", Title: "Место", Width: 5},
+			{Key: "role", Title: "Бит u_i"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	reliability := code.Reliability()
+	role := make(map[int]string)
+	for t, pos := range code.InformationPositions() {
+		role[pos] = "данные"
+		if t >= code.Dimension() {
+			role[pos] = "CRC"
+		}
+	}
+	// Место канала по надёжности: 1 — самый надёжный (при равенстве — с большим номером)
+	order := make([]int, len(reliability))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := reliability[order[a]], reliability[order[b]]
+		return ra < rb || (ra == rb && order[a] > order[b])
+	})
+	rank := make([]int, len(reliability))
+	for place, i := range order {
+		rank[i] = place + 1
+	}
+	bound := 0.0
+	for i, r := range reliability {
+		name, ok := role[i]
+		if !ok {
+			name = "заморожен"
+		} else {
+			bound += r
+		}
+		if err := out.WriteRow(i, r, rank[i], name); err != nil {
+			return err
+		}
+	}
+	err = out.EndTable(
+		report.Field{Key: "rate", Title: "Скорость кода R = k/n", Value: float64(code.Dimension()) / float64(code.Length())},
+		report.Field{Key: "capacity", Title: "Пропускная способность канала C, бит/символ", Value: capacity},
+		report.Field{Key: "block_error_bound", Title: "Сумма показателей по информационным каналам (оценка FER для SC)", Value: bound},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runPolarization(path string, args []string) error {
+	fs := newFlagSet(path, "Поляризация каналов: с ростом длины n = 2^m доля почти идеальных синтезированных каналов\n"+
+		"(показатель меньше delta) стремится к пропускной способности C, а доля почти бесполезных — к 1 - C.")
+	newDesign := addPolarDesignFlags(fs)
+	mMax := fs.Int("m-max", 10, "наибольший порядок m (длина 2^m)")
+	delta := fs.Float64("delta", 1e-3, "порог близости к идеальному и бесполезному каналу")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("m-max", *mMax); err != nil {
+		return err
+	}
+	if *delta <= 0 || *delta >= 0.25 {
+		return fmt.Errorf("--delta: ожидается значение из интервала (0, 0.25), получено %g", *delta)
+	}
+	d, err := newDesign()
+	if err != nil {
+		return err
+	}
+	capacity, err := designCapacity(d)
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "polarization",
+		Title: fmt.Sprintf("Поляризация каналов, построение: %s", d),
+		Columns: []report.Column{
+			{Key: "m", Title: "m", Width: 3},
+			{Key: "n", Title: "n", Width: 8},
+			{Key: "good", Title: "Доля хороших", Format: "%.4f"},
+			{Key: "bad", Title: "Доля плохих", Format: "%.4f"},
+			{Key: "unpolarized", Title: "Не поляризованы", Format: "%.4f"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	worst := d.WorstLevel()
+	for m := 1; m <= *mMax; m++ {
+		reliability, err := coding.PolarReliability(1<<m, d)
+		if err != nil {
+			return err
+		}
+		good, bad := 0, 0
+		for _, r := range reliability {
+			switch {
+			case r < *delta:
+				good++
+			case r > worst-*delta:
+				bad++
+			}
+		}
+		n := float64(len(reliability))
+		if err := out.WriteRow(m, len(reliability), float64(good)/n, float64(bad)/n, float64(len(reliability)-good-bad)/n); err != nil {
+			return err
+		}
+	}
+	err = out.EndTable(
+		report.Field{Key: "capacity", Title: "Пропускная способность C, бит/символ", Value: capacity},
+		report.Field{Key: "loss", Title: "1 - C", Value: 1 - capacity},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"itc/coding"
+	"itc/report"
+)
+
+// qhammingCommands — подкоманды группы qhamming
+var qhammingCommands = []*command{
+	{name: "encode", summary: "кодирование информационных символов", run: runQHammingEncode},
+	{name: "decode", summary: "декодирование принятого слова с исправлением ошибки в одном символе", run: runQHammingDecode},
+	{name: "simulate", summary: "эксперименты с внесением случайных ошибок в символы", run: runQHammingSimulate},
+}
+
+// addQHammingFlags добавляет флаги размера поля и числа проверочных символов
+func addQHammingFlags(fs *flag.FlagSet) func() (*coding.QaryHamming, error) {
+	q := fs.Int("q", 3, "размер поля GF(q): простое число или степень простого, не больше 256")
+	r := fs.Int("r", 2, "число проверочных символов, длина кода (q^r-1)/(q-1)")
+	return func() (*coding.QaryHamming, error) { return coding.NewQaryHamming(*q, *r) }
+}
+
+// qhammingTitle — заголовок таблиц команд qhamming
+func qhammingTitle(code *coding.QaryHamming) string {
+	return fmt.Sprintf("%s: n = %d, k = %d", code.Name(), code.Length(), code.Dimension())
+}
+
+func runQHammingEncode(path string, args []string) error {
+	fs := newFlagSet(path, "Кодирование информационных символов кодом Хэмминга над GF(q). При q <= 10 символы\n"+
+		"записываются цифрами подряд, иначе — числами через запятую.")
+	newCode := addQHammingFlags(fs)
+	data := fs.String("data", "", "информационные символы, например 0121 (пусто — случайные)")
+	matrices := fs.Bool("matrices", false, "вывести производящую и проверочную матрицы")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	q := code.Field().Size()
+	var msg []int
+	if *data == "" {
+		msg = coding.RandomSymbols(code.Dimension(), q, newRand())
+	} else if msg, err = coding.ParseSymbols(*data, q); err != nil {
+		return fmt.Errorf("--data: %w", err)
+	}
+	word, err := code.Encode(msg)
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	if *matrices {
+		if err := writeMatrix(out, "generator_matrix", "Производящая матрица G", code.GeneratorMatrix(), "%d"); err != nil {
+			return err
+		}
+		if err := writeMatrix(out, "parity_check_matrix", "Проверочная матрица H", code.ParityCheckMatrix(), "%d"); err != nil {
+			return err
+		}
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "encode",
+		Title: qhammingTitle(code),
+		Columns: []report.Column{
+			{Key: "data", Title: "Информационные символы"},
+			{Key: "codeword", Title: "Кодовое слово"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := out.WriteRow(coding.SymbolsToString(msg, q), coding.SymbolsToString(word, q)); err != nil {
+		return err
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runQHammingDecode(path string, args []string) error {
+	fs := newFlagSet(path, "Декодирование принятого слова кодом Хэмминга над GF(q): синдром s = H·y равен e·h_j\n"+
+		"для ошибки e в символе j, поэтому значение ошибки — первый ненулевой элемент синдрома,\n"+
+		"а позиция — номер столбца H, пропорционального синдрому.")
+	newCode := addQHammingFlags(fs)
+	wordText := fs.String("word", "", "принятое слово из n символов (обязательный флаг)")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *wordText == "" {
+		fs.Usage()
+		return fmt.Errorf("не задан флаг --word")
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	q := code.Field().Size()
+	received, err := coding.ParseSymbols(*wordText, q)
+	if err != nil {
+		return fmt.Errorf("--word: %w", err)
+	}
+	res, err := code.Decode(received)
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "decode",
+		Title: qhammingTitle(code),
+		Columns: []report.Column{
+			{Key: "received", Title: "Принятое слово"},
+			{Key: "syndrome", Title: "Синдром"},
+			{Key: "status", Title: "Решение"},
+			{Key: "error_position", Title: "Позиция ошибки"},
+			{Key: "error_value", Title: "Значение ошибки"},
+			{Key: "corrected", Title: "Исправленное слово"},
+			{Key: "data", Title: "Информационные символы"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, value, _ := code.FindError(res.Syndrome)
+	err = out.WriteRow(coding.SymbolsToString(received, q), coding.SymbolsToString(res.Syndrome, q), res.Status,
+		res.ErrorPos, value, coding.SymbolsToString(res.Codeword, q), coding.SymbolsToString(res.Data, q))
+	if err != nil {
+		return err
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runQHammingSimulate(path string, args []string) error {
+	fs := newFlagSet(path, "Эксперименты: случайные символы кодируются кодом Хэмминга над GF(q), к случайным\n"+
+		"символам слова прибавляются ненулевые ошибки, затем слово декодируется.")
+	newCode := addQHammingFlags(fs)
+	experiments := fs.Int("experiments", 10, "число экспериментов")
+	minErrors := fs.Int("min-errors", 0, "наименьшее число ошибочных символов в слове")
+	maxErrors := fs.Int("max-errors", 1, "наибольшее число ошибочных символов в слове")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("experiments", *experiments); err != nil {
+		return err
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	if *minErrors < 0 || *minErrors > *maxErrors || *maxErrors > code.Length() {
+		return fmt.Errorf("--min-errors и --max-errors: ожидается 0 <= min <= max <= %d, получено [%d, %d]",
+			code.Length(), *minErrors, *maxErrors)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+	q := code.Field().Size()
+	// Ширина записи вектора из n символов
+	width := func(n int) int { return len(coding.SymbolsToString(make([]int, n), q)) }
+
+	err = out.BeginTable(&report.Table{
+		Name:  "simulate",
+		Title: fmt.Sprintf("%s, ошибок в слове: %d..%d", qhammingTitle(code), *minErrors, *maxErrors),
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "data", Title: "Данные", Width: width(code.Dimension())},
+			{Key: "codeword", Title: "Кодовое слово", Width: width(code.Length())},
+			{Key: "errors", Title: "Ошибки", Width: 6},
+			{Key: "received", Title: "Принятое слово", Width: width(code.Length())},
+			{Key: "syndrome", Title: "Синдром", Width: width(code.Redundancy())},
+			{Key: "status", Title: "Решение", Width: statusWidth},
+			{Key: "data_ok", Title: "Данные верны"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[coding.DecodeStatus]int)
+	correct := 0
+	for exp := 1; exp <= *experiments; exp++ {
+		data := coding.RandomSymbols(code.Dimension(), q, rng)
+		word, err := code.Encode(data)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", exp, err)
+		}
+		count := *minErrors + rng.Intn(*maxErrors-*minErrors+1)
+		received, positions, err := code.InjectSymbolErrors(word, count, rng)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", exp, err)
+		}
+		res, err := code.Decode(received)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", exp, err)
+		}
+
+		errStr := "-"
+		if len(positions) > 0 {
+			errStr = joinInts(positions)
+		}
+		ok := coding.BitsEqual(data, res.Data)
+		statuses[res.Status]++
+		if ok {
+			correct++
+		}
+		err = out.WriteRow(exp, coding.SymbolsToString(data, q), coding.SymbolsToString(word, q), errStr,
+			coding.SymbolsToString(received, q), coding.SymbolsToString(res.Syndrome, q), res.Status, ok)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = out.EndTable(
+		report.Field{Key: "no_errors", Title: "Ошибок нет", Value: statuses[coding.StatusOK]},
+		report.Field{Key: "corrected", Title: "Исправлено", Value: statuses[coding.StatusCorrected]},
+		report.Field{Key: "data_ok", Title: "Данные восстановлены корректно", Value: correct},
+		report.Field{Key: "experiments", Title: "Экспериментов", Value: *experiments},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
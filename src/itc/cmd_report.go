@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"itc/docx"
+	"itc/plot"
+	"itc/report"
+)
+
+// Команда report ссылается на список commands, поэтому добавляется в него
+// при инициализации, а не в объявлении
+func init() {
+	commands = append(commands, &command{
+		name:    "report",
+		summary: "запуск эксперимента и отчёт в формате DOCX с таблицами и графиками",
+		run:     runReport,
+	})
+}
+
+// chartSpec описывает график, который строится по таблице результатов
+type chartSpec struct {
+	table string   // имя таблицы
+	x     string   // столбец значений по оси абсцисс
+	y     []string // столбцы кривых
+	group string   // столбец, по значениям которого кривая y[0] делится на несколько
+}
+
+// reportCharts — графики отчёта для команд экспериментов
+var reportCharts = map[string][]chartSpec{
+	"entropy":     {{table: "entropy", x: "n", y: []string{"entropy", "max_entropy"}}},
+	"markov":      {{table: "markov", x: "n", y: []string{"redundancy"}, group: "order"}},
+	"generalized": {{table: "generalized", x: "n", y: []string{"shannon", "hartley", "renyi_0_5", "renyi_2", "min_entropy"}}},
+	"estimate":    {{table: "estimate", x: "n", y: []string{"entropy", "plugin", "miller_madow", "chao_shen", "grassberger", "nsb"}}},
+	"empirical":   {{table: "empirical", x: "order", y: []string{"block_entropy", "conditional_entropy"}}},
+	"channel":     {{table: "channel", x: "iteration", y: []string{"entropy", "equivocation", "information"}}},
+	"capacity": {
+		{table: "capacity_noise", x: "experiment", y: []string{"capacity", "rate"}},
+		{table: "capacity_noiseless", x: "experiment", y: []string{"capacity", "rate"}},
+	},
+}
+
+// buildChart строит график по сохранённой таблице
+func buildChart(t *report.RecordedTable, spec chartSpec) (plot.Chart, error) {
+	xs, ok := t.Floats(spec.x)
+	if !ok {
+		return plot.Chart{}, fmt.Errorf("таблица %q: нет столбца %q", t.Table.Name, spec.x)
+	}
+	title := func(key string) string { return t.Table.Columns[t.Column(key)].Title }
+	chart := plot.Chart{Title: t.Table.Title, XLabel: title(spec.x)}
+	for _, key := range spec.y {
+		if t.Column(key) < 0 {
+			return plot.Chart{}, fmt.Errorf("таблица %q: нет столбца %q", t.Table.Name, key)
+		}
+	}
+	if len(spec.y) == 1 {
+		chart.YLabel = title(spec.y[0])
+	}
+
+	if spec.group == "" {
+		for _, key := range spec.y {
+			ys, _ := t.Floats(key)
+			chart.Series = append(chart.Series, plot.Series{Name: title(key), X: xs, Y: ys})
+		}
+		return chart, nil
+	}
+	groups, ok := t.Floats(spec.group)
+	if !ok {
+		return plot.Chart{}, fmt.Errorf("таблица %q: нет столбца %q", t.Table.Name, spec.group)
+	}
+	ys, _ := t.Floats(spec.y[0])
+	index := make(map[float64]int)
+	for i, g := range groups {
+		j, ok := index[g]
+		if !ok {
+			j = len(chart.Series)
+			index[g] = j
+			chart.Series = append(chart.Series, plot.Series{Name: fmt.Sprintf("%s = %g", title(spec.group), g)})
+		}
+		chart.Series[j].X = append(chart.Series[j].X, xs[i])
+		chart.Series[j].Y = append(chart.Series[j].Y, ys[i])
+	}
+	return chart, nil
+}
+
+// findCommand возвращает подкоманду, которую запустят аргументы args
+func findCommand(cmds []*command, args []string) *command {
+	for len(args) > 0 {
+		var found *command
+		for _, c := range cmds {
+			if c.name == args[0] {
+				found = c
+			}
+		}
+		if found == nil || found.subcommands == nil {
+			return found
+		}
+		cmds, args = found.subcommands, args[1:]
+	}
+	return nil
+}
+
+// capitalize делает первую букву строки заглавной
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func runReport(path string, args []string) error {
+	fs := newFlagSet(path,
+		"Запускает эксперимент, заданный после флагов отчёта (<команда> [флаги команды]), и сохраняет\n"+
+			"отчёт DOCX: параметры, таблицы результатов, матрицы и графики.\n"+
+			"Вид отчёта задаёт шаблон text/template (встроенный выводит флаг --print-template).\n"+
+			"Пример: "+path+" --out lab2.docx channel --n 8 --matrix")
+	outPath := fs.String("out", "report.docx", "файл отчёта")
+	templatePath := fs.String("template", "", "файл шаблона отчёта (пусто — встроенный шаблон)")
+	printTemplate := fs.Bool("print-template", false, "вывести встроенный шаблон и завершить работу")
+	title := fs.String("title", "", "заголовок отчёта (пусто — описание команды)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *printTemplate {
+		fmt.Print(docx.DefaultTemplate)
+		return nil
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("не указана команда эксперимента")
+	}
+	cmdArgs := fs.Args()
+	if cmdArgs[0] == "report" {
+		return fmt.Errorf("команда report не может запускать сама себя")
+	}
+	layout := docx.DefaultTemplate
+	if *templatePath != "" {
+		data, err := os.ReadFile(*templatePath)
+		if err != nil {
+			return err
+		}
+		layout = string(data)
+	}
+	if err := docx.CheckTemplate(layout); err != nil {
+		return err
+	}
+
+	c, err := runCaptured(cmdArgs)
+	if err != nil {
+		return err
+	}
+
+	rep := &docx.Report{
+		Title:   *title,
+		Command: "itc " + strings.Join(cmdArgs, " "),
+		Date:    time.Now().Format("02.01.2006"),
+		Tables:  c.recorder.Tables,
+	}
+	if rep.Title == "" {
+		if cmd := findCommand(commands, cmdArgs); cmd != nil {
+			rep.Title = capitalize(cmd.summary)
+		}
+	}
+	if c.flags != nil {
+		c.flags.VisitAll(func(f *flag.Flag) {
+			if f.Name != "format" {
+				rep.Params = append(rep.Params, docx.Param{Name: f.Name, Value: f.Value.String(), Usage: f.Usage})
+			}
+		})
+	}
+	for _, spec := range reportCharts[cmdArgs[0]] {
+		t := c.recorder.Table(spec.table)
+		if t == nil {
+			continue
+		}
+		chart, err := buildChart(t, spec)
+		if err != nil {
+			return err
+		}
+		var img bytes.Buffer
+		if err := chart.WritePNG(&img, plot.DefaultWidth, plot.DefaultHeight); err != nil {
+			return err
+		}
+		rep.Charts = append(rep.Charts, docx.Chart{Title: chart.Title, PNG: img.Bytes(), Width: plot.DefaultWidth, Height: plot.DefaultHeight})
+	}
+
+	f, err := os.Create(*outPath)
+	if err != nil {
+		return err
+	}
+	if err := docx.Write(f, layout, rep); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Отчёт сохранён в %s: таблиц %d, графиков %d\n", *outPath, len(rep.Tables), len(rep.Charts))
+	return nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"itc/experiment"
+	"itc/report"
+)
+
+// Команда run, как и report, запускает другие команды из списка commands
+func init() {
+	commands = append(commands, &command{
+		name:    "run",
+		summary: "серия экспериментов по файлу конфигурации YAML, JSON или TOML с сеткой параметров",
+		run:     runRun,
+	})
+}
+
+// runCaptured запускает команду itc с аргументами args, перехватывая её
+// таблицы и разобранные флаги
+func runCaptured(args []string) (*capture, error) {
+	c := &capture{}
+	activeCapture = c
+	defer func() { activeCapture = nil }()
+	if err := dispatch("itc", "Теория информации и кодирования: эксперименты лабораторных работ.", commands, args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// savePoint сохраняет таблицы точки в файл path в формате f
+func savePoint(path string, f report.Format, rec *report.Recorder) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w, err := report.NewWriter(file, f)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := rec.Replay(w); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func runRun(path string, args []string) error {
+	fs := newFlagSet(path, "Серия экспериментов: команда из файла конфигурации запускается в каждой точке декартова\n"+
+		"произведения значений параметров, результаты точки сохраняются в отдельный файл, а список\n"+
+		"точек с параметрами — в index.csv. Формат конфигурации определяется расширением файла.")
+	configPath := fs.String("config", "", "файл конфигурации: .yaml, .yml, .json или .toml (обязательный флаг)")
+	outDir := fs.String("out-dir", "", "каталог результатов (пусто — output.dir из конфигурации)")
+	dryRun := fs.Bool("dry-run", false, "только вывести команды всех точек, не запуская их")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *configPath == "" {
+		fs.Usage()
+		return fmt.Errorf("не задан флаг --config")
+	}
+	cfg, err := experiment.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if name := cfg.Command[0]; name == "run" || name == "report" {
+		return fmt.Errorf("команда %s не может выполняться в серии экспериментов", name)
+	}
+	if findCommand(commands, cfg.Command) == nil {
+		return fmt.Errorf("неизвестная команда %q", strings.Join(cfg.Command, " "))
+	}
+	format, err := report.ParseFormat(cfg.Output.Format)
+	if err != nil {
+		return fmt.Errorf("output.format: %w", err)
+	}
+	dir := cfg.Output.Dir
+	if *outDir != "" {
+		dir = *outDir
+	}
+	points := cfg.Points()
+
+	if *dryRun {
+		for i, p := range points {
+			fmt.Printf("%d: itc %s\n", i+1, strings.Join(cfg.Args(p), " "))
+		}
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// Список точек: номер, файл результатов, значения параметров
+	columns := []report.Column{{Key: "point", Title: "Точка"}, {Key: "file", Title: "Файл"}}
+	for _, p := range cfg.Params {
+		columns = append(columns, report.Column{Key: p.Name, Title: p.Name})
+	}
+	indexRec := &report.Recorder{}
+	if err := indexRec.BeginTable(&report.Table{Name: "points", Title: "Точки серии", Columns: columns}); err != nil {
+		return err
+	}
+	for i, p := range points {
+		fmt.Fprintf(os.Stderr, "Точка %d/%d: %s\n", i+1, len(points), p)
+		c, err := runCaptured(cfg.Args(p))
+		if err != nil {
+			return fmt.Errorf("точка %d (%s): %w", i+1, p, err)
+		}
+		name := fmt.Sprintf("point_%04d.%s", i+1, format.Ext())
+		if err := savePoint(filepath.Join(dir, name), format, &c.recorder); err != nil {
+			return fmt.Errorf("точка %d (%s): %w", i+1, p, err)
+		}
+		row := []any{i + 1, name}
+		for _, a := range p {
+			row = append(row, a.Value)
+		}
+		if err := indexRec.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := indexRec.EndTable(); err != nil {
+		return err
+	}
+	if err := savePoint(filepath.Join(dir, "index.csv"), report.FormatCSV, indexRec); err != nil {
+		return err
+	}
+	fmt.Printf("Серия из %d точек сохранена в %s\n", len(points), dir)
+	return nil
+}
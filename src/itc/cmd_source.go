@@ -0,0 +1,447 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"itc/infotheory"
+	"itc/report"
+)
+
+// defaultAlphabets — размеры алфавита источника из лабораторной работы 1
+var defaultAlphabets = intList{8, 9, 10, 11, 12, 13}
+
+// formatSequence преобразует последовательность символов в строку, ограничивая её длину
+func formatSequence(seq []int, limit int) string {
+	strs := make([]string, 0, limit)
+	for i, a := range seq {
+		if i == limit {
+			strs = append(strs, "...")
+			break
+		}
+		strs = append(strs, fmt.Sprintf("%d", a))
+	}
+	return strings.Join(strs, " ")
+}
+
+// entropyTable — таблица лабораторной работы 1
+var entropyTable = report.Table{
+	Name: "entropy",
+	Columns: []report.Column{
+		{Key: "experiment", Title: "Exp", Width: 3},
+		{Key: "n", Title: "n", Width: 3},
+		{Key: "probabilities", Title: "Вероятности", Format: "%.4f", Width: 110},
+		{Key: "entropy", Title: "Средн. H", Format: "%.4f", Width: 10},
+		{Key: "max_entropy", Title: "Макс. H", Format: "%.4f", Width: 10},
+	},
+}
+
+func runEntropy(path string, args []string) error {
+	fs := newFlagSet(path, "Энтропия источников со случайным распределением вероятностей и её максимум log2(n).")
+	ns := append(intList(nil), defaultAlphabets...)
+	fs.Var(&ns, "n", "размеры алфавита источника через запятую")
+	source := addSourceFlag(fs)
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", ns...); err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	if err := out.BeginTable(&entropyTable); err != nil {
+		return err
+	}
+	sum := 0.0
+	for i, n := range ns {
+		probs, err := source.spec.Generate(n, rng)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", i+1, err)
+		}
+		h := infotheory.Entropy(probs)
+		sum += h
+		if err := out.WriteRow(i+1, n, probs, h, infotheory.MaxEntropy(n)); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(report.Field{Key: "mean_entropy", Title: "Список Средн. H", Value: sum / float64(len(ns))}); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// sweepSources — законы распределения от вырожденного до равномерного
+var sweepSources = []string{
+	"degenerate",
+	"dirichlet:0.01", "dirichlet:0.1", "dirichlet:0.5", "dirichlet:1", "dirichlet:10", "dirichlet:100",
+	"zipf:4", "zipf:2", "zipf:1", "zipf:0.5",
+	"geometric:0.9", "geometric:0.5", "geometric:0.1",
+	"binomial:0.5",
+	"random",
+	"uniform",
+}
+
+func runSweep(path string, args []string) error {
+	fs := newFlagSet(path, "Зависимость энтропии источника от закона распределения: от 0 до log2(n).")
+	n := fs.Int("n", 13, "размер алфавита источника")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", *n); err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "sweep",
+		Title: fmt.Sprintf("Зависимость энтропии от закона распределения (n = %d)", *n),
+		Columns: []report.Column{
+			{Key: "source", Title: "Распределение", Width: 16},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "entropy", Title: "H", Format: "%.4f", Width: 10},
+			{Key: "max_entropy", Title: "Макс. H", Format: "%.4f", Width: 10},
+			{Key: "ratio", Title: "H/Hmax", Format: "%.4f", Width: 7},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	maxEnt := infotheory.MaxEntropy(*n)
+	for _, text := range sweepSources {
+		spec, err := infotheory.ParseSourceSpec(text)
+		if err != nil {
+			return err
+		}
+		probs, err := spec.Generate(*n, rng)
+		if err != nil {
+			return fmt.Errorf("распределение %s: %w", text, err)
+		}
+		h := infotheory.Entropy(probs)
+		ratio := 0.0
+		if maxEnt > 0 {
+			ratio = h / maxEnt
+		}
+		if err := out.WriteRow(spec.String(), *n, h, maxEnt, ratio); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runMarkov(path string, args []string) error {
+	fs := newFlagSet(path, "Энтропия H∞ и избыточность случайных марковских источников порядка k.")
+	ns := append(intList(nil), defaultAlphabets...)
+	fs.Var(&ns, "n", "размеры алфавита источника через запятую")
+	orders := intList{1, 2}
+	fs.Var(&orders, "orders", "порядки марковской цепи через запятую")
+	length := fs.Int("length", 40, "длина генерируемой последовательности")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", ns...); err != nil {
+		return err
+	}
+	if err := checkPositive("length", *length); err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "markov",
+		Title: "Марковские источники: H∞ — энтропия источника, H0 — энтропия без учёта памяти",
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "order", Title: "k", Width: 3},
+			{Key: "entropy_rate", Title: "H∞", Format: "%.4f", Width: 10},
+			{Key: "memoryless_entropy", Title: "H0", Format: "%.4f", Width: 10},
+			{Key: "max_entropy", Title: "Макс. H", Format: "%.4f", Width: 10},
+			{Key: "redundancy", Title: "Избыточность", Format: "%.4f", Width: 12},
+			{Key: "sequence", Title: "Последовательность", Width: 50},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	exp := 1
+	for _, order := range orders {
+		for _, n := range ns {
+			source, err := infotheory.RandomMarkovSource(order, n, rng)
+			if err != nil {
+				return fmt.Errorf("эксперимент %d: %w", exp, err)
+			}
+			stats, err := source.Analyze()
+			if err != nil {
+				return fmt.Errorf("эксперимент %d: %w", exp, err)
+			}
+			seq, err := source.Generate(*length, rng)
+			if err != nil {
+				return fmt.Errorf("эксперимент %d: %w", exp, err)
+			}
+			err = out.WriteRow(exp, n, order, stats.EntropyRate, stats.MemorylessEntropy, stats.MaxEntropy,
+				stats.Redundancy, formatSequence(seq, 20))
+			if err != nil {
+				return err
+			}
+			exp++
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runGeneralized(path string, args []string) error {
+	fs := newFlagSet(path, "Энтропии Реньи разных порядков, энтропия Цаллиса S2 и расхождение D(p||u) с равномерным распределением.")
+	ns := append(intList(nil), defaultAlphabets...)
+	fs.Var(&ns, "n", "размеры алфавита источника через запятую")
+	source := addSourceFlag(fs)
+	base := addUnitFlag(fs, "энтропий")
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", ns...); err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+	unit := base.unit
+
+	err = out.BeginTable(&report.Table{
+		Name:  "generalized",
+		Title: fmt.Sprintf("Обобщённые энтропии (%s): H_α — энтропия Реньи порядка α, S2 — энтропия Цаллиса, D(p||u) — расхождение с равномерным", unit),
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "shannon", Title: "Шеннон", Format: "%.4f", Width: 9},
+			{Key: "hartley", Title: "Хартли H0", Format: "%.4f", Width: 9},
+			{Key: "renyi_0_5", Title: "H_1/2", Format: "%.4f", Width: 9},
+			{Key: "renyi_2", Title: "H_2", Format: "%.4f", Width: 9},
+			{Key: "min_entropy", Title: "H∞ (мин.)", Format: "%.4f", Width: 9},
+			{Key: "tsallis_2", Title: "S2", Format: "%.4f", Width: 9},
+			{Key: "kl_uniform", Title: "D(p||u)", Format: "%.4f", Width: 9},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for i, n := range ns {
+		probs, err := source.spec.Generate(n, rng)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", i+1, err)
+		}
+		uniform := infotheory.UniformDistribution(n)
+
+		row := []any{i + 1, n}
+		for _, alpha := range []float64{1, 0, 0.5, 2, math.Inf(1)} {
+			h, err := infotheory.RenyiEntropy(probs, alpha, unit)
+			if err != nil {
+				return fmt.Errorf("эксперимент %d: %w", i+1, err)
+			}
+			row = append(row, h)
+		}
+		tsallis, err := infotheory.TsallisEntropy(probs, 2)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", i+1, err)
+		}
+		kl, err := infotheory.KLDivergence(probs, uniform, unit)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", i+1, err)
+		}
+		if err := out.WriteRow(append(row, tsallis, kl)...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runEstimate(path string, args []string) error {
+	fs := newFlagSet(path, "Сравнение оценок энтропии по выборке ограниченного объёма с истинной энтропией источника.")
+	ns := append(intList(nil), defaultAlphabets...)
+	fs.Var(&ns, "n", "размеры алфавита источника через запятую")
+	samples := fs.Int("samples", 50, "объём выборки")
+	resamples := fs.Int("bootstrap", 200, "число бутстрэп-выборок для доверительного интервала")
+	level := fs.Float64("level", 0.95, "доверительная вероятность интервала")
+	estimator := fs.String("estimator", "nsb", "оценка с доверительным интервалом: "+joinNames(infotheory.EstimatorNames))
+	source := addSourceFlag(fs)
+	newRand := addSeedFlag(fs)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("n", ns...); err != nil {
+		return err
+	}
+	if _, err := infotheory.ParseEstimator(*estimator, 1); err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	err = out.BeginTable(&report.Table{
+		Name:  "estimate",
+		Title: fmt.Sprintf("Оценки энтропии по выборке (интервал %g%% для оценки %s)", *level*100, *estimator),
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "samples", Title: "N", Width: 4},
+			{Key: "entropy", Title: "H", Format: "%.4f", Width: 8},
+			{Key: "plugin", Title: "Plug-in", Format: "%.4f", Width: 8},
+			{Key: "miller_madow", Title: "MM", Format: "%.4f", Width: 8},
+			{Key: "chao_shen", Title: "CS", Format: "%.4f", Width: 8},
+			{Key: "grassberger", Title: "G", Format: "%.4f", Width: 8},
+			{Key: "nsb", Title: "NSB", Format: "%.4f", Width: 8},
+			{Key: "estimate", Title: "Оценка H", Format: "%.4f", Width: 8},
+			{Key: "half_width", Title: "± Δ", Format: "%.4f", Width: 6},
+			{Key: "lower", Title: "Нижняя", Format: "%.4f", Width: 6},
+			{Key: "upper", Title: "Верхняя", Format: "%.4f", Width: 6},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for i, n := range ns {
+		probs, err := source.spec.Generate(n, rng)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", i+1, err)
+		}
+		counts, err := infotheory.Counts(infotheory.Sample(probs, *samples, rng), n)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", i+1, err)
+		}
+
+		estimators := []infotheory.EstimatorFunc{
+			infotheory.PluginEntropy,
+			infotheory.MillerMadowEntropy,
+			infotheory.ChaoShenEntropy,
+			infotheory.GrassbergerEntropy,
+			infotheory.NSBEntropy(n),
+		}
+		row := []any{i + 1, n, *samples, infotheory.Entropy(probs)}
+		for _, est := range estimators {
+			h, err := est(counts)
+			if err != nil {
+				return fmt.Errorf("эксперимент %d: %w", i+1, err)
+			}
+			row = append(row, h)
+		}
+
+		selected, err := infotheory.ParseEstimator(*estimator, n)
+		if err != nil {
+			return err
+		}
+		interval, err := infotheory.BootstrapInterval(counts, selected, *resamples, *level, rng)
+		if err != nil {
+			return fmt.Errorf("эксперимент %d: %w", i+1, err)
+		}
+		row = append(row, interval.Estimate, interval.HalfWidth(), interval.Lower, interval.Upper)
+		if err := out.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func runEmpirical(path string, args []string) error {
+	fs := newFlagSet(path, "Эмпирические энтропии порядков 0..order для файла или стандартного ввода.")
+	file := fs.String("file", "-", "файл для анализа (\"-\" — стандартный ввод)")
+	unitName := fs.String("unit", "bytes", "единица разбиения: bytes, runes или words")
+	order := fs.Int("order", 3, "максимальный порядок условной энтропии")
+	alphabet := fs.Int("alphabet", 0, "размер алфавита для расчёта избыточности (0 — число различных символов)")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	unit, err := infotheory.ParseTokenUnit(*unitName)
+	if err != nil {
+		return err
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	res, err := infotheory.AnalyzeReader(r, unit, *alphabet, *order)
+	if err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "empirical",
+		Title: fmt.Sprintf("Источник: %s, единица: %s", *file, res.Unit),
+		Columns: []report.Column{
+			{Key: "order", Title: "Порядок", Width: 7},
+			{Key: "blocks", Title: "Блоков", Width: 7},
+			{Key: "block_entropy", Title: "H блоков, бит", Format: "%.4f", Width: 13},
+			{Key: "conditional_entropy", Title: "H(X|k), бит", Format: "%.4f", Width: 11},
+			{Key: "redundancy", Title: "Избыточность", Format: "%.4f", Width: 12},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, o := range res.Orders {
+		if err := out.WriteRow(o.Order, o.Blocks, o.BlockEntropy, o.Conditional, o.Redundancy); err != nil {
+			return err
+		}
+	}
+	err = out.EndTable(
+		report.Field{Key: "tokens", Title: "Символов", Value: res.Tokens},
+		report.Field{Key: "observed", Title: "Различных символов", Value: res.Observed},
+		report.Field{Key: "alphabet", Title: "Алфавит", Value: res.Alphabet},
+		report.Field{Key: "max_entropy", Title: "Макс. H, бит", Value: res.MaxEntropy, Format: "%.4f"},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
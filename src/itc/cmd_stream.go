@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"itc/coding"
+	"itc/report"
+)
+
+// addStreamFlags добавляет флаги кодирования файлов --in и --out, если
+// семейство их поддерживает. Пустой --out означает обычный режим с одним словом.
+func (f codeFamily) addStreamFlags(fs *flag.FlagSet) (in, out *string) {
+	if !f.streams {
+		return new(string), new(string)
+	}
+	in = fs.String("in", "-", "входной файл для кодирования файлов (\"-\" — стандартный ввод)")
+	out = fs.String("out", "", "выходной файл; если задан, кодируется весь файл --in блоками по k бит,\n"+
+		"а при декодировании k берётся из заголовка файла")
+	return in, out
+}
+
+// openStreams открывает входной и выходной файлы потокового кодирования
+func openStreams(inPath, outPath string) (io.ReadCloser, *os.File, error) {
+	if outPath == "-" {
+		return nil, nil, fmt.Errorf("--out: в стандартный вывод печатается отчёт, укажите файл")
+	}
+	in := io.NopCloser(os.Stdin)
+	if inPath != "-" {
+		f, err := os.Open(inPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		in = f
+	}
+	out, err := os.Create(outPath)
+	if err != nil {
+		in.Close()
+		return nil, nil, err
+	}
+	return in, out, nil
+}
+
+// encodeFile кодирует файл inPath кодом SECDED с k информационными битами
+func encodeFile(out report.Writer, k int, inPath, outPath string) error {
+	in, file, err := openStreams(inPath, outPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	length, err := coding.EncodeSECDEDStream(file, in, k)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "stream_encode",
+		Title: fmt.Sprintf("Кодирование файла: SECDED, k = %d", k),
+		Columns: []report.Column{
+			{Key: "input", Title: "Входной файл"},
+			{Key: "output", Title: "Выходной файл"},
+			{Key: "input_bytes", Title: "Байт на входе", Format: "%d"},
+			{Key: "output_bytes", Title: "Байт на выходе", Format: "%d"},
+			{Key: "overhead", Title: "Рост размера", Format: "%.4f"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	overhead := 0.0
+	if length > 0 {
+		overhead = float64(info.Size()) / float64(length)
+	}
+	if err := out.WriteRow(inPath, outPath, length, info.Size(), overhead); err != nil {
+		return err
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// decodeFile декодирует файл inPath, записанный encodeFile, и выводит отчёт
+// о блоках с исправленными и неисправимыми ошибками
+func decodeFile(out report.Writer, inPath, outPath string) error {
+	in, file, err := openStreams(inPath, outPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	rep, err := coding.DecodeSECDEDStream(file, in)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	err = out.BeginTable(&report.Table{
+		Name:  "corruption",
+		Title: fmt.Sprintf("Повреждённые блоки: %s, SECDED, k = %d", inPath, rep.K),
+		Columns: []report.Column{
+			{Key: "block", Title: "Блок", Format: "%d"},
+			{Key: "offset", Title: "Смещение в файле", Format: "%d"},
+			{Key: "data_offset", Title: "Смещение данных"},
+			{Key: "status", Title: "Решение", Width: statusWidth},
+			{Key: "error_position", Title: "Позиция ошибки", Format: "%d"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, ev := range rep.Events {
+		dataOffset := "длина"
+		if ev.DataOffset >= 0 {
+			dataOffset = fmt.Sprint(ev.DataOffset)
+		}
+		if err := out.WriteRow(ev.Block, ev.Offset, dataOffset, ev.Status, ev.ErrorPos); err != nil {
+			return err
+		}
+	}
+	err = out.EndTable(
+		report.Field{Key: "blocks", Title: "Блоков", Value: rep.Blocks},
+		report.Field{Key: "bytes", Title: "Восстановлено байт", Value: rep.Bytes},
+		report.Field{Key: "corrected", Title: "Исправлено блоков", Value: rep.Corrected},
+		report.Field{Key: "detected", Title: "Обнаружено без исправления", Value: rep.Detected},
+	)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"itc/coding"
+	"itc/report"
+)
+
+func runTables(path string, args []string) error {
+	fs := newFlagSet(path, "Таблицы для кодирования и декодирования за O(1) на слово: байт данных -> проверочные биты,\n"+
+		"байт принятого слова -> вклад в синдром, синдром -> позиция ошибки. С флагом --lang таблицы\n"+
+		"выводятся исходным текстом на Go или C, без него — сводка размеров таблиц.")
+	codeSpec := fs.String("code", "secded:64", "код: hamming:k (систематический), positional:k или secded:k")
+	lang := fs.String("lang", "", "язык исходного текста: go или c (пусто — только сводка)")
+	outPath := fs.String("out", "-", "файл исходного текста (\"-\" — стандартный вывод)")
+	pkg := fs.String("package", "tables", "имя пакета Go")
+	prefix := fs.String("prefix", "", "префикс имён констант и массивов (пусто — семейство кода из --code)")
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	code, err := parseCodeSpec(*codeSpec)
+	if err != nil {
+		return fmt.Errorf("--code: %w", err)
+	}
+	t, err := coding.NewTables(code)
+	if err != nil {
+		return err
+	}
+
+	if *lang != "" {
+		l, err := coding.ParseTableLanguage(*lang)
+		if err != nil {
+			return fmt.Errorf("--lang: %w", err)
+		}
+		name := *prefix
+		if name == "" {
+			name, _, _ = strings.Cut(*codeSpec, ":")
+		}
+		if *outPath == "-" {
+			return t.WriteSource(os.Stdout, l, *pkg, name)
+		}
+		file, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		if err := t.WriteSource(file, l, *pkg, name); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	err = out.BeginTable(&report.Table{
+		Name:  "tables",
+		Title: fmt.Sprintf("Таблицы кода %s: n = %d, k = %d, r = %d", t.Name, t.N, t.K, t.R),
+		Columns: []report.Column{
+			{Key: "table", Title: "Таблица", Width: 8},
+			{Key: "index", Title: "Индекс", Width: 20},
+			{Key: "entries", Title: "Элементов", Format: "%d"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	rows := [][]any{
+		{"Check", "байт данных", len(t.CheckBytes) * 256},
+		{"Syndrome", "байт принятого слова", len(t.SyndromeBytes) * 256},
+		{"ErrorPos", "синдром", len(t.ErrorPos)},
+	}
+	for _, row := range rows {
+		if err := out.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"itc/coding"
+	"itc/plot"
+	"itc/report"
+)
+
+// turboFamily — турбокоды с итеративным декодированием BCJR
+var turboFamily = codeFamily{
+	name: "турбокод",
+	addFlags: func(fs *flag.FlagSet) func() (coding.LinearCode, error) {
+		newCode := addTurboFlags(fs, 40)
+		return func() (coding.LinearCode, error) { return newCode() }
+	},
+	minErrors:  0,
+	maxErrors:  4,
+	experiment: 10,
+}
+
+// turboCommands — подкоманды группы turbo
+func turboCommands() []*command {
+	cmds := codeCommands(turboFamily)
+	return append(cmds, &command{name: "ber", summary: "BER и FER после каждой итерации декодера в каналах AWGN и ДСК (турбо-обрыв)", run: runTurboBER})
+}
+
+// addTurboFlags добавляет флаги составляющего кодера, перемежителя и
+// декодера; kDefault — число информационных бит по умолчанию
+func addTurboFlags(fs *flag.FlagSet, kDefault int) func() (*coding.Turbo, error) {
+	def := coding.DefaultTurboDecoder()
+	k := fs.Int("k", kDefault, "число информационных бит (длина перемежителя)")
+	feedback := fs.String("feedback", "13", "полином обратной связи RSC-кодера, восьмеричный")
+	forward := fs.String("forward", "15", "полином прямой ветви RSC-кодера, восьмеричный")
+	interleaver := fs.String("interleaver", "srandom", "перемежитель: random, block:строк, srandom[:S] или qpp:f1:f2")
+	seed := fs.Int64("interleaver-seed", 1, "зерно генератора случайного перемежителя")
+	puncture := fs.Bool("puncture", false, "выкалывать проверочные биты поочерёдно (скорость 1/2 вместо 1/3)")
+	algorithm := fs.String("algorithm", def.Algorithm.String(), "алгоритм составляющих декодеров: log-map или max-log-map")
+	iterations := fs.Int("iterations", def.MaxIterations, "наибольшее число итераций")
+	crossover := fs.Float64("crossover", def.Crossover, "вероятность ошибки ДСК для перевода принятых бит в LLR")
+	return func() (*coding.Turbo, error) {
+		if err := checkPositive("k", *k); err != nil {
+			return nil, err
+		}
+		rsc, err := coding.NewRSC(*feedback, *forward)
+		if err != nil {
+			return nil, fmt.Errorf("--feedback и --forward: %w", err)
+		}
+		perm, err := coding.ParseInterleaver(*interleaver, *k, rand.New(rand.NewSource(*seed)))
+		if err != nil {
+			return nil, fmt.Errorf("--interleaver: %w", err)
+		}
+		code, err := coding.NewTurbo(rsc, perm, *puncture)
+		if err != nil {
+			return nil, err
+		}
+		d := coding.TurboDecoder{MaxIterations: *iterations, Crossover: *crossover}
+		if d.Algorithm, err = coding.ParseBCJRAlgorithm(*algorithm); err != nil {
+			return nil, fmt.Errorf("--algorithm: %w", err)
+		}
+		if err := code.SetDecoder(d); err != nil {
+			return nil, err
+		}
+		return code, nil
+	}
+}
+
+func runTurboBER(path string, args []string) error {
+	fs := newFlagSet(path, "Моделирование турбокода: доля ошибочных бит (BER) и слов (FER) после каждой итерации\n"+
+		"декодера в гауссовском канале с двоичной фазовой модуляцией (по Eb/N0) или в двоичном\n"+
+		"симметричном канале (по вероятности ошибки p). С ростом числа итераций кривая BER\n"+
+		"круто падает начиная с некоторого отношения сигнал/шум — турбо-обрыв.")
+	newCode := addTurboFlags(fs, 512)
+	channel := fs.String("channel", "awgn", "канал: awgn или bsc")
+	ebn0Min := fs.Float64("ebn0-min", 0, "awgn: наименьшее Eb/N0, дБ")
+	ebn0Max := fs.Float64("ebn0-max", 2, "awgn: наибольшее Eb/N0, дБ")
+	ebn0Step := fs.Float64("ebn0-step", 0.25, "awgn: шаг по Eb/N0, дБ")
+	pMin := fs.Float64("p-min", 0.04, "bsc: наименьшая вероятность ошибки")
+	pMax := fs.Float64("p-max", 0.12, "bsc: наибольшая вероятность ошибки")
+	points := fs.Int("points", 6, "bsc: число точек (равномерно по логарифмической шкале)")
+	words := fs.Int("words", 200, "число слов в каждой точке")
+	newRand := addSeedFlag(fs)
+	save := addPlotFlags(fs, "turbo_ber.svg", false, true)
+	newOutput := addFormatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkPositive("words", *words); err != nil {
+		return err
+	}
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+
+	var xs []float64
+	var xKey, xTitle, xLabel string
+	var uncoded func(x float64) float64
+	switch *channel {
+	case "awgn":
+		if xs, err = grid(*ebn0Min, *ebn0Max, *ebn0Step); err != nil {
+			return fmt.Errorf("--ebn0-min, --ebn0-max и --ebn0-step: %w", err)
+		}
+		xKey, xTitle, xLabel = "ebn0", "Eb/N0, дБ", "Eb/N0, дБ"
+		uncoded = coding.UncodedAWGNBER
+	case "bsc":
+		if err := checkRange(*pMin, *pMax); err != nil {
+			return fmt.Errorf("--p-min и --p-max: %w", err)
+		}
+		if *pMax >= 0.5 {
+			return fmt.Errorf("--p-max: вероятность ошибки должна быть меньше 0.5, получено %g", *pMax)
+		}
+		if xs, err = logGrid(*pMin, *pMax, *points); err != nil {
+			return fmt.Errorf("--p-min, --p-max и --points: %w", err)
+		}
+		xKey, xTitle, xLabel = "p", "p", "Вероятность ошибки в канале p"
+		uncoded = func(p float64) float64 { return p }
+	default:
+		return fmt.Errorf("--channel: неизвестный канал %q (ожидается awgn или bsc)", *channel)
+	}
+	out, err := newOutput()
+	if err != nil {
+		return err
+	}
+	rng := newRand()
+
+	d := code.Decoder()
+	err = out.BeginTable(&report.Table{
+		Name: "turbo_ber",
+		Title: fmt.Sprintf("%s, %s, канал %s, %d слов в каждой точке",
+			code.Name(), d.Algorithm, strings.ToUpper(*channel), *words),
+		Columns: []report.Column{
+			{Key: xKey, Title: xTitle, Format: "%.3g", Width: 9},
+			{Key: "iteration", Title: "Итерация", Width: 8},
+			{Key: "ber", Title: "BER", Format: "%.3e", Width: 9},
+			{Key: "fer", Title: "FER", Format: "%.3e"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	reference := plot.Series{Name: "без кодирования", X: xs}
+	for _, x := range xs {
+		reference.Y = append(reference.Y, uncoded(x))
+	}
+	series := make([]plot.Series, d.MaxIterations)
+	for it := range series {
+		series[it].Name = fmt.Sprintf("итерация %d", it+1)
+	}
+	rate := float64(code.Dimension()) / float64(code.Length())
+	for _, x := range xs {
+		if *channel == "bsc" {
+			d.Crossover = x
+			if err := code.SetDecoder(d); err != nil {
+				return err
+			}
+		}
+		// Ошибки после каждой итерации по одним и тем же принятым словам
+		rates := make([]coding.ErrorRates, d.MaxIterations)
+		for i := 0; i < *words; i++ {
+			data := coding.RandomBits(code.Dimension(), rng)
+			word, err := code.Encode(data)
+			if err != nil {
+				return err
+			}
+			var llr []float64
+			if *channel == "bsc" {
+				received, _ := coding.TransmitBSC(word, x, rng)
+				llr = coding.BSCLLR(received, x)
+			} else {
+				llr = coding.TransmitAWGN(word, coding.AWGNSigma(x, rate), rng)
+			}
+			trace, err := code.DecodeTrace(llr)
+			if err != nil {
+				return err
+			}
+			for it, estimate := range trace {
+				wrong := 0
+				for j := range data {
+					if data[j] != estimate[j] {
+						wrong++
+					}
+				}
+				rates[it].Words++
+				rates[it].DataBits += len(data)
+				rates[it].BitErrors += wrong
+				if wrong > 0 {
+					rates[it].FrameErrors++
+				}
+			}
+		}
+		for it, r := range rates {
+			if err := out.WriteRow(x, it+1, r.BER(), r.FER()); err != nil {
+				return err
+			}
+			series[it].X = append(series[it].X, x)
+			series[it].Y = append(series[it].Y, r.BER())
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return save(&plot.Chart{
+		Title:  "Помехоустойчивость " + code.Name() + " по итерациям",
+		XLabel: xLabel,
+		YLabel: "BER",
+		Series: append([]plot.Series{reference}, series...),
+	})
+}
@@ -0,0 +1,164 @@
+package coding
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Формат alist (D. MacKay) описывает разреженную матрицу H размера m x n:
+//
+//	n m
+//	наибольшая степень столбца, наибольшая степень строки
+//	степени n столбцов
+//	степени m строк
+//	n строк: номера строк H (с 1) с единицами в столбце
+//	m строк: номера столбцов H (с 1) с единицами в строке
+//
+// Списки короче наибольшей степени дополняются нулями; при чтении нули
+// пропускаются, поэтому принимаются файлы и с дополнением, и без него.
+
+// ReadAlist читает проверочную матрицу в формате alist и строит LDPC-код
+func ReadAlist(r io.Reader) (*LDPC, error) {
+	lines, err := alistLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) < 4 || len(lines[0]) != 2 || len(lines[1]) != 2 {
+		return nil, fmt.Errorf("alist: ожидаются строки с размерами и наибольшими степенями")
+	}
+	n, m := lines[0][0], lines[0][1]
+	if n <= 0 || m <= 0 {
+		return nil, fmt.Errorf("alist: размеры матрицы %d x %d должны быть положительными", m, n)
+	}
+	if len(lines) != 4+n+m {
+		return nil, fmt.Errorf("alist: ожидалось %d непустых строк, прочитано %d", 4+n+m, len(lines))
+	}
+	colDeg, rowDeg := lines[2], lines[3]
+	if len(colDeg) != n || len(rowDeg) != m {
+		return nil, fmt.Errorf("alist: ожидалось %d степеней столбцов и %d степеней строк, прочитано %d и %d",
+			n, m, len(colDeg), len(rowDeg))
+	}
+
+	cols := make([]map[int]bool, n)
+	for j := 0; j < n; j++ {
+		list, err := alistList(lines[4+j], colDeg[j], m, "столбец", j)
+		if err != nil {
+			return nil, err
+		}
+		cols[j] = make(map[int]bool, len(list))
+		for _, i := range list {
+			cols[j][i] = true
+		}
+	}
+	checks := make([][]int, m)
+	edges := 0
+	for i := 0; i < m; i++ {
+		list, err := alistList(lines[4+n+i], rowDeg[i], n, "строка", i)
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range list {
+			if !cols[j][i] {
+				return nil, fmt.Errorf("alist: строка %d содержит столбец %d, но столбец %d не содержит строку %d", i+1, j+1, j+1, i+1)
+			}
+		}
+		checks[i] = list
+		edges += len(list)
+	}
+	total := 0
+	for _, d := range colDeg {
+		total += d
+	}
+	if total != edges {
+		return nil, fmt.Errorf("alist: сумма степеней столбцов %d не равна сумме степеней строк %d", total, edges)
+	}
+	return NewLDPC(n, checks)
+}
+
+// alistLines разбивает текст на непустые строки целых чисел
+func alistLines(r io.Reader) ([][]int, error) {
+	var lines [][]int
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for num := 1; sc.Scan(); num++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		line := make([]int, len(fields))
+		for t, f := range fields {
+			v, err := strconv.Atoi(f)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("alist: строка %d: ожидается неотрицательное целое, получено %q", num, f)
+			}
+			line[t] = v
+		}
+		lines = append(lines, line)
+	}
+	return lines, sc.Err()
+}
+
+// alistList проверяет список номеров (с 1) длины degree и переводит их в номера с 0
+func alistList(line []int, degree, limit int, what string, index int) ([]int, error) {
+	var list []int
+	for _, v := range line {
+		if v == 0 {
+			continue
+		}
+		if v > limit {
+			return nil, fmt.Errorf("alist: %s %d: номер %d вне диапазона 1..%d", what, index+1, v, limit)
+		}
+		list = append(list, v-1)
+	}
+	if len(list) != degree {
+		return nil, fmt.Errorf("alist: %s %d: указано %d номеров, а степень равна %d", what, index+1, len(list), degree)
+	}
+	return list, nil
+}
+
+// WriteAlist записывает проверочную матрицу кода в формате alist; списки
+// дополняются нулями до наибольшей степени
+func (c *LDPC) WriteAlist(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	maxCol, maxRow := 0, 0
+	for _, v := range c.vars {
+		maxCol = max(maxCol, len(v))
+	}
+	for _, row := range c.checks {
+		maxRow = max(maxRow, len(row))
+	}
+	writeList := func(values []int, width int, shift int) {
+		for t := 0; t < width; t++ {
+			if t > 0 {
+				bw.WriteByte(' ')
+			}
+			if t < len(values) {
+				bw.WriteString(strconv.Itoa(values[t] + shift))
+			} else {
+				bw.WriteByte('0')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	fmt.Fprintf(bw, "%d %d\n%d %d\n", c.n, len(c.checks), maxCol, maxRow)
+	colDeg := make([]int, c.n)
+	for j, v := range c.vars {
+		colDeg[j] = len(v)
+	}
+	rowDeg := make([]int, len(c.checks))
+	for i, row := range c.checks {
+		rowDeg[i] = len(row)
+	}
+	writeList(colDeg, len(colDeg), 0)
+	writeList(rowDeg, len(rowDeg), 0)
+	for _, v := range c.vars {
+		writeList(v, maxCol, 1)
+	}
+	for _, row := range c.checks {
+		writeList(row, maxRow, 1)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,166 @@
+// Package coding содержит помехоустойчивые коды лабораторных работ: коды
+// Хэмминга в систематической (лабораторная работа 4) и позиционной
+// (лабораторная работа 5) формах, а также расширенный код SECDED.
+//
+// Двоичные векторы представлены срезами []int из нулей и единиц, как в
+// исходных лабораторных работах.
+package coding
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// RandomBits генерирует случайный массив из length бит (0 или 1)
+func RandomBits(length int, rng *rand.Rand) []int {
+	v := make([]int, length)
+	for i := range v {
+		v[i] = rng.Intn(2)
+	}
+	return v
+}
+
+// InjectErrors инвертирует count различных случайных бит слова и возвращает
+// искажённую копию и позиции ошибок (с 1) в порядке возрастания
+func InjectErrors(word []int, count int, rng *rand.Rand) ([]int, []int, error) {
+	if count < 0 || count > len(word) {
+		return nil, nil, fmt.Errorf("число ошибок %d вне диапазона 0..%d", count, len(word))
+	}
+	noisy := append([]int(nil), word...)
+	positions := rng.Perm(len(word))[:count]
+	for i := range positions {
+		positions[i]++
+		noisy[positions[i]-1] ^= 1
+	}
+	sort.Ints(positions)
+	return noisy, positions, nil
+}
+
+// TransmitBSC передаёт слово через двоичный симметричный канал: каждый бит
+// независимо инвертируется с вероятностью p. Возвращает искажённую копию и
+// число ошибок.
+func TransmitBSC(word []int, p float64, rng *rand.Rand) ([]int, int) {
+	noisy := append([]int(nil), word...)
+	errors := 0
+	for i := range noisy {
+		if rng.Float64() < p {
+			noisy[i] ^= 1
+			errors++
+		}
+	}
+	return noisy, errors
+}
+
+// ParseBits преобразует строку из 0 и 1 в массив бит. Пробелы игнорируются.
+func ParseBits(s string) ([]int, error) {
+	bits := make([]int, 0, len(s))
+	for i, r := range s {
+		switch r {
+		case '0':
+			bits = append(bits, 0)
+		case '1':
+			bits = append(bits, 1)
+		case ' ', '_':
+		default:
+			return nil, fmt.Errorf("недопустимый символ %q в позиции %d: ожидаются 0 и 1", r, i)
+		}
+	}
+	return bits, nil
+}
+
+// BitsToString преобразует массив бит в строку из 0 и 1
+func BitsToString(bits []int) string {
+	var sb strings.Builder
+	sb.Grow(len(bits))
+	for _, b := range bits {
+		sb.WriteByte(byte('0' + b))
+	}
+	return sb.String()
+}
+
+// BitsToSpacedString превращает массив бит в строку вида "1 0 1 1" — с пробелами
+func BitsToSpacedString(bits []int) string {
+	strs := make([]string, len(bits))
+	for i, b := range bits {
+		strs[i] = string(byte('0' + b))
+	}
+	return strings.Join(strs, " ")
+}
+
+// BitsEqual сравнивает два массива бит на полное совпадение
+func BitsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkBits проверяет длину вектора и то, что он состоит из 0 и 1
+func checkBits(what string, bits []int, length int) error {
+	if len(bits) != length {
+		return fmt.Errorf("%s: ожидалось %d бит, получено %d", what, length, len(bits))
+	}
+	for i, b := range bits {
+		if b != 0 && b != 1 {
+			return fmt.Errorf("%s: бит %d равен %d, а не 0 или 1", what, i, b)
+		}
+	}
+	return nil
+}
+
+// newMatrix создаёт двумерный срез (матрицу) размера r x c, заполненный нулями
+func newMatrix(r, c int) [][]int {
+	m := make([][]int, r)
+	for i := range m {
+		m[i] = make([]int, c)
+	}
+	return m
+}
+
+// copyMatrix возвращает копию матрицы
+func copyMatrix(m [][]int) [][]int {
+	res := make([][]int, len(m))
+	for i, row := range m {
+		res[i] = append([]int(nil), row...)
+	}
+	return res
+}
+
+// boolToInt преобразует bool в 0 или 1
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Multiply выполняет умножение матрицы M на вектор vec по модулю 2 (XOR)
+func Multiply(M [][]int, vec []int) []int {
+	s := make([]int, len(M))
+	for i, row := range M {
+		acc := 0
+		for j, v := range row {
+			acc ^= v & vec[j]
+		}
+		s[i] = acc & 1
+	}
+	return s
+}
+
+// SyndromeIndex преобразует синдром (массив бит, младший бит первый) в число
+func SyndromeIndex(s []int) int {
+	val := 0
+	for i, bit := range s {
+		if bit == 1 {
+			val |= 1 << i
+		}
+	}
+	return val
+}
@@ -0,0 +1,234 @@
+package coding
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// BitVec — двоичный вектор, упакованный в машинные слова по 64 бита.
+// Бит i хранится в разряде i%64 слова i/64; разряды за пределами длины
+// всегда равны нулю, поэтому слова можно сравнивать и считать единицы
+// без маскирования.
+type BitVec struct {
+	n     int
+	words []uint64
+}
+
+// NewBitVec создаёт нулевой вектор длины n
+func NewBitVec(n int) BitVec {
+	return BitVec{n: n, words: make([]uint64, (n+63)/64)}
+}
+
+// BitVecFromBits упаковывает массив бит; элементы, отличные от нуля, считаются единицами
+func BitVecFromBits(b []int) BitVec {
+	v := NewBitVec(len(b))
+	for i, bit := range b {
+		if bit != 0 {
+			v.words[i/64] |= 1 << (i % 64)
+		}
+	}
+	return v
+}
+
+// ParseBitVec преобразует строку из 0 и 1 в вектор. Пробелы игнорируются.
+func ParseBitVec(s string) (BitVec, error) {
+	b, err := ParseBits(s)
+	if err != nil {
+		return BitVec{}, err
+	}
+	return BitVecFromBits(b), nil
+}
+
+// RandomBitVec генерирует случайный вектор длины n
+func RandomBitVec(n int, rng *rand.Rand) BitVec {
+	v := NewBitVec(n)
+	for i := range v.words {
+		v.words[i] = rng.Uint64()
+	}
+	v.trim()
+	return v
+}
+
+// trim обнуляет разряды последнего слова за пределами длины
+func (v BitVec) trim() {
+	if r := v.n % 64; r != 0 {
+		v.words[len(v.words)-1] &= 1<<r - 1
+	}
+}
+
+// Len возвращает длину вектора в битах
+func (v BitVec) Len() int { return v.n }
+
+// Words возвращает слова вектора (не копию)
+func (v BitVec) Words() []uint64 { return v.words }
+
+// Clone возвращает копию вектора
+func (v BitVec) Clone() BitVec {
+	return BitVec{n: v.n, words: append([]uint64(nil), v.words...)}
+}
+
+// Bit возвращает бит i (0 или 1)
+func (v BitVec) Bit(i int) int {
+	return int(v.words[i/64]>>(i%64)) & 1
+}
+
+// Set устанавливает бит i в значение b (0 или 1)
+func (v BitVec) Set(i, b int) {
+	if b != 0 {
+		v.words[i/64] |= 1 << (i % 64)
+	} else {
+		v.words[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Flip инвертирует бит i
+func (v BitVec) Flip(i int) {
+	v.words[i/64] ^= 1 << (i % 64)
+}
+
+// Uint64 возвращает width <= 64 бит, начиная с бита off; бит off — младший разряд результата
+func (v BitVec) Uint64(off, width int) uint64 {
+	if width == 0 {
+		return 0
+	}
+	w, s := off/64, off%64
+	x := v.words[w] >> s
+	if s+width > 64 {
+		x |= v.words[w+1] << (64 - s)
+	}
+	if width < 64 {
+		x &= 1<<width - 1
+	}
+	return x
+}
+
+// SetUint64 записывает младшие width <= 64 бит x в биты off..off+width-1
+func (v BitVec) SetUint64(off, width int, x uint64) {
+	if width == 0 {
+		return
+	}
+	mask := ^uint64(0)
+	if width < 64 {
+		mask = 1<<width - 1
+	}
+	x &= mask
+	w, s := off/64, off%64
+	v.words[w] = v.words[w]&^(mask<<s) | x<<s
+	if s+width > 64 {
+		v.words[w+1] = v.words[w+1]&^(mask>>(64-s)) | x>>(64-s)
+	}
+}
+
+// Xor прибавляет к вектору u по модулю 2; длины должны совпадать
+func (v BitVec) Xor(u BitVec) {
+	for i, w := range u.words {
+		v.words[i] ^= w
+	}
+}
+
+// OnesCount возвращает число единиц (вес Хэмминга)
+func (v BitVec) OnesCount() int {
+	count := 0
+	for _, w := range v.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Parity возвращает чётность числа единиц (0 или 1)
+func (v BitVec) Parity() int {
+	var acc uint64
+	for _, w := range v.words {
+		acc ^= w
+	}
+	return bits.OnesCount64(acc) & 1
+}
+
+// AndParity возвращает скалярное произведение векторов по модулю 2 —
+// чётность числа единиц в v AND u, не создавая промежуточный вектор
+func (v BitVec) AndParity(u BitVec) int {
+	var acc uint64
+	for i, w := range u.words {
+		acc ^= v.words[i] & w
+	}
+	return bits.OnesCount64(acc) & 1
+}
+
+// Equal сравнивает векторы на полное совпадение
+func (v BitVec) Equal(u BitVec) bool {
+	if v.n != u.n {
+		return false
+	}
+	for i, w := range v.words {
+		if u.words[i] != w {
+			return false
+		}
+	}
+	return true
+}
+
+// Bits распаковывает вектор в массив бит
+func (v BitVec) Bits() []int {
+	b := make([]int, v.n)
+	for i := range b {
+		b[i] = v.Bit(i)
+	}
+	return b
+}
+
+// String возвращает вектор строкой из 0 и 1, бит 0 первым
+func (v BitVec) String() string {
+	var sb strings.Builder
+	sb.Grow(v.n)
+	for i := 0; i < v.n; i++ {
+		sb.WriteByte(byte('0' + v.Bit(i)))
+	}
+	return sb.String()
+}
+
+// checkLen проверяет длину вектора
+func (v BitVec) checkLen(what string, length int) error {
+	if v.n != length {
+		return fmt.Errorf("%s: ожидалось %d бит, получено %d", what, length, v.n)
+	}
+	return nil
+}
+
+// InjectErrorsVec инвертирует count различных случайных бит вектора и
+// возвращает искажённую копию и позиции ошибок (с 1) — упакованный вариант InjectErrors
+func InjectErrorsVec(word BitVec, count int, rng *rand.Rand) (BitVec, []int, error) {
+	if count < 0 || count > word.n {
+		return BitVec{}, nil, fmt.Errorf("число ошибок %d вне диапазона 0..%d", count, word.n)
+	}
+	noisy := word.Clone()
+	positions := make([]int, 0, count)
+	// Выбор без повторений: для малого числа ошибок это быстрее rng.Perm
+	for len(positions) < count {
+		pos := rng.Intn(word.n)
+		if noisy.Bit(pos) != word.Bit(pos) {
+			continue
+		}
+		noisy.Flip(pos)
+		positions = append(positions, pos+1)
+	}
+	sort.Ints(positions)
+	return noisy, positions, nil
+}
+
+// TransmitBSCVec передаёт вектор через двоичный симметричный канал с
+// вероятностью ошибки p — упакованный вариант TransmitBSC. Возвращает
+// искажённую копию и число ошибок.
+func TransmitBSCVec(word BitVec, p float64, rng *rand.Rand) (BitVec, int) {
+	noisy := word.Clone()
+	errors := 0
+	for i := 0; i < word.n; i++ {
+		if rng.Float64() < p {
+			noisy.Flip(i)
+			errors++
+		}
+	}
+	return noisy, errors
+}
@@ -0,0 +1,208 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Bound — граница наибольшего числа слов A_q(n,d) кода длины n с
+// минимальным расстоянием d над алфавитом из q символов
+type Bound struct {
+	Name  string
+	Upper bool     // true — верхняя граница (код большего размера не существует), false — нижняя (код такого размера существует)
+	Size  *big.Int // граница числа слов
+}
+
+// Dimension возвращает наибольшее k, для которого q^k не превосходит
+// границы: для верхней границы — наибольшее возможное число информационных
+// символов, для нижней — гарантированное
+func (b Bound) Dimension(q int) int {
+	k := 0
+	for p := big.NewInt(int64(q)); p.Cmp(b.Size) <= 0; p.Mul(p, big.NewInt(int64(q))) {
+		k++
+	}
+	return k
+}
+
+// Log возвращает log_q размера границы — «число информационных символов»
+// с дробной частью
+func (b Bound) Log(q int) float64 {
+	return bigLog2(b.Size) / math.Log2(float64(q))
+}
+
+// bigLog2 возвращает log2 положительного большого целого
+func bigLog2(a *big.Int) float64 {
+	shift := max(a.BitLen()-53, 0)
+	top, _ := new(big.Int).Rsh(a, uint(shift)).Float64()
+	return float64(shift) + math.Log2(top)
+}
+
+// checkBoundParams проверяет параметры n, d и q
+func checkBoundParams(n, d, q int) error {
+	if q < 2 {
+		return fmt.Errorf("размер алфавита q = %d, ожидается не меньше 2", q)
+	}
+	if n < 1 || d < 1 || d > n {
+		return fmt.Errorf("ожидается 1 <= d <= n, получено n = %d, d = %d", n, d)
+	}
+	return nil
+}
+
+// bigPow возвращает q^e
+func bigPow(q, e int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(int64(q)), big.NewInt(int64(e)), nil)
+}
+
+// SphereVolume возвращает число слов в шаре радиуса r пространства длины n
+// над алфавитом из q символов: V = Σ C(n,i)(q-1)^i, i = 0..r
+func SphereVolume(n, r, q int) *big.Int {
+	v := new(big.Int)
+	term := big.NewInt(1) // C(n,i)(q-1)^i
+	for i := 0; i <= min(r, n); i++ {
+		v.Add(v, term)
+		term.Mul(term, big.NewInt(int64((n-i)*(q-1))))
+		term.Quo(term, big.NewInt(int64(i+1)))
+	}
+	return v
+}
+
+// CodeBounds возвращает стандартные границы A_q(n,d): верхние Хэмминга
+// (упаковки шаров), Синглтона, Плоткина и Элайеса — Бассалыго и нижние
+// Гилберта и Варшамова (для линейных кодов)
+func CodeBounds(n, d, q int) ([]Bound, error) {
+	if err := checkBoundParams(n, d, q); err != nil {
+		return nil, err
+	}
+	qn := bigPow(q, n)
+	t := (d - 1) / 2
+	hamming := new(big.Int).Quo(qn, SphereVolume(n, t, q))
+	singleton := bigPow(q, n-d+1)
+
+	// Плоткин: при θl < d, θ = (q-1)/q, A_q(l,d) <= d/(d - θl) = qd/(qd - (q-1)l);
+	// l — наибольшая такая длина, не больше n, и A_q(n,d) <= q^(n-l)·A_q(l,d)
+	l := min(n, (q*d-1)/(q-1))
+	plotkin := new(big.Int).Mul(bigPow(q, n-l), big.NewInt(int64(q*d/(q*d-(q-1)*l))))
+
+	// Элайес — Бассалыго: A <= θnd/(w² - 2θnw + θnd)·q^n/V(n,w) при w <= θn и
+	// положительном знаменателе; после умножения на q все величины целые
+	var elias *big.Int
+	for w := 0; q*w <= (q-1)*n; w++ {
+		den := q*w*w - 2*(q-1)*n*w + (q-1)*n*d
+		if den <= 0 {
+			continue
+		}
+		num := new(big.Int).Mul(big.NewInt(int64((q-1)*n*d)), qn)
+		b := num.Quo(num, new(big.Int).Mul(big.NewInt(int64(den)), SphereVolume(n, w, q)))
+		if elias == nil || b.Cmp(elias) < 0 {
+			elias = b
+		}
+	}
+
+	// Гилберт: жадный выбор слов на расстоянии не меньше d даёт
+	// A >= ⌈q^n / V(n, d-1)⌉
+	volume := SphereVolume(n, d-1, q)
+	gilbert := new(big.Int).Quo(new(big.Int).Add(qn, new(big.Int).Sub(volume, big.NewInt(1))), volume)
+
+	bounds := []Bound{
+		{Name: "Хэмминг", Upper: true, Size: hamming},
+		{Name: "Синглтон", Upper: true, Size: singleton},
+		{Name: "Плоткин", Upper: true, Size: plotkin},
+		{Name: "Элайес — Бассалыго", Upper: true, Size: elias},
+		{Name: "Гилберт", Upper: false, Size: gilbert},
+		{Name: "Варшамов (линейные коды)", Upper: false, Size: bigPow(q, VarshamovDimension(n, d, q))},
+	}
+	return bounds, nil
+}
+
+// VarshamovDimension возвращает наибольшее k, при котором линейный код
+// [n,k,d] над GF(q) заведомо существует: столбцы проверочной матрицы можно
+// выбирать по одному так, чтобы любые d-1 из них были независимы, пока
+// q^(n-k) > V(n-1, d-2)
+func VarshamovDimension(n, d, q int) int {
+	if d == 1 {
+		return n
+	}
+	volume := SphereVolume(n-1, d-2, q)
+	r := 0
+	for bigPow(q, r).Cmp(volume) <= 0 {
+		r++
+	}
+	return max(n-r, 0)
+}
+
+// Redundancy — оценки наименьшего числа проверочных символов r = n - k
+// кода с k информационными символами, исправляющего t ошибок
+type Redundancy struct {
+	Hamming   int // необходимо: q^r >= V(k+r, t)
+	Singleton int // необходимо: r >= 2t
+	Varshamov int // достаточно для линейного кода: q^r > V(k+r-1, 2t-1)
+}
+
+// Lower возвращает наибольшую из необходимых оценок
+func (r Redundancy) Lower() int { return max(r.Hamming, r.Singleton) }
+
+// MinRedundancy оценивает наименьшее число проверочных символов для k
+// информационных символов и t исправляемых ошибок. При q = 2 и t = 1 оценка
+// Хэмминга совпадает с CalculateN(k) - k лабораторной работы 4 и с MinP(k)
+// лабораторной работы 5 без общего паритетного бита.
+func MinRedundancy(k, t, q int) (Redundancy, error) {
+	if k < 1 || t < 0 || q < 2 {
+		return Redundancy{}, fmt.Errorf("ожидается k >= 1, t >= 0, q >= 2, получено k = %d, t = %d, q = %d", k, t, q)
+	}
+	res := Redundancy{Singleton: 2 * t}
+	for bigPow(q, res.Hamming).Cmp(SphereVolume(k+res.Hamming, t, q)) < 0 {
+		res.Hamming++
+	}
+	if t > 0 {
+		for bigPow(q, res.Varshamov).Cmp(SphereVolume(k+res.Varshamov-1, 2*t-1, q)) <= 0 {
+			res.Varshamov++
+		}
+	}
+	return res, nil
+}
+
+// Feasibility — вывод о существовании кода с заданными параметрами
+type Feasibility int
+
+const (
+	FeasibilityUnknown Feasibility = iota // границы не дают ответа
+	Feasible                              // код существует по нижней границе
+	Infeasible                            // код не существует по верхней границе
+)
+
+func (f Feasibility) String() string {
+	switch f {
+	case Feasible:
+		return "существует"
+	case Infeasible:
+		return "не существует"
+	}
+	return "не определено границами"
+}
+
+// CheckCode проверяет, существует ли код (n,k,d) из q^k слов: код
+// невозможен, если q^k превышает какую-либо верхнюю границу, и заведомо
+// существует, если q^k не превышает нижнюю. Возвращает вывод и название
+// решившей границы.
+func CheckCode(n, k, d, q int) (Feasibility, string, error) {
+	if k < 0 || k > n {
+		return FeasibilityUnknown, "", fmt.Errorf("ожидается 0 <= k <= n, получено n = %d, k = %d", n, k)
+	}
+	bounds, err := CodeBounds(n, d, q)
+	if err != nil {
+		return FeasibilityUnknown, "", err
+	}
+	size := bigPow(q, k)
+	for _, b := range bounds {
+		if b.Upper && size.Cmp(b.Size) > 0 {
+			return Infeasible, b.Name, nil
+		}
+	}
+	for _, b := range bounds {
+		if !b.Upper && size.Cmp(b.Size) <= 0 {
+			return Feasible, b.Name, nil
+		}
+	}
+	return FeasibilityUnknown, "", nil
+}
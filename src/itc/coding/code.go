@@ -0,0 +1,169 @@
+package coding
+
+import "fmt"
+
+// DecodeStatus — итог декодирования принятого слова
+type DecodeStatus int
+
+const (
+	StatusOK        DecodeStatus = iota // ошибок нет
+	StatusCorrected                     // ошибка обнаружена и исправлена
+	StatusDetected                      // ошибка обнаружена, но исправить её нельзя
+)
+
+// String возвращает описание итога декодирования
+func (s DecodeStatus) String() string {
+	switch s {
+	case StatusOK:
+		return "ошибок нет"
+	case StatusCorrected:
+		return "ошибка исправлена"
+	case StatusDetected:
+		return "ошибка обнаружена, исправить нельзя"
+	}
+	return "неизвестный итог"
+}
+
+// DecodeResult — результат декодирования одного принятого слова
+type DecodeResult struct {
+	Data     []int        // восстановленные информационные биты
+	Codeword []int        // исправленное кодовое слово
+	Syndrome []int        // синдром принятого слова
+	ErrorPos int          // позиция исправленной ошибки (с 1), 0 — ошибка не исправлялась
+	Status   DecodeStatus // итог декодирования
+
+	// ErrorPositions — все исправленные позиции (с 1) для кодов, исправляющих
+	// несколько ошибок; у кодов Хэмминга не заполняется
+	ErrorPositions []int
+	// Iterations — число итераций итеративного декодера (LDPC); у остальных 0
+	Iterations int
+}
+
+// BlockCode — двоичный блочный код: k информационных бит кодируются
+// словом из n бит
+type BlockCode interface {
+	Name() string
+	Length() int    // n — длина кодового слова
+	Dimension() int // k — число информационных бит
+	Encode(data []int) ([]int, error)
+	Decode(received []int) (DecodeResult, error)
+}
+
+// LinearCode — линейный блочный код, заданный производящей и проверочной
+// матрицами
+type LinearCode interface {
+	BlockCode
+	GeneratorMatrix() [][]int   // G размера k x n
+	ParityCheckMatrix() [][]int // H размера (n-k) x n
+}
+
+// CorrectableErrors возвращает число ошибок t, которые исправляет код. Коды,
+// исправляющие больше одной ошибки, сообщают его методом CorrectableErrors,
+// для остальных (коды Хэмминга) t = 1.
+func CorrectableErrors(code BlockCode) int {
+	if c, ok := code.(interface{ CorrectableErrors() int }); ok {
+		return c.CorrectableErrors()
+	}
+	return 1
+}
+
+// checkSingleError проверяет, что код исправляет одну ошибку: таблицы
+// синдромов, упакованный декодер и модули HDL сопоставляют синдрому столбец H
+func checkSingleError(code BlockCode) error {
+	if t := CorrectableErrors(code); t != 1 {
+		return fmt.Errorf("%s исправляет %d ошибки, а поддерживаются только коды, исправляющие одну ошибку", code.Name(), t)
+	}
+	return nil
+}
+
+// unitEncodings возвращает производящую матрицу кода: строка t — кодовое
+// слово для данных с единственной единицей в бите t
+func unitEncodings(code BlockCode) [][]int {
+	G := make([][]int, code.Dimension())
+	for t := range G {
+		data := make([]int, code.Dimension())
+		data[t] = 1
+		G[t], _ = code.Encode(data)
+	}
+	return G
+}
+
+// nullSpace возвращает базис ядра матрицы G над GF(2) — проверочную матрицу
+// кода с производящей матрицей G. G приводится к ступенчатому виду; каждому
+// неведущему столбцу j соответствует строка H с единицей в j и в ведущих
+// столбцах строк, содержащих j.
+func nullSpace(G [][]int) [][]int {
+	n := len(G[0])
+	words := (n + 63) / 64
+	rows := make([][]uint64, len(G))
+	for i, row := range G {
+		rows[i] = make([]uint64, words)
+		for j, v := range row {
+			if v == 1 {
+				rows[i][j/64] |= 1 << (j % 64)
+			}
+		}
+	}
+	var pivots []int
+	rank := 0
+	for col := 0; col < n && rank < len(rows); col++ {
+		w, bit := col/64, uint64(1)<<(col%64)
+		p := rank
+		for p < len(rows) && rows[p][w]&bit == 0 {
+			p++
+		}
+		if p == len(rows) {
+			continue
+		}
+		rows[rank], rows[p] = rows[p], rows[rank]
+		for i := range rows {
+			if i != rank && rows[i][w]&bit != 0 {
+				for t := range rows[i] {
+					rows[i][t] ^= rows[rank][t]
+				}
+			}
+		}
+		pivots = append(pivots, col)
+		rank++
+	}
+	isPivot := make([]bool, n)
+	for _, col := range pivots {
+		isPivot[col] = true
+	}
+	var H [][]int
+	for j := 0; j < n; j++ {
+		if isPivot[j] {
+			continue
+		}
+		h := make([]int, n)
+		h[j] = 1
+		for r, col := range pivots {
+			if rows[r][j/64]>>(j%64)&1 == 1 {
+				h[col] = 1
+			}
+		}
+		H = append(H, h)
+	}
+	return H
+}
+
+// checkMatrix — упакованная проверочная матрица для быстрого вычисления
+// синдромов составных кодов; строится при первом обращении
+type checkMatrix struct {
+	rows []BitVec
+}
+
+// syndrome возвращает синдром H·word по проверочной матрице кода
+func (m *checkMatrix) syndrome(code LinearCode, word []int) []int {
+	if m.rows == nil {
+		for _, row := range code.ParityCheckMatrix() {
+			m.rows = append(m.rows, BitVecFromBits(row))
+		}
+	}
+	v := BitVecFromBits(word)
+	s := make([]int, len(m.rows))
+	for i, row := range m.rows {
+		s[i] = row.AndParity(v)
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package coding
+
+import "fmt"
+
+// Concatenated — последовательная конкатенация: внешний код Рида — Соломона
+// и внутренний двоичный код. Двоичный образ слова RS дополняется нулями до
+// целого числа блоков внутреннего кода, каждый блок кодируется внутренним
+// кодом. Декодер сначала декодирует блоки внутреннего кода (по LLR, если
+// внутренний декодер мягкий), затем исправляет оставшиеся ошибки внешним
+// кодом: пакет ошибок после внутреннего декодера портит лишь несколько
+// символов RS.
+type Concatenated struct {
+	outer  *ReedSolomon
+	inner  LinearCode
+	blocks int
+	checks checkMatrix
+}
+
+// NewConcatenated строит конкатенацию внешнего кода outer и внутреннего inner
+func NewConcatenated(outer *ReedSolomon, inner LinearCode) (*Concatenated, error) {
+	if inner.Dimension() < 1 {
+		return nil, fmt.Errorf("у внутреннего кода %s нет информационных бит", inner.Name())
+	}
+	k := inner.Dimension()
+	return &Concatenated{outer: outer, inner: inner, blocks: (outer.Length() + k - 1) / k}, nil
+}
+
+// Name возвращает название кода
+func (c *Concatenated) Name() string {
+	return fmt.Sprintf("Конкатенация [%s] + [%s]", c.outer.Name(), c.inner.Name())
+}
+
+// Length возвращает длину кодового слова: число блоков внутреннего кода,
+// умноженное на его длину
+func (c *Concatenated) Length() int { return c.blocks * c.inner.Length() }
+
+// Dimension возвращает число информационных бит внешнего кода
+func (c *Concatenated) Dimension() int { return c.outer.Dimension() }
+
+// Components возвращает внешний и внутренний коды
+func (c *Concatenated) Components() (*ReedSolomon, LinearCode) { return c.outer, c.inner }
+
+// Blocks возвращает число блоков внутреннего кода в слове
+func (c *Concatenated) Blocks() int { return c.blocks }
+
+// Encode кодирует данные внешним кодом, затем блоки — внутренним
+func (c *Concatenated) Encode(data []int) ([]int, error) {
+	outer, err := c.outer.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+	padded := make([]int, c.blocks*c.inner.Dimension())
+	copy(padded, outer)
+	k := c.inner.Dimension()
+	word := make([]int, 0, c.Length())
+	for b := 0; b < c.blocks; b++ {
+		block, err := c.inner.Encode(padded[b*k : (b+1)*k])
+		if err != nil {
+			return nil, err
+		}
+		word = append(word, block...)
+	}
+	return word, nil
+}
+
+// GeneratorMatrix возвращает производящую матрицу (k x n)
+func (c *Concatenated) GeneratorMatrix() [][]int { return unitEncodings(c) }
+
+// ParityCheckMatrix возвращает проверочную матрицу — базис ядра G
+func (c *Concatenated) ParityCheckMatrix() [][]int { return nullSpace(c.GeneratorMatrix()) }
+
+// Syndrome возвращает синдром слова по проверочной матрице
+func (c *Concatenated) Syndrome(word []int) []int { return c.checks.syndrome(c, word) }
+
+// Decode декодирует блоки внутреннего кода по жёстким решениям, затем
+// внешний код
+func (c *Concatenated) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.Length()); err != nil {
+		return DecodeResult{}, err
+	}
+	n := c.inner.Length()
+	var outer []int
+	for b := 0; b < c.blocks; b++ {
+		res, err := c.inner.Decode(received[b*n : (b+1)*n])
+		if err != nil {
+			return DecodeResult{}, err
+		}
+		outer = append(outer, res.Data...)
+	}
+	return c.finish(received, outer)
+}
+
+// DecodeLLR декодирует блоки мягким внутренним декодером, если он есть,
+// иначе — по знакам LLR
+func (c *Concatenated) DecodeLLR(llr []float64) (DecodeResult, error) {
+	if len(llr) != c.Length() {
+		return DecodeResult{}, fmt.Errorf("длина вектора LLR %d, ожидалось %d", len(llr), c.Length())
+	}
+	hard := make([]int, len(llr))
+	for i, l := range llr {
+		if l < 0 {
+			hard[i] = 1
+		}
+	}
+	soft, ok := c.inner.(SoftDecoder)
+	if !ok {
+		return c.Decode(hard)
+	}
+	n := c.inner.Length()
+	var outer []int
+	for b := 0; b < c.blocks; b++ {
+		res, err := soft.DecodeLLR(llr[b*n : (b+1)*n])
+		if err != nil {
+			return DecodeResult{}, err
+		}
+		outer = append(outer, res.Data...)
+	}
+	return c.finish(hard, outer)
+}
+
+// finish декодирует внешний код по битам outer, восстановленным внутренним
+// декодером, и сравнивает итоговое слово с принятым
+func (c *Concatenated) finish(received, outer []int) (DecodeResult, error) {
+	rs, err := c.outer.Decode(outer[:c.outer.Length()])
+	if err != nil {
+		return DecodeResult{}, err
+	}
+	res := DecodeResult{Data: rs.Data, Syndrome: c.Syndrome(received)}
+	res.Codeword, _ = c.Encode(res.Data)
+	for j := range received {
+		if received[j] != res.Codeword[j] {
+			res.ErrorPositions = append(res.ErrorPositions, j+1)
+		}
+	}
+	switch {
+	case rs.Status == StatusDetected:
+		// Внешний код обнаружил больше ошибок, чем может исправить
+		res.Status = StatusDetected
+		res.ErrorPositions = nil
+	case len(res.ErrorPositions) == 0:
+		res.Status = StatusOK
+	default:
+		res.Status = StatusCorrected
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
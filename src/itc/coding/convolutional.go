@@ -0,0 +1,248 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Convolutional — несистематический свёрточный код скорости 1/n с памятью m,
+// завершённый m нулевыми битами: k информационных бит дают (k+m)·n бит
+// кодового слова, по n выходных бит на такт. Полиномы заданы восьмеричными
+// числами из m+1 бит, старший бит — коэффициент при D^0, как у RSC:
+// (7, 5) — код с памятью 2, (171, 133) — стандартный код с памятью 6.
+// Декодирование — алгоритмом Витерби: по жёстким решениям (метрика
+// Хэмминга) в Decode и по LLR в DecodeLLR.
+type Convolutional struct {
+	k, memory int
+	polys     []int
+	output    [][]int // output[reg] — выходные биты при содержимом регистра reg (m+1 бит, текущий бит старший)
+	checks    checkMatrix
+}
+
+// NewConvolutional строит завершённый свёрточный код для k информационных
+// бит по 2–4 восьмеричным полиномам. Катастрофические коды (полиномы с общим
+// делителем) отвергаются: у них конечное число ошибок в канале может дать
+// бесконечное число ошибок декодирования.
+func NewConvolutional(k int, polys ...string) (*Convolutional, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("число информационных бит должно быть больше 0, получено %d", k)
+	}
+	if len(polys) < 2 || len(polys) > 4 {
+		return nil, fmt.Errorf("ожидается от 2 до 4 полиномов, получено %d", len(polys))
+	}
+	c := &Convolutional{k: k}
+	for _, s := range polys {
+		v, err := strconv.ParseUint(s, 8, 16)
+		if err != nil || v == 0 {
+			return nil, fmt.Errorf("полином %q должен быть ненулевым восьмеричным числом", s)
+		}
+		c.polys = append(c.polys, int(v))
+		c.memory = max(c.memory, bits.Len64(v)-1)
+	}
+	if c.memory < 1 || c.memory > 10 {
+		return nil, fmt.Errorf("память кода %d вне отрезка [1, 10]", c.memory)
+	}
+	// Коэффициенты при D^i в младших битах: переворот m+1 бит
+	g := 0
+	for _, p := range c.polys {
+		g = polyGCD(g, int(bits.Reverse64(uint64(p))>>(63-c.memory)))
+	}
+	if g != 1 {
+		return nil, fmt.Errorf("код с полиномами %s катастрофический: общий делитель полиномов отличен от 1", strings.Join(polys, ", "))
+	}
+	c.output = make([][]int, 1<<(c.memory+1))
+	for reg := range c.output {
+		for _, p := range c.polys {
+			c.output[reg] = append(c.output[reg], bits.OnesCount(uint(reg&p))&1)
+		}
+	}
+	return c, nil
+}
+
+// polyGCD возвращает НОД двоичных многочленов (бит i — коэффициент при x^i)
+func polyGCD(a, b int) int {
+	for b != 0 {
+		for a != 0 && bits.Len(uint(a)) >= bits.Len(uint(b)) {
+			a ^= b << (bits.Len(uint(a)) - bits.Len(uint(b)))
+		}
+		a, b = b, a
+	}
+	return a
+}
+
+// Name возвращает название кода
+func (c *Convolutional) Name() string {
+	octal := make([]string, len(c.polys))
+	for i, p := range c.polys {
+		octal[i] = strconv.FormatInt(int64(p), 8)
+	}
+	return fmt.Sprintf("Свёрточный (%s), k = %d", strings.Join(octal, ","), c.k)
+}
+
+// Memory возвращает память кода m
+func (c *Convolutional) Memory() int { return c.memory }
+
+// Length возвращает длину кодового слова (k+m)·n
+func (c *Convolutional) Length() int { return (c.k + c.memory) * len(c.polys) }
+
+// Dimension возвращает число информационных бит k
+func (c *Convolutional) Dimension() int { return c.k }
+
+// FreeDistance возвращает свободное расстояние: наименьший вес пути по
+// решётке, выходящего из нулевого состояния и возвращающегося в него
+func (c *Convolutional) FreeDistance() int {
+	states := 1 << c.memory
+	dist := make([]int, states)
+	for s := range dist {
+		dist[s] = math.MaxInt32
+	}
+	// Первый шаг — обязательно единица на входе
+	start := 1 << c.memory
+	dist[start>>1] = weight(c.output[start])
+	best := math.MaxInt32
+	for step := 0; step < 4*(c.memory+1)*states; step++ {
+		next := make([]int, states)
+		for s := range next {
+			next[s] = math.MaxInt32
+		}
+		changed := false
+		for s, d := range dist {
+			if d >= best {
+				continue
+			}
+			for u := 0; u < 2; u++ {
+				reg := u<<c.memory | s
+				nd := d + weight(c.output[reg])
+				if reg>>1 == 0 {
+					best = min(best, nd)
+				} else if nd < next[reg>>1] {
+					next[reg>>1] = nd
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+		dist = next
+	}
+	return best
+}
+
+// CorrectableErrors возвращает число ошибок, гарантированно исправляемых
+// на длине кодового ограничения: (d_free - 1)/2
+func (c *Convolutional) CorrectableErrors() int { return (c.FreeDistance() - 1) / 2 }
+
+// Encode кодирует данные и завершает решётку m нулями
+func (c *Convolutional) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, c.k); err != nil {
+		return nil, err
+	}
+	word := make([]int, 0, c.Length())
+	state := 0
+	for t := 0; t < c.k+c.memory; t++ {
+		u := 0
+		if t < c.k {
+			u = data[t]
+		}
+		reg := u<<c.memory | state
+		word = append(word, c.output[reg]...)
+		state = reg >> 1
+	}
+	return word, nil
+}
+
+// GeneratorMatrix возвращает производящую матрицу (k x n)
+func (c *Convolutional) GeneratorMatrix() [][]int { return unitEncodings(c) }
+
+// ParityCheckMatrix возвращает проверочную матрицу — базис ядра G
+func (c *Convolutional) ParityCheckMatrix() [][]int { return nullSpace(c.GeneratorMatrix()) }
+
+// Syndrome возвращает синдром слова по проверочной матрице
+func (c *Convolutional) Syndrome(word []int) []int { return c.checks.syndrome(c, word) }
+
+// Decode декодирует слово алгоритмом Витерби по расстоянию Хэмминга
+func (c *Convolutional) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.Length()); err != nil {
+		return DecodeResult{}, err
+	}
+	llr := make([]float64, len(received))
+	for i, b := range received {
+		llr[i] = float64(1 - 2*b)
+	}
+	return c.DecodeLLR(llr)
+}
+
+// DecodeLLR декодирует слово алгоритмом Витерби: метрика ветви — сумма LLR
+// выходных бит со знаком «+» для 0 и «-» для 1; выбирается путь из нулевого
+// состояния в нулевое с наибольшей метрикой
+func (c *Convolutional) DecodeLLR(llr []float64) (DecodeResult, error) {
+	if len(llr) != c.Length() {
+		return DecodeResult{}, fmt.Errorf("длина вектора LLR %d, ожидалось %d", len(llr), c.Length())
+	}
+	states := 1 << c.memory
+	n := len(c.polys)
+	steps := c.k + c.memory
+	metric := make([]float64, states)
+	next := make([]float64, states)
+	for s := range metric {
+		metric[s] = math.Inf(-1)
+	}
+	metric[0] = 0
+	// decision[t][s] — младший бит предшественника состояния s на шаге t
+	decision := make([][]uint8, steps)
+	for t := 0; t < steps; t++ {
+		decision[t] = make([]uint8, states)
+		for s := range next {
+			next[s] = math.Inf(-1)
+		}
+		for reg := 0; reg < 2*states; reg++ {
+			u, prev := reg>>c.memory, reg&(states-1)
+			if t >= c.k && u == 1 || math.IsInf(metric[prev], -1) {
+				continue
+			}
+			m := metric[prev]
+			for j, b := range c.output[reg] {
+				if b == 0 {
+					m += llr[t*n+j]
+				} else {
+					m -= llr[t*n+j]
+				}
+			}
+			if ns := reg >> 1; m > next[ns] {
+				next[ns] = m
+				decision[t][ns] = uint8(reg & 1)
+			}
+		}
+		metric, next = next, metric
+	}
+	// Обратный проход из нулевого состояния; вход шага — старший бит состояния
+	path := make([]int, steps)
+	state := 0
+	for t := steps - 1; t >= 0; t-- {
+		path[t] = state >> (c.memory - 1)
+		state = (state<<1)&(states-1) | int(decision[t][state])
+	}
+	hard := make([]int, len(llr))
+	for i, l := range llr {
+		if l < 0 {
+			hard[i] = 1
+		}
+	}
+	res := DecodeResult{Data: path[:c.k], Syndrome: c.Syndrome(hard)}
+	res.Codeword, _ = c.Encode(res.Data)
+	for j := range hard {
+		if hard[j] != res.Codeword[j] {
+			res.ErrorPositions = append(res.ErrorPositions, j+1)
+		}
+	}
+	res.Status = StatusOK
+	if len(res.ErrorPositions) > 0 {
+		res.Status = StatusCorrected
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
@@ -0,0 +1,61 @@
+package coding
+
+import "fmt"
+
+// crcPolynomials — образующие полиномы поддерживаемых CRC без старшего члена
+var crcPolynomials = map[int]uint64{
+	6:  0x21,     // x^6 + x^5 + 1 (CRC6 в 5G NR)
+	8:  0x07,     // x^8 + x^2 + x + 1 (CRC-8-CCITT)
+	11: 0x621,    // x^11 + x^10 + x^9 + x^5 + 1 (CRC11 в 5G NR)
+	16: 0x1021,   // x^16 + x^12 + x^5 + 1 (CRC-16-CCITT)
+	24: 0xB2B117, // CRC24C в 5G NR
+}
+
+// CRC — циклический избыточный код с нулевым начальным значением; биты
+// сообщения обрабатываются по порядку, как коэффициенты от старшего к младшему
+type CRC struct {
+	Bits int
+	poly uint64
+}
+
+// NewCRC возвращает CRC длины bits: 6, 8, 11, 16 или 24
+func NewCRC(bits int) (CRC, error) {
+	poly, ok := crcPolynomials[bits]
+	if !ok {
+		return CRC{}, fmt.Errorf("CRC длины %d не поддерживается (ожидается 6, 8, 11, 16 или 24)", bits)
+	}
+	return CRC{Bits: bits, poly: poly}, nil
+}
+
+// Compute возвращает Bits проверочных бит сообщения (старший бит первым)
+func (c CRC) Compute(data []int) []int {
+	top := uint64(1) << (c.Bits - 1)
+	mask := top<<1 - 1
+	var reg uint64
+	for _, bit := range data {
+		feedback := reg&top != 0
+		if bit != 0 {
+			feedback = !feedback
+		}
+		reg = reg << 1 & mask
+		if feedback {
+			reg ^= c.poly
+		}
+	}
+	res := make([]int, c.Bits)
+	for i := range res {
+		res[i] = int(reg >> (c.Bits - 1 - i) & 1)
+	}
+	return res
+}
+
+// Check проверяет, что последние Bits бит слова — CRC предшествующих
+func (c CRC) Check(word []int) bool {
+	if len(word) < c.Bits {
+		return false
+	}
+	return BitsEqual(c.Compute(word[:len(word)-c.Bits]), word[len(word)-c.Bits:])
+}
+
+// String возвращает название CRC
+func (c CRC) String() string { return fmt.Sprintf("CRC-%d", c.Bits) }
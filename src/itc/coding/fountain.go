@@ -0,0 +1,649 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+)
+
+// DegreeDistribution — распределение степеней выходных символов фонтанного
+// кода: p[d] — вероятность того, что символ равен XOR d входных, p[0] = 0
+type DegreeDistribution []float64
+
+// Mean возвращает среднюю степень
+func (p DegreeDistribution) Mean() float64 {
+	mean := 0.0
+	for d, v := range p {
+		mean += float64(d) * v
+	}
+	return mean
+}
+
+// MaxDegree возвращает наибольшую степень с ненулевой вероятностью
+func (p DegreeDistribution) MaxDegree() int {
+	for d := len(p) - 1; d > 0; d-- {
+		if p[d] > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// IdealSoliton возвращает идеальное распределение Солитона:
+// ρ(1) = 1/k, ρ(d) = 1/(d(d-1)) для d = 2..k
+func IdealSoliton(k int) (DegreeDistribution, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("число исходных символов должно быть больше 0, получено %d", k)
+	}
+	p := make(DegreeDistribution, k+1)
+	p[1] = 1 / float64(k)
+	for d := 2; d <= k; d++ {
+		p[d] = 1 / (float64(d) * float64(d-1))
+	}
+	return p, nil
+}
+
+// SolitonParams — параметры робастного распределения Солитона: ожидаемое
+// число символов степени 1 R = c ln(k/δ) √k, положение пика k/R и
+// нормирующий множитель β (декодеру нужно около kβ символов)
+type SolitonParams struct {
+	R     float64
+	Spike int
+	Beta  float64
+}
+
+// RobustSoliton возвращает робастное распределение Солитона (Luby, 2002):
+// μ(d) = (ρ(d) + τ(d)) / β, где τ(d) = R/(dk) для d < k/R,
+// τ(k/R) = R ln(R/δ)/k и τ(d) = 0 для остальных d
+func RobustSoliton(k int, c, delta float64) (DegreeDistribution, SolitonParams, error) {
+	if c <= 0 {
+		return nil, SolitonParams{}, fmt.Errorf("параметр c должен быть больше 0, получено %g", c)
+	}
+	if delta <= 0 || delta >= 1 {
+		return nil, SolitonParams{}, fmt.Errorf("вероятность неудачи δ = %g вне интервала (0, 1)", delta)
+	}
+	p, err := IdealSoliton(k)
+	if err != nil {
+		return nil, SolitonParams{}, err
+	}
+	R := c * math.Log(float64(k)/delta) * math.Sqrt(float64(k))
+	params := SolitonParams{R: R, Spike: min(max(int(math.Round(float64(k)/R)), 1), k)}
+	for d := 1; d <= k; d++ {
+		switch {
+		case d < params.Spike:
+			p[d] += R / (float64(d) * float64(k))
+		case d == params.Spike:
+			p[d] += max(R*math.Log(R/delta)/float64(k), 0)
+		}
+		params.Beta += p[d]
+	}
+	for d := range p {
+		p[d] /= params.Beta
+	}
+	return p, params, nil
+}
+
+// RaptorDistribution возвращает распределение степеней Ω(x) кода Raptor из
+// RFC 5053: степени 1, 2, 3, 4, 10, 11 и 40 со средним около 4.6. Слабое LT-
+// кодирование восстанавливает большую часть промежуточных символов, остальные
+// восстанавливает предкод.
+func RaptorDistribution() DegreeDistribution {
+	// Накопленные вероятности из RFC 5053 в единицах 2^-20
+	cumulative := []struct{ degree, f int }{
+		{1, 10241}, {2, 491582}, {3, 712794}, {4, 831695}, {10, 948446}, {11, 1032189}, {40, 1048576},
+	}
+	p := make(DegreeDistribution, 41)
+	prev := 0
+	for _, c := range cumulative {
+		p[c.degree] = float64(c.f-prev) / (1 << 20)
+		prev = c.f
+	}
+	return p
+}
+
+// truncate переносит вероятность степеней больше n на степень n
+func (p DegreeDistribution) truncate(n int) DegreeDistribution {
+	if len(p) <= n+1 {
+		return p
+	}
+	q := append(DegreeDistribution(nil), p[:n+1]...)
+	for _, v := range p[n+1:] {
+		q[n] += v
+	}
+	return q
+}
+
+// Precode — разреженный предкод Raptor: к k исходным символам добавляются
+// проверочные, каждый — XOR своих исходных символов. Каждый исходный символ
+// входит в три проверки (или во все, если проверок меньше трёх).
+type Precode struct {
+	k      int
+	checks [][]int
+}
+
+// NewPrecode строит предкод с заданным числом проверок
+func NewPrecode(k, checks int, rng *rand.Rand) (*Precode, error) {
+	if k < 1 || checks < 1 {
+		return nil, fmt.Errorf("предкод: число исходных символов %d и проверок %d должны быть больше 0", k, checks)
+	}
+	c := &Precode{k: k, checks: make([][]int, checks)}
+	w := min(3, checks)
+	for i := 0; i < k; i++ {
+		for _, j := range rng.Perm(checks)[:w] {
+			c.checks[j] = append(c.checks[j], i)
+		}
+	}
+	return c, nil
+}
+
+// RaptorChecks возвращает число проверок предкода для k исходных символов, как
+// в LDPC-части предкода RFC 5053: ⌈0.01k⌉ + X, где X(X-1) ≥ 2k
+func RaptorChecks(k int) int {
+	x := 1
+	for x*(x-1) < 2*k {
+		x++
+	}
+	return (k+99)/100 + x
+}
+
+// Checks возвращает число проверочных символов
+func (c *Precode) Checks() int { return len(c.checks) }
+
+// Check возвращает номера исходных символов проверки j
+func (c *Precode) Check(j int) []int { return c.checks[j] }
+
+// Fountain — бесскоростной (фонтанный) код над каналом со стираниями.
+// Передатчик порождает сколько угодно выходных символов; символ с номером id —
+// XOR промежуточных символов, выбранных по распределению степеней
+// генератором, который зависит только от зерна кода и id. LT-код кодирует
+// исходные символы непосредственно, код Raptor — промежуточные символы
+// предкода (исходные и проверочные).
+type Fountain struct {
+	k       int
+	dist    DegreeDistribution
+	cdf     []float64
+	precode *Precode
+}
+
+// NewLT строит LT-код для k исходных символов
+func NewLT(k int, dist DegreeDistribution) (*Fountain, error) {
+	return newFountain(k, dist, nil)
+}
+
+// NewRaptor строит код Raptor: предкод с заданным числом проверок и LT-код
+// над промежуточными символами
+func NewRaptor(k, checks int, dist DegreeDistribution, rng *rand.Rand) (*Fountain, error) {
+	pre, err := NewPrecode(k, checks, rng)
+	if err != nil {
+		return nil, err
+	}
+	return newFountain(k, dist, pre)
+}
+
+func newFountain(k int, dist DegreeDistribution, pre *Precode) (*Fountain, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("число исходных символов должно быть больше 0, получено %d", k)
+	}
+	f := &Fountain{k: k, precode: pre}
+	f.dist = dist.truncate(f.IntermediateSymbols())
+	f.cdf = make([]float64, len(f.dist))
+	sum := 0.0
+	for d, v := range f.dist {
+		if v < 0 {
+			return nil, fmt.Errorf("отрицательная вероятность степени %d", d)
+		}
+		sum += v
+		f.cdf[d] = sum
+	}
+	if f.dist.MaxDegree() == 0 || math.Abs(sum-1) > 1e-9 {
+		return nil, fmt.Errorf("распределение степеней должно давать в сумме 1, получено %g", sum)
+	}
+	f.cdf[len(f.cdf)-1] = 1
+	return f, nil
+}
+
+// Name возвращает название кода
+func (f *Fountain) Name() string {
+	if f.precode == nil {
+		return fmt.Sprintf("LT, k = %d", f.k)
+	}
+	return fmt.Sprintf("Raptor, k = %d, проверок предкода %d", f.k, f.precode.Checks())
+}
+
+// SourceSymbols возвращает число исходных символов k
+func (f *Fountain) SourceSymbols() int { return f.k }
+
+// IntermediateSymbols возвращает число промежуточных символов, из которых
+// составляются выходные: k для LT-кода, k плюс проверки предкода для Raptor
+func (f *Fountain) IntermediateSymbols() int {
+	if f.precode == nil {
+		return f.k
+	}
+	return f.k + f.precode.Checks()
+}
+
+// Distribution возвращает распределение степеней выходных символов
+func (f *Fountain) Distribution() DegreeDistribution { return f.dist }
+
+// Precode возвращает предкод (nil для LT-кода)
+func (f *Fountain) Precode() *Precode { return f.precode }
+
+// symbolRand — генератор SplitMix64 для выбора соседей выходного символа.
+// Последовательность определяется зерном кода и номером символа, поэтому
+// приёмнику, знающему зерно, достаточно номера принятого символа.
+type symbolRand uint64
+
+func newSymbolRand(seed int64, id int) *symbolRand {
+	r := symbolRand(uint64(seed)*0xD1B54A32D192ED03 + uint64(id))
+	return &r
+}
+
+func (r *symbolRand) next() uint64 {
+	*r += 0x9E3779B97F4A7C15
+	z := uint64(*r)
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	return z ^ z>>31
+}
+
+func (r *symbolRand) float() float64 { return float64(r.next()>>11) / (1 << 53) }
+
+func (r *symbolRand) intn(n int) int { return int(r.next() % uint64(n)) }
+
+// Neighbors возвращает номера промежуточных символов, из которых составлен
+// выходной символ id при зерне seed
+func (f *Fountain) Neighbors(seed int64, id int) []int {
+	r := newSymbolRand(seed, id)
+	u := r.float()
+	d := sort.Search(len(f.cdf), func(i int) bool { return f.cdf[i] > u })
+	d = min(d, f.dist.MaxDegree())
+	n := f.IntermediateSymbols()
+	res := make([]int, 0, d)
+	seen := make(map[int]bool, d)
+	for len(res) < d {
+		v := r.intn(n)
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// EncodedSymbol — выходной символ фонтанного кода
+type EncodedSymbol struct {
+	ID   int
+	Data []byte
+}
+
+// xorInto прибавляет b к a по модулю 2
+func xorInto(a, b []byte) {
+	for i := range a {
+		a[i] ^= b[i]
+	}
+}
+
+// FountainEncoder порождает выходные символы для одного набора исходных
+type FountainEncoder struct {
+	f            *Fountain
+	seed         int64
+	intermediate [][]byte
+}
+
+// NewEncoder готовит кодирование k исходных символов одинаковой длины
+func (f *Fountain) NewEncoder(source [][]byte, seed int64) (*FountainEncoder, error) {
+	if len(source) != f.k {
+		return nil, fmt.Errorf("ожидалось %d исходных символов, получено %d", f.k, len(source))
+	}
+	size := len(source[0])
+	for i, s := range source {
+		if len(s) != size || size == 0 {
+			return nil, fmt.Errorf("исходный символ %d: все символы должны быть непустыми и одной длины", i+1)
+		}
+	}
+	e := &FountainEncoder{f: f, seed: seed, intermediate: source}
+	if f.precode != nil {
+		e.intermediate = append([][]byte(nil), source...)
+		for _, check := range f.precode.checks {
+			parity := make([]byte, size)
+			for _, i := range check {
+				xorInto(parity, source[i])
+			}
+			e.intermediate = append(e.intermediate, parity)
+		}
+	}
+	return e, nil
+}
+
+// Symbol возвращает выходной символ с номером id
+func (e *FountainEncoder) Symbol(id int) EncodedSymbol {
+	data := make([]byte, len(e.intermediate[0]))
+	for _, v := range e.f.Neighbors(e.seed, id) {
+		xorInto(data, e.intermediate[v])
+	}
+	return EncodedSymbol{ID: id, Data: data}
+}
+
+// fountainEquation — принятый символ или проверка предкода: XOR ещё
+// неизвестных промежуточных символов neighbors равен data. last — XOR
+// номеров неизвестных соседей: когда остаётся один, это его номер.
+type fountainEquation struct {
+	neighbors []int
+	remaining int
+	last      int
+	data      []byte
+}
+
+// FountainDecoder восстанавливает исходные символы по принятым выходным
+// символам. Основной алгоритм — «очистка» (peeling): символ, у которого
+// остался один неизвестный сосед, определяет этого соседа, после чего сосед
+// исключается из остальных уравнений. Проверки предкода Raptor участвуют в
+// очистке наравне с принятыми символами. Если задано Elimination, при
+// остановке очистки оставшаяся система решается методом Гаусса.
+type FountainDecoder struct {
+	Elimination bool
+
+	f          *Fountain
+	seed       int64
+	size       int
+	known      []bool
+	values     [][]byte
+	watch      [][]*fountainEquation
+	active     []*fountainEquation
+	ripple     []*fountainEquation
+	unknown    int
+	sourceLeft int
+	received   int
+}
+
+// NewDecoder создаёт декодер символов длины size для зерна seed
+func (f *Fountain) NewDecoder(seed int64, size int) *FountainDecoder {
+	n := f.IntermediateSymbols()
+	d := &FountainDecoder{
+		f: f, seed: seed, size: size,
+		known: make([]bool, n), values: make([][]byte, n), watch: make([][]*fountainEquation, n),
+		unknown: n, sourceLeft: f.k,
+	}
+	if f.precode != nil {
+		for j, check := range f.precode.checks {
+			d.addEquation(append(append([]int(nil), check...), f.k+j), make([]byte, size))
+		}
+		d.peel()
+	}
+	return d
+}
+
+// Received возвращает число принятых выходных символов
+func (d *FountainDecoder) Received() int { return d.received }
+
+// Recovered возвращает число восстановленных исходных символов
+func (d *FountainDecoder) Recovered() int { return d.f.k - d.sourceLeft }
+
+// Done сообщает, восстановлены ли все исходные символы
+func (d *FountainDecoder) Done() bool { return d.sourceLeft == 0 }
+
+// Source возвращает исходные символы; невосстановленные равны nil
+func (d *FountainDecoder) Source() [][]byte { return d.values[:d.f.k] }
+
+// Add учитывает принятый символ и возвращает true, если все исходные
+// символы восстановлены
+func (d *FountainDecoder) Add(sym EncodedSymbol) (bool, error) {
+	if len(sym.Data) != d.size {
+		return false, fmt.Errorf("символ %d: длина %d байт, ожидалось %d", sym.ID, len(sym.Data), d.size)
+	}
+	d.received++
+	if d.Done() {
+		return true, nil
+	}
+	d.addEquation(d.f.Neighbors(d.seed, sym.ID), append([]byte(nil), sym.Data...))
+	d.peel()
+	// Метод Гаусса имеет смысл, когда уравнений не меньше неизвестных
+	if !d.Done() && d.Elimination && len(d.active) >= d.unknown {
+		d.eliminate()
+	}
+	return d.Done(), nil
+}
+
+// addEquation исключает из уравнения известные символы и ставит его в
+// очередь очистки или в список активных
+func (d *FountainDecoder) addEquation(neighbors []int, data []byte) {
+	eq := &fountainEquation{data: data}
+	for _, v := range neighbors {
+		if d.known[v] {
+			xorInto(eq.data, d.values[v])
+			continue
+		}
+		eq.neighbors = append(eq.neighbors, v)
+		eq.remaining++
+		eq.last ^= v
+	}
+	switch eq.remaining {
+	case 0:
+		return
+	case 1:
+		d.ripple = append(d.ripple, eq)
+		return
+	}
+	for _, v := range eq.neighbors {
+		d.watch[v] = append(d.watch[v], eq)
+	}
+	d.active = append(d.active, eq)
+}
+
+// peel обрабатывает очередь уравнений с одним неизвестным
+func (d *FountainDecoder) peel() {
+	for len(d.ripple) > 0 {
+		eq := d.ripple[len(d.ripple)-1]
+		d.ripple = d.ripple[:len(d.ripple)-1]
+		if !d.known[eq.last] {
+			d.resolve(eq.last, eq.data)
+		}
+	}
+	// Уравнения, ушедшие в очередь, больше не активны
+	kept := d.active[:0]
+	for _, eq := range d.active {
+		if eq.remaining >= 2 {
+			kept = append(kept, eq)
+		}
+	}
+	d.active = kept
+}
+
+// resolve запоминает значение промежуточного символа v и исключает его из
+// уравнений, в которые он входит
+func (d *FountainDecoder) resolve(v int, data []byte) {
+	d.known[v] = true
+	d.values[v] = data
+	d.unknown--
+	if v < d.f.k {
+		d.sourceLeft--
+	}
+	for _, eq := range d.watch[v] {
+		if eq.remaining < 2 {
+			continue
+		}
+		xorInto(eq.data, data)
+		eq.remaining--
+		eq.last ^= v
+		if eq.remaining == 1 {
+			d.ripple = append(d.ripple, eq)
+		}
+	}
+	d.watch[v] = nil
+}
+
+// eliminate решает систему активных уравнений методом Гаусса над GF(2) и
+// запоминает все однозначно определённые символы, после чего продолжает очистку
+func (d *FountainDecoder) eliminate() {
+	column := make(map[int]int)
+	var vars []int
+	for v, ok := range d.known {
+		if !ok {
+			column[v] = len(vars)
+			vars = append(vars, v)
+		}
+	}
+	words := (len(vars) + 63) / 64
+	rows := make([][]uint64, len(d.active))
+	data := make([][]byte, len(d.active))
+	for r, eq := range d.active {
+		rows[r] = make([]uint64, words)
+		for _, v := range eq.neighbors {
+			if !d.known[v] {
+				c := column[v]
+				rows[r][c/64] |= 1 << (c % 64)
+			}
+		}
+		data[r] = append([]byte(nil), eq.data...)
+	}
+	var pivots []int
+	rank := 0
+	for c := 0; c < len(vars) && rank < len(rows); c++ {
+		p := -1
+		for r := rank; r < len(rows); r++ {
+			if rows[r][c/64]>>(c%64)&1 == 1 {
+				p = r
+				break
+			}
+		}
+		if p < 0 {
+			continue
+		}
+		rows[rank], rows[p] = rows[p], rows[rank]
+		data[rank], data[p] = data[p], data[rank]
+		for r := range rows {
+			if r != rank && rows[r][c/64]>>(c%64)&1 == 1 {
+				for w := range rows[r] {
+					rows[r][w] ^= rows[rank][w]
+				}
+				xorInto(data[r], data[rank])
+			}
+		}
+		pivots = append(pivots, c)
+		rank++
+	}
+	for r, c := range pivots {
+		ones := 0
+		for _, w := range rows[r] {
+			ones += bits.OnesCount64(w)
+		}
+		if ones == 1 && !d.known[vars[c]] {
+			d.resolve(vars[c], data[r])
+		}
+	}
+	d.peel()
+}
+
+// FountainTrial — результат передачи одного набора исходных символов
+type FountainTrial struct {
+	Sent     int  // передано символов, включая стёртые
+	Received int  // принято символов до завершения декодирования
+	Decoded  bool // все исходные символы восстановлены
+}
+
+// Overhead возвращает относительный избыток принятых символов (Received - k) / k
+func (t FountainTrial) Overhead(k int) float64 {
+	return float64(t.Received-k) / float64(k)
+}
+
+// SimulateFountain передаёт trials наборов случайных исходных символов по
+// каналу со стираниями с вероятностью erasure, пока декодер не восстановит
+// все символы (но не более 10k принятых), и сверяет результат с исходными
+func SimulateFountain(f *Fountain, erasure float64, trials int, elimination bool, rng *rand.Rand) ([]FountainTrial, error) {
+	if erasure < 0 || erasure >= 1 {
+		return nil, fmt.Errorf("вероятность стирания %g вне интервала [0, 1)", erasure)
+	}
+	if trials <= 0 {
+		return nil, fmt.Errorf("число испытаний должно быть больше 0, получено %d", trials)
+	}
+	limit := 10 * f.k
+	res := make([]FountainTrial, trials)
+	for t := range res {
+		source := make([][]byte, f.k)
+		for i := range source {
+			source[i] = []byte{byte(rng.Intn(256))}
+		}
+		seed := rng.Int63()
+		enc, err := f.NewEncoder(source, seed)
+		if err != nil {
+			return nil, err
+		}
+		dec := f.NewDecoder(seed, 1)
+		dec.Elimination = elimination
+		for id := 0; !dec.Done() && dec.Received() < limit; id++ {
+			res[t].Sent++
+			if rng.Float64() < erasure {
+				continue
+			}
+			if _, err := dec.Add(enc.Symbol(id)); err != nil {
+				return nil, err
+			}
+		}
+		res[t].Received = dec.Received()
+		res[t].Decoded = dec.Done()
+		if res[t].Decoded {
+			for i, s := range dec.Source() {
+				if s[0] != source[i][0] {
+					return nil, fmt.Errorf("испытание %d: исходный символ %d восстановлен неверно", t+1, i+1)
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// OverheadStats — статистика избытка принятых символов по успешным испытаниям
+type OverheadStats struct {
+	Trials, Failures           int
+	Mean, StdDev               float64
+	Min, Median, P90, P99, Max float64
+	SentPerSource              float64 // среднее число переданных символов на исходный
+}
+
+// NewOverheadStats вычисляет статистику избытка для кода с k исходными символами
+func NewOverheadStats(trials []FountainTrial, k int) OverheadStats {
+	s := OverheadStats{Trials: len(trials)}
+	var overheads []float64
+	sent := 0
+	for _, t := range trials {
+		sent += t.Sent
+		if !t.Decoded {
+			s.Failures++
+			continue
+		}
+		overheads = append(overheads, t.Overhead(k))
+	}
+	s.SentPerSource = float64(sent) / float64(len(trials)*k)
+	if len(overheads) == 0 {
+		return s
+	}
+	sort.Float64s(overheads)
+	for _, v := range overheads {
+		s.Mean += v
+	}
+	s.Mean /= float64(len(overheads))
+	for _, v := range overheads {
+		s.StdDev += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(s.StdDev / float64(len(overheads)))
+	quantile := func(q float64) float64 {
+		return overheads[min(int(q*float64(len(overheads))), len(overheads)-1)]
+	}
+	s.Min, s.Max = overheads[0], overheads[len(overheads)-1]
+	s.Median, s.P90, s.P99 = quantile(0.5), quantile(0.9), quantile(0.99)
+	return s
+}
+
+// FailureProbability возвращает долю испытаний, в которых после приёма
+// k(1 + overhead) символов исходные символы ещё не были восстановлены
+func FailureProbability(trials []FountainTrial, k int, overhead float64) float64 {
+	failed := 0
+	for _, t := range trials {
+		if !t.Decoded || float64(t.Received) > float64(k)*(1+overhead)+1e-9 {
+			failed++
+		}
+	}
+	return float64(failed) / float64(len(trials))
+}
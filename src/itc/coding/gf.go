@@ -0,0 +1,88 @@
+package coding
+
+import "fmt"
+
+// primitivePolynomials — примитивные многочлены степени m с единичным старшим
+// коэффициентом (бит m)
+var primitivePolynomials = map[int]int{
+	2: 0x7, 3: 0xB, 4: 0x13, 5: 0x25, 6: 0x43, 7: 0x89, 8: 0x11D,
+	9: 0x211, 10: 0x409, 11: 0x805, 12: 0x1053, 13: 0x201B, 14: 0x4443, 15: 0x8003, 16: 0x1100B,
+}
+
+// GF — конечное поле GF(2^m). Элементы — числа 0..2^m-1 (коэффициенты
+// многочлена от α), сложение — XOR, умножение — по таблицам степеней и
+// логарифмов примитивного элемента α.
+type GF struct {
+	m   int
+	exp []int // exp[i] = α^i, i = 0..2(2^m-1)
+	log []int // log[a] — показатель степени a = α^log[a], a ≠ 0
+}
+
+// NewGF строит поле GF(2^m), 2 <= m <= 16
+func NewGF(m int) (*GF, error) {
+	poly, ok := primitivePolynomials[m]
+	if !ok {
+		return nil, fmt.Errorf("поле GF(2^%d) не поддерживается (ожидается 2 <= m <= 16)", m)
+	}
+	order := 1<<m - 1
+	f := &GF{m: m, exp: make([]int, 2*order), log: make([]int, order+1)}
+	x := 1
+	for i := 0; i < order; i++ {
+		f.exp[i], f.exp[i+order] = x, x
+		f.log[x] = i
+		x <<= 1
+		if x > order {
+			x ^= poly
+		}
+	}
+	return f, nil
+}
+
+// String возвращает обозначение поля, например "GF(2^8)"
+func (f *GF) String() string { return fmt.Sprintf("GF(2^%d)", f.m) }
+
+// Bits возвращает m — число бит в элементе
+func (f *GF) Bits() int { return f.m }
+
+// Order возвращает порядок мультипликативной группы 2^m - 1
+func (f *GF) Order() int { return 1<<f.m - 1 }
+
+// Exp возвращает α^i для любого целого i
+func (f *GF) Exp(i int) int {
+	i %= f.Order()
+	if i < 0 {
+		i += f.Order()
+	}
+	return f.exp[i]
+}
+
+// Log возвращает логарифм ненулевого элемента по основанию α
+func (f *GF) Log(a int) int { return f.log[a] }
+
+// Mul возвращает произведение a·b
+func (f *GF) Mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]+f.log[b]]
+}
+
+// Div возвращает частное a/b, b ≠ 0
+func (f *GF) Div(a, b int) int {
+	if a == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]-f.log[b]+f.Order()]
+}
+
+// Inv возвращает обратный элемент 1/a, a ≠ 0
+func (f *GF) Inv(a int) int { return f.exp[f.Order()-f.log[a]] }
+
+// polyEval вычисляет многочлен p (коэффициенты от младшей степени) в точке x
+func (f *GF) polyEval(p []int, x int) int {
+	acc := 0
+	for i := len(p) - 1; i >= 0; i-- {
+		acc = f.Mul(acc, x) ^ p[i]
+	}
+	return acc
+}
@@ -0,0 +1,213 @@
+package coding
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// GFq — конечное поле GF(q) из q = p^e элементов для простого p. Элементы —
+// числа 0..q-1, цифры которых в системе счисления по основанию p —
+// коэффициенты многочлена от примитивного элемента α (младшая цифра —
+// свободный член). Сложение поразрядное по модулю p, умножение — по таблицам
+// степеней и логарифмов α. При q = 2^m сложение совпадает с XOR, как в GF.
+type GFq struct {
+	p, e, q int
+	add     [][]int // add[a][b] = a + b
+	neg     []int   // neg[a] = -a
+	exp     []int   // exp[i] = α^i, i = 0..2(q-1)
+	log     []int   // log[a] — показатель степени a = α^log[a], a ≠ 0
+}
+
+// maxFieldSize — наибольший поддерживаемый размер поля GFq: таблица
+// сложения занимает q² элементов
+const maxFieldSize = 256
+
+// primePower раскладывает q = p^e; ok = false, если q не степень простого
+func primePower(q int) (p, e int, ok bool) {
+	if q < 2 {
+		return 0, 0, false
+	}
+	p = 2
+	for q%p != 0 {
+		p++
+	}
+	for q%p == 0 {
+		q /= p
+		e++
+	}
+	return p, e, q == 1
+}
+
+// NewGFq строит поле GF(q) для простого или степени простого q <= 256.
+// Примитивный многочлен степени e ищется перебором: x — примитивный
+// элемент, если его степени впервые возвращаются к 1 ровно через q-1 шагов.
+func NewGFq(q int) (*GFq, error) {
+	p, e, ok := primePower(q)
+	if !ok || q > maxFieldSize {
+		return nil, fmt.Errorf("поле GF(%d) не поддерживается: q должно быть простым числом или степенью простого, не больше %d", q, maxFieldSize)
+	}
+	f := &GFq{p: p, e: e, q: q}
+	// Многочлен x^e + c(x), c задан числом t: цифры — коэффициенты c_0..c_(e-1)
+	for t := 1; t < q && f.exp == nil; t++ {
+		if t%p != 0 {
+			f.exp = primitivePowers(p, e, t)
+		}
+	}
+	f.exp = append(f.exp, f.exp...)
+	f.log = make([]int, q)
+	for i := 0; i < q-1; i++ {
+		f.log[f.exp[i]] = i
+	}
+	f.add = make([][]int, q)
+	f.neg = make([]int, q)
+	for a := range f.add {
+		f.add[a] = make([]int, q)
+		for b := range f.add[a] {
+			f.add[a][b] = f.digitwise(a, b, 1)
+		}
+		f.neg[a] = f.digitwise(0, a, -1)
+	}
+	return f, nil
+}
+
+// primitivePowers возвращает степени x^0..x^(q-2) по модулю x^e + c(x) или
+// nil, если x не примитивный элемент
+func primitivePowers(p, e, c int) []int {
+	q := 1
+	coef := make([]int, e)
+	for j := range coef {
+		coef[j] = c % p
+		c /= p
+		q *= p
+	}
+	digits := make([]int, e)
+	digits[0] = 1
+	powers := []int{1}
+	for i := 1; i < q; i++ {
+		// Умножение на x: x^e заменяется на -c(x)
+		top := digits[e-1]
+		copy(digits[1:], digits[:e-1])
+		digits[0] = 0
+		value := 0
+		for j := e - 1; j >= 0; j-- {
+			digits[j] = ((digits[j]-top*coef[j])%p + p) % p
+			value = value*p + digits[j]
+		}
+		if value == 1 {
+			if i == q-1 {
+				return powers
+			}
+			return nil
+		}
+		powers = append(powers, value)
+	}
+	return nil
+}
+
+// digitwise возвращает a + sign·b поразрядно по модулю p
+func (f *GFq) digitwise(a, b, sign int) int {
+	value, scale := 0, 1
+	for j := 0; j < f.e; j++ {
+		d := ((a%f.p+sign*(b%f.p))%f.p + f.p) % f.p
+		value += d * scale
+		a, b, scale = a/f.p, b/f.p, scale*f.p
+	}
+	return value
+}
+
+// String возвращает обозначение поля, например "GF(9)"
+func (f *GFq) String() string { return fmt.Sprintf("GF(%d)", f.q) }
+
+// Size возвращает число элементов поля q
+func (f *GFq) Size() int { return f.q }
+
+// Characteristic возвращает характеристику поля p
+func (f *GFq) Characteristic() int { return f.p }
+
+// Add возвращает сумму a + b
+func (f *GFq) Add(a, b int) int { return f.add[a][b] }
+
+// Sub возвращает разность a - b
+func (f *GFq) Sub(a, b int) int { return f.add[a][f.neg[b]] }
+
+// Neg возвращает противоположный элемент -a
+func (f *GFq) Neg(a int) int { return f.neg[a] }
+
+// Mul возвращает произведение a·b
+func (f *GFq) Mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]+f.log[b]]
+}
+
+// Div возвращает частное a/b, b ≠ 0
+func (f *GFq) Div(a, b int) int {
+	if a == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]-f.log[b]+f.q-1]
+}
+
+// Inv возвращает обратный элемент 1/a, a ≠ 0
+func (f *GFq) Inv(a int) int { return f.exp[f.q-1-f.log[a]] }
+
+// checkSymbols проверяет длину вектора и принадлежность его элементов полю
+func (f *GFq) checkSymbols(name string, symbols []int, n int) error {
+	if len(symbols) != n {
+		return fmt.Errorf("%s: ожидалось %d символов, получено %d", name, n, len(symbols))
+	}
+	for i, s := range symbols {
+		if s < 0 || s >= f.q {
+			return fmt.Errorf("%s: символ %d равен %d и не принадлежит %s", name, i+1, s, f)
+		}
+	}
+	return nil
+}
+
+// RandomSymbols возвращает вектор из length случайных элементов GF(q)
+func RandomSymbols(length, q int, rng *rand.Rand) []int {
+	v := make([]int, length)
+	for i := range v {
+		v[i] = rng.Intn(q)
+	}
+	return v
+}
+
+// ParseSymbols разбирает вектор над GF(q): при q <= 10 допускается запись
+// цифрами подряд ("0121"), в общем случае — числа через запятую или пробел
+func ParseSymbols(s string, q int) ([]int, error) {
+	s = strings.TrimSpace(s)
+	var fields []string
+	if strings.ContainsAny(s, ", \t") {
+		fields = strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	} else if q <= 10 {
+		fields = strings.Split(s, "")
+	} else {
+		fields = []string{s}
+	}
+	symbols := make([]int, len(fields))
+	for i, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil || v < 0 || v >= q {
+			return nil, fmt.Errorf("символ %d (%q) должен быть целым числом от 0 до %d", i+1, field, q-1)
+		}
+		symbols[i] = v
+	}
+	return symbols, nil
+}
+
+// SymbolsToString записывает вектор над GF(q): цифрами подряд при q <= 10,
+// иначе числами через запятую
+func SymbolsToString(symbols []int, q int) string {
+	strs := make([]string, len(symbols))
+	for i, s := range symbols {
+		strs[i] = strconv.Itoa(s)
+	}
+	if q <= 10 {
+		return strings.Join(strs, "")
+	}
+	return strings.Join(strs, ",")
+}
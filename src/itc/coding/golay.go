@@ -0,0 +1,299 @@
+package coding
+
+import "fmt"
+
+// golayB — матрица B расширенного кода Голея: G = [I | B]. B симметрична и
+// B * B = I, поэтому H = [B | I], а синдром можно переводить умножением на B.
+var golayB = [12]string{
+	"110111000101",
+	"101110001011",
+	"011100010111",
+	"111000101101",
+	"110001011011",
+	"100010110111",
+	"000101101111",
+	"001011011101",
+	"010110111001",
+	"101101110001",
+	"011011100011",
+	"111111111110",
+}
+
+// Golay — двоичный код Голея: совершенный код (23,12,7) или расширенный
+// (24,12,8). Оба исправляют до трёх ошибок; расширенный код, кроме того,
+// обнаруживает четырёхкратные ошибки. Кодовое слово — 12 информационных
+// бит, за которыми следуют проверочные; код (23,12) получается из
+// расширенного выкалыванием последнего бита.
+type Golay struct {
+	n    int
+	b    [12][]int // строки матрицы B
+	g, h [][]int
+}
+
+// NewGolay строит совершенный код Голея (23,12,7)
+func NewGolay() *Golay { return newGolay(23) }
+
+// NewExtendedGolay строит расширенный код Голея (24,12,8)
+func NewExtendedGolay() *Golay { return newGolay(24) }
+
+func newGolay(n int) *Golay {
+	c := &Golay{n: n}
+	for i, row := range golayB {
+		c.b[i] = make([]int, 12)
+		for j, ch := range row {
+			c.b[i][j] = int(ch - '0')
+		}
+	}
+	r := n - 12
+	c.g = newMatrix(12, n)
+	for i := 0; i < 12; i++ {
+		c.g[i][i] = 1
+		for j := 0; j < r; j++ {
+			c.g[i][12+j] = c.b[i][j]
+		}
+	}
+	// H = [B' | I], B' — первые r столбцов B (B симметрична)
+	c.h = newMatrix(r, n)
+	for i := 0; i < r; i++ {
+		for j := 0; j < 12; j++ {
+			c.h[i][j] = c.b[j][i]
+		}
+		c.h[i][12+i] = 1
+	}
+	return c
+}
+
+// Name возвращает название кода
+func (c *Golay) Name() string {
+	if c.n == 24 {
+		return "Голей (24,12), расширенный"
+	}
+	return "Голей (23,12)"
+}
+
+// Length возвращает длину кодового слова n
+func (c *Golay) Length() int { return c.n }
+
+// Dimension возвращает число информационных бит k = 12
+func (c *Golay) Dimension() int { return 12 }
+
+// CorrectableErrors возвращает число исправляемых ошибок t = 3
+func (c *Golay) CorrectableErrors() int { return 3 }
+
+// GeneratorMatrix возвращает производящую матрицу G = [I | B]
+func (c *Golay) GeneratorMatrix() [][]int { return copyMatrix(c.g) }
+
+// ParityCheckMatrix возвращает проверочную матрицу H = [B | I]
+func (c *Golay) ParityCheckMatrix() [][]int { return copyMatrix(c.h) }
+
+// Encode дописывает к 12 информационным битам проверочные: data * B
+func (c *Golay) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, 12); err != nil {
+		return nil, err
+	}
+	checks := c.timesB(data)
+	return append(append([]int(nil), data...), checks[:c.n-12]...), nil
+}
+
+// weight возвращает вес Хэмминга вектора
+func weight(v []int) int {
+	w := 0
+	for _, b := range v {
+		w += b
+	}
+	return w
+}
+
+// xorBits возвращает сумму векторов одинаковой длины по модулю 2
+func xorBits(a, b []int) []int {
+	s := make([]int, len(a))
+	for i := range a {
+		s[i] = a[i] ^ b[i]
+	}
+	return s
+}
+
+// timesB умножает вектор-строку на матрицу B
+func (c *Golay) timesB(v []int) []int {
+	res := make([]int, 12)
+	for i, bit := range v {
+		if bit == 1 {
+			res = xorBits(res, c.b[i])
+		}
+	}
+	return res
+}
+
+// decodeExtended находит вектор ошибок веса не больше 3 для слова длины 24
+// арифметическим методом: синдром s = w1 + w2*B, затем проверяются s, s + b_i,
+// s*B и s*B + b_i. Если ни один вариант не подходит, ошибок не меньше четырёх.
+func (c *Golay) decodeExtended(w []int) ([]int, bool) {
+	s := xorBits(w[:12], c.timesB(w[12:]))
+	errVec := func(left, right []int) []int {
+		return append(append([]int(nil), left...), right...)
+	}
+	zero := make([]int, 12)
+	unit := func(i int) []int {
+		u := make([]int, 12)
+		u[i] = 1
+		return u
+	}
+
+	if weight(s) <= 3 {
+		return errVec(s, zero), true
+	}
+	for i := 0; i < 12; i++ {
+		if t := xorBits(s, c.b[i]); weight(t) <= 2 {
+			return errVec(t, unit(i)), true
+		}
+	}
+	sB := c.timesB(s)
+	if weight(sB) <= 3 {
+		return errVec(zero, sB), true
+	}
+	for i := 0; i < 12; i++ {
+		if t := xorBits(sB, c.b[i]); weight(t) <= 2 {
+			return errVec(unit(i), t), true
+		}
+	}
+	return nil, false
+}
+
+// Decode исправляет до трёх ошибок. Слово длины 23 дополняется битом до
+// нечётного веса: в полученном слове длины 24 нечётное число ошибок, не
+// больше трёх, если в исходном их было не больше трёх.
+func (c *Golay) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.n); err != nil {
+		return DecodeResult{}, err
+	}
+	res := DecodeResult{
+		Codeword: append([]int(nil), received...),
+		Syndrome: Multiply(c.h, received),
+	}
+	w := res.Codeword
+	if c.n == 23 {
+		w = append(append([]int(nil), received...), 1-weight(received)%2)
+	}
+	errors, ok := c.decodeExtended(w)
+	switch {
+	case !ok:
+		res.Status = StatusDetected
+	case weight(errors[:c.n]) == 0:
+		res.Status = StatusOK
+	default:
+		res.Status = StatusCorrected
+		for i := 0; i < c.n; i++ {
+			if errors[i] == 1 {
+				res.Codeword[i] ^= 1
+				res.ErrorPositions = append(res.ErrorPositions, i+1)
+			}
+		}
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	res.Data = append([]int(nil), res.Codeword[:12]...)
+	return res, nil
+}
+
+// PerfectReport — итоги полного перебора векторов ошибок веса не больше t
+type PerfectReport struct {
+	Code        string
+	N, K, T     int
+	MinDistance int
+	Patterns    int  // векторов ошибок веса 0..t
+	Syndromes   int  // различных синдромов у этих векторов
+	Cosets      int  // 2^(n-k) — всего синдромов
+	Perfect     bool // шары радиуса t покрывают пространство без пересечений
+	Decoded     int  // векторов ошибок, которые декодер исправил правильно
+}
+
+// VerifyPerfect перебирает все векторы ошибок веса не больше t: их синдромы
+// должны быть различны, а код совершенен, если их ровно 2^(n-k) (граница
+// Хэмминга достигается). Каждый вектор ошибок также подаётся на декодер
+// вместе с кодовым словом codeword, а минимальное расстояние считается
+// перебором всех 2^k кодовых слов.
+func VerifyPerfect(code LinearCode, t int, codeword []int) (PerfectReport, error) {
+	n, k := code.Length(), code.Dimension()
+	H := code.ParityCheckMatrix()
+	r := len(H)
+	if k > 24 || r > 24 {
+		return PerfectReport{}, fmt.Errorf("%s: полный перебор для k = %d, r = %d слишком долгий", code.Name(), k, r)
+	}
+	if t < 0 || t > n {
+		return PerfectReport{}, fmt.Errorf("число ошибок t = %d вне диапазона 0..%d", t, n)
+	}
+	rep := PerfectReport{Code: code.Name(), N: n, K: k, T: t, Cosets: 1 << r, MinDistance: minDistance(code)}
+
+	// Столбцы H как числа: синдром вектора ошибок — XOR столбцов его единиц
+	columns := make([]int, n)
+	for j := range columns {
+		for i := range H {
+			columns[j] |= H[i][j] << i
+		}
+	}
+	seen := make([]bool, 1<<r)
+	positions := make([]int, 0, t)
+	var walk func(start, syndrome int) error
+	walk = func(start, syndrome int) error {
+		rep.Patterns++
+		if !seen[syndrome] {
+			seen[syndrome] = true
+			rep.Syndromes++
+		}
+		received := append([]int(nil), codeword...)
+		for _, pos := range positions {
+			received[pos] ^= 1
+		}
+		res, err := code.Decode(received)
+		if err != nil {
+			return err
+		}
+		if BitsEqual(res.Codeword, codeword) {
+			rep.Decoded++
+		}
+		if len(positions) == t {
+			return nil
+		}
+		for pos := start; pos < n; pos++ {
+			positions = append(positions, pos)
+			if err := walk(pos+1, syndrome^columns[pos]); err != nil {
+				return err
+			}
+			positions = positions[:len(positions)-1]
+		}
+		return nil
+	}
+	if err := walk(0, 0); err != nil {
+		return rep, err
+	}
+	rep.Perfect = rep.Syndromes == rep.Patterns && rep.Patterns == rep.Cosets
+	return rep, nil
+}
+
+// minDistance перебирает все ненулевые кодовые слова и возвращает наименьший вес
+func minDistance(code LinearCode) int {
+	G := code.GeneratorMatrix()
+	n, k := code.Length(), code.Dimension()
+	best := n
+	word := make([]int, n)
+	// Код Грея: соседние сообщения отличаются одним битом, слово обновляется одной строкой G
+	for m := 1; m < 1<<k; m++ {
+		row := G[trailingZeros(m)]
+		for j := range word {
+			word[j] ^= row[j]
+		}
+		if w := weight(word); w < best {
+			best = w
+		}
+	}
+	return best
+}
+
+// trailingZeros возвращает число младших нулевых бит m > 0
+func trailingZeros(m int) int {
+	z := 0
+	for m&1 == 0 {
+		m >>= 1
+		z++
+	}
+	return z
+}
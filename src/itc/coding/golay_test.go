@@ -0,0 +1,44 @@
+package coding
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestGolayVerifyPerfect проверяет минимальные расстояния кодов Голея,
+// совершенность (23,12) и исправление всех векторов ошибок веса до 3 для
+// нескольких кодовых слов
+func TestGolayVerifyPerfect(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, tc := range []struct {
+		code     *Golay
+		distance int
+		perfect  bool
+	}{
+		{NewGolay(), 7, true},
+		{NewExtendedGolay(), 8, false},
+	} {
+		for i := 0; i < 4; i++ {
+			word, err := tc.code.Encode(RandomBits(tc.code.Dimension(), rng))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rep, err := VerifyPerfect(tc.code, tc.code.CorrectableErrors(), word)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.code.Name(), err)
+			}
+			if rep.MinDistance != tc.distance {
+				t.Errorf("%s: минимальное расстояние %d, ожидалось %d", rep.Code, rep.MinDistance, tc.distance)
+			}
+			if rep.Perfect != tc.perfect {
+				t.Errorf("%s: совершенный = %v, ожидалось %v", rep.Code, rep.Perfect, tc.perfect)
+			}
+			if rep.Syndromes != rep.Patterns {
+				t.Errorf("%s: %d векторов ошибок дают лишь %d различных синдромов", rep.Code, rep.Patterns, rep.Syndromes)
+			}
+			if rep.Decoded != rep.Patterns {
+				t.Errorf("%s: исправлено %d векторов ошибок из %d", rep.Code, rep.Decoded, rep.Patterns)
+			}
+		}
+	}
+}
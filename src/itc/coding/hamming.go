@@ -0,0 +1,196 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+)
+
+// SystematicHamming — код Хэмминга в систематической форме (лабораторная
+// работа 4): кодовое слово состоит из k информационных бит, за которыми
+// следуют p = n - k проверочных бит.
+type SystematicHamming struct {
+	n, k, p int
+	g       [][]int // производящая матрица G = [U_k | H_p]
+	h       [][]int // проверочная матрица H = [H_p^T | I_p]
+	// errorPos — позиция ошибки (с 0) по значению синдрома, -1 — столбца нет
+	errorPos []int
+}
+
+// CalculateN подбирает длину кода n для k информационных бит по границе
+// Хэмминга 2^k <= 2^n / (1 + n)
+func CalculateN(k int) int {
+	n := k + 1
+	for math.Pow(2, float64(k)) > math.Pow(2, float64(n))/(1+float64(n)) {
+		n++
+	}
+	return n
+}
+
+// NewSystematicHamming строит систематический код Хэмминга для k информационных бит
+func NewSystematicHamming(k int) (*SystematicHamming, error) {
+	if k <= 0 || k > 57 {
+		return nil, fmt.Errorf("число информационных бит k = %d вне диапазона 1..57", k)
+	}
+	n := CalculateN(k)
+	p := n - k
+	g := buildGeneratorMatrix(n, p, k)
+	c := &SystematicHamming{n: n, k: k, p: p, g: g, h: buildParityCheckMatrix(g, n, p, k)}
+	c.errorPos = buildSyndromeTable(c.h, n, p)
+	return c, nil
+}
+
+// buildSyndromeTable строит таблицу синдромов: для каждого значения синдрома
+// (младший бит — первая строка H) — номер совпадающего столбца H или -1
+func buildSyndromeTable(H [][]int, n, p int) []int {
+	table := make([]int, 1<<p)
+	for s := range table {
+		table[s] = -1
+	}
+	for pos := n - 1; pos >= 0; pos-- {
+		column := 0
+		for j := 0; j < p; j++ {
+			column |= H[j][pos] << j
+		}
+		table[column] = pos
+	}
+	return table
+}
+
+// buildGeneratorMatrix строит производящую матрицу G = [U_k | H_p]. Столбцы H_p —
+// двоичные записи чисел, не являющихся степенями двойки; последняя строка —
+// все единицы.
+func buildGeneratorMatrix(n, p, k int) [][]int {
+	G := newMatrix(k, n)
+
+	d := 3
+	for i := 0; i < k; i++ {
+		// U_k — единичная матрица
+		for j := 0; j < k; j++ {
+			G[i][j] = boolToInt(i == j)
+		}
+
+		// Увеличиваем d при достижении степени 2
+		if i+d == 1<<(d-1) {
+			d++
+		}
+
+		if i < k-1 {
+			num := i + d
+			for j := 0; j < p; j++ {
+				G[i][k+j] = (num >> (p - 1 - j)) & 1
+			}
+		} else {
+			for j := 0; j < p; j++ {
+				G[i][k+j] = 1
+			}
+		}
+	}
+	return G
+}
+
+// buildParityCheckMatrix строит проверочную матрицу H = [H_p | I_p]
+func buildParityCheckMatrix(G [][]int, n, p, k int) [][]int {
+	H := newMatrix(p, n)
+
+	// Копируем H_p
+	for i := 0; i < p; i++ {
+		for j := 0; j < k; j++ {
+			H[i][j] = G[j][k+i]
+		}
+	}
+
+	// I_p — единичная матрица в правой части
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			H[i][k+j] = boolToInt(i == j)
+		}
+	}
+	return H
+}
+
+// Name возвращает название кода
+func (c *SystematicHamming) Name() string {
+	return fmt.Sprintf("Хэмминг (%d,%d), систематический", c.n, c.k)
+}
+
+// Length возвращает длину кодового слова n
+func (c *SystematicHamming) Length() int { return c.n }
+
+// Dimension возвращает число информационных бит k
+func (c *SystematicHamming) Dimension() int { return c.k }
+
+// Redundancy возвращает число проверочных бит p
+func (c *SystematicHamming) Redundancy() int { return c.p }
+
+// GeneratorMatrix возвращает копию производящей матрицы G
+func (c *SystematicHamming) GeneratorMatrix() [][]int { return copyMatrix(c.g) }
+
+// ParityCheckMatrix возвращает копию проверочной матрицы H
+func (c *SystematicHamming) ParityCheckMatrix() [][]int { return copyMatrix(c.h) }
+
+// Encode добавляет к сообщению проверочные биты
+func (c *SystematicHamming) Encode(msg []int) ([]int, error) {
+	if err := checkBits("сообщение", msg, c.k); err != nil {
+		return nil, err
+	}
+	codeword := make([]int, c.n)
+	copy(codeword, msg)
+
+	// Вычисляем проверочные биты
+	for i := 0; i < c.p; i++ {
+		sum := 0
+		for j := 0; j < c.k; j++ {
+			if c.h[i][j] == 1 {
+				sum += codeword[j]
+			}
+		}
+		codeword[c.k+i] = sum % 2
+	}
+	return codeword, nil
+}
+
+// Syndrome вычисляет синдром принятого слова
+func (c *SystematicHamming) Syndrome(received []int) []int {
+	syndrome := make([]int, c.p)
+	for i := 0; i < c.p; i++ {
+		sum := received[c.k+i]
+		for j := 0; j < c.k; j++ {
+			if c.h[i][j] == 1 {
+				sum += received[j]
+			}
+		}
+		syndrome[i] = sum % 2
+	}
+	return syndrome
+}
+
+// FindErrorPosition возвращает номер (с 0) столбца H, совпадающего с
+// синдромом, по таблице синдромов за O(1). Проверяются и информационные,
+// и проверочные позиции.
+func (c *SystematicHamming) FindErrorPosition(syndrome []int) (int, bool) {
+	pos := c.errorPos[SyndromeIndex(syndrome)]
+	return pos, pos >= 0
+}
+
+// Decode вычисляет синдром, исправляет однократную ошибку и извлекает сообщение
+func (c *SystematicHamming) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.n); err != nil {
+		return DecodeResult{}, err
+	}
+	res := DecodeResult{
+		Codeword: append([]int(nil), received...),
+		Syndrome: c.Syndrome(received),
+	}
+	if SyndromeIndex(res.Syndrome) != 0 {
+		pos, found := c.FindErrorPosition(res.Syndrome)
+		if found {
+			res.Codeword[pos] ^= 1
+			res.ErrorPos = pos + 1
+			res.Status = StatusCorrected
+		} else {
+			res.Status = StatusDetected
+		}
+	}
+	res.Data = append([]int(nil), res.Codeword[:c.k]...)
+	return res, nil
+}
@@ -0,0 +1,421 @@
+package coding
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"strings"
+)
+
+// HDLLanguage — язык описания аппаратуры
+type HDLLanguage string
+
+const (
+	HDLVerilog HDLLanguage = "verilog"
+	HDLVHDL    HDLLanguage = "vhdl"
+)
+
+// ParseHDLLanguage проверяет название языка описания аппаратуры
+func ParseHDLLanguage(s string) (HDLLanguage, error) {
+	switch l := HDLLanguage(strings.ToLower(s)); l {
+	case HDLVerilog, HDLVHDL:
+		return l, nil
+	}
+	return "", fmt.Errorf("неизвестный язык %q (ожидается verilog или vhdl)", s)
+}
+
+// Ext возвращает расширение файлов на языке l
+func (l HDLLanguage) Ext() string {
+	if l == HDLVHDL {
+		return "vhd"
+	}
+	return "v"
+}
+
+// hdlIdent — допустимое имя модуля: буква, затем буквы, цифры и подчёркивания
+var hdlIdent = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// HDLVector — тестовый вектор: данные, кодовое слово и ожидаемый результат
+// декодирования принятого слова, полученные кодером и декодером на Go
+type HDLVector struct {
+	Data, Codeword, Received []int
+	Result                   DecodeResult
+}
+
+// NewHDLVectors кодирует count случайных сообщений и вносит в каждое слово
+// от 0 до maxErrors ошибок; ожидаемый результат даёт декодер code
+func NewHDLVectors(code LinearCode, count, maxErrors int, rng *rand.Rand) ([]HDLVector, error) {
+	if maxErrors < 0 || maxErrors > code.Length() {
+		return nil, fmt.Errorf("число ошибок %d вне диапазона 0..%d", maxErrors, code.Length())
+	}
+	vectors := make([]HDLVector, count)
+	for i := range vectors {
+		v := &vectors[i]
+		v.Data = RandomBits(code.Dimension(), rng)
+		var err error
+		if v.Codeword, err = code.Encode(v.Data); err != nil {
+			return nil, err
+		}
+		// Первые векторы — без ошибок и с каждой кратностью по очереди
+		errors := i % (maxErrors + 1)
+		if i > maxErrors {
+			errors = rng.Intn(maxErrors + 1)
+		}
+		if v.Received, _, err = InjectErrors(v.Codeword, errors, rng); err != nil {
+			return nil, err
+		}
+		if v.Result, err = code.Decode(v.Received); err != nil {
+			return nil, err
+		}
+	}
+	return vectors, nil
+}
+
+// hdlEquations — уравнения кода: какие биты складываются по модулю 2
+type hdlEquations struct {
+	name    string
+	n, k, r int
+	dataPos []int   // позиция в слове каждого информационного бита
+	checks  [][]int // для каждой позиции слова — информационные биты проверочного уравнения; nil у информационных позиций
+	rows    [][]int // для каждой строки H — позиции принятого слова в синдроме
+	columns []int   // синдром однократной ошибки в каждой позиции; -1 — позиция не исправляется
+}
+
+func newHDLEquations(code LinearCode) (*hdlEquations, error) {
+	if err := checkSingleError(code); err != nil {
+		return nil, err
+	}
+	G, H := code.GeneratorMatrix(), code.ParityCheckMatrix()
+	e := &hdlEquations{name: code.Name(), n: code.Length(), k: code.Dimension(), r: len(H)}
+	if e.r > 62 {
+		return nil, fmt.Errorf("%s: синдром из %d бит слишком длинный", e.name, e.r)
+	}
+	var err error
+	if e.dataPos, err = dataPositions(code, G); err != nil {
+		return nil, err
+	}
+	isData := make([]bool, e.n)
+	for _, pos := range e.dataPos {
+		isData[pos] = true
+	}
+	e.checks = make([][]int, e.n)
+	for j := 0; j < e.n; j++ {
+		if isData[j] {
+			continue
+		}
+		e.checks[j] = []int{}
+		for i := 0; i < e.k; i++ {
+			if G[i][j] == 1 {
+				e.checks[j] = append(e.checks[j], i)
+			}
+		}
+	}
+	e.rows = make([][]int, e.r)
+	for i, row := range H {
+		for j, bit := range row {
+			if bit == 1 {
+				e.rows[i] = append(e.rows[i], j)
+			}
+		}
+	}
+	// Если столбцы совпадают, исправляется первая позиция — как в Tables
+	e.columns = make([]int, e.n)
+	seen := map[int]bool{0: true}
+	for j := range e.columns {
+		column := 0
+		for i := range H {
+			column |= H[i][j] << i
+		}
+		e.columns[j] = -1
+		if !seen[column] {
+			e.columns[j] = column
+			seen[column] = true
+		}
+	}
+	return e, nil
+}
+
+// bitString записывает число x в r двоичных разрядов, старший первым
+func bitString(x, r int) string {
+	return fmt.Sprintf("%0*b", r, x)
+}
+
+// msbFirst возвращает биты строкой со старшим (последним) битом первым —
+// так записываются константы в Verilog и VHDL
+func msbFirst(bits []int) string {
+	var sb strings.Builder
+	for i := len(bits) - 1; i >= 0; i-- {
+		sb.WriteByte(byte('0' + bits[i]))
+	}
+	return sb.String()
+}
+
+// xorTerms соединяет операнды операцией XOR; пустая сумма равна нулю
+func xorTerms(signal string, indexes []int, lang HDLLanguage) string {
+	if len(indexes) == 0 {
+		if lang == HDLVHDL {
+			return "'0'"
+		}
+		return "1'b0"
+	}
+	op, open, close := " ^ ", "[", "]"
+	if lang == HDLVHDL {
+		op, open, close = " xor ", "(", ")"
+	}
+	terms := make([]string, len(indexes))
+	for i, idx := range indexes {
+		terms[i] = fmt.Sprintf("%s%s%d%s", signal, open, idx, close)
+	}
+	return strings.Join(terms, op)
+}
+
+// WriteHDL выводит синтезируемые модули кодера <module>_encoder и декодера
+// <module>_decoder кода code. Кодер — дерево XOR для каждого проверочного
+// бита, декодер вычисляет синдром, сравнивает его со столбцами H и
+// инвертирует совпавшую позицию. Текст только в ASCII: не все САПР
+// принимают UTF-8.
+func WriteHDL(w io.Writer, code LinearCode, lang HDLLanguage, module string) error {
+	if !hdlIdent.MatchString(module) {
+		return fmt.Errorf("недопустимое имя модуля %q", module)
+	}
+	e, err := newHDLEquations(code)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if lang == HDLVHDL {
+		e.writeVHDL(&b, module)
+	} else {
+		e.writeVerilog(&b, module)
+	}
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// header — общий комментарий в начале файла
+func (e *hdlEquations) header(b *bytes.Buffer, comment, what string) {
+	fmt.Fprintf(b, "%s Generated by itc hdl. DO NOT EDIT.\n", comment)
+	fmt.Fprintf(b, "%s %s: n = %d, k = %d, r = %d.\n", comment, what, e.n, e.k, e.r)
+	fmt.Fprintf(b, "%s Bit i of a vector is codeword position i+1 (data bit i).\n\n", comment)
+}
+
+func (e *hdlEquations) writeVerilog(b *bytes.Buffer, module string) {
+	e.header(b, "//", "Encoder and syndrome decoder")
+	fmt.Fprintf(b, "module %s_encoder (\n", module)
+	fmt.Fprintf(b, "    input  wire [%d:0] data,\n", e.k-1)
+	fmt.Fprintf(b, "    output wire [%d:0] codeword\n);\n", e.n-1)
+	for j := 0; j < e.n; j++ {
+		fmt.Fprintf(b, "    assign codeword[%d] = %s;\n", j, e.encodeTerm(j, HDLVerilog))
+	}
+	fmt.Fprint(b, "endmodule\n\n")
+
+	fmt.Fprintf(b, "module %s_decoder (\n", module)
+	fmt.Fprintf(b, "    input  wire [%d:0] received,\n", e.n-1)
+	fmt.Fprintf(b, "    output wire [%d:0] data,\n", e.k-1)
+	fmt.Fprintf(b, "    output wire [%d:0] corrected,\n", e.n-1)
+	fmt.Fprintf(b, "    output wire [%d:0] syndrome,\n", e.r-1)
+	fmt.Fprint(b, "    output wire error_corrected,\n")
+	fmt.Fprint(b, "    output wire error_detected\n);\n")
+	fmt.Fprintf(b, "    wire [%d:0] flip;\n\n", e.n-1)
+	for i, row := range e.rows {
+		fmt.Fprintf(b, "    assign syndrome[%d] = %s;\n", i, xorTerms("received", row, HDLVerilog))
+	}
+	fmt.Fprintln(b)
+	for j, column := range e.columns {
+		if column < 0 {
+			fmt.Fprintf(b, "    assign flip[%d] = 1'b0;\n", j)
+		} else {
+			fmt.Fprintf(b, "    assign flip[%d] = (syndrome == %d'b%s);\n", j, e.r, bitString(column, e.r))
+		}
+	}
+	fmt.Fprint(b, "\n    assign corrected = received ^ flip;\n")
+	for i, pos := range e.dataPos {
+		fmt.Fprintf(b, "    assign data[%d] = corrected[%d];\n", i, pos)
+	}
+	fmt.Fprint(b, "    assign error_corrected = |flip;\n")
+	fmt.Fprint(b, "    assign error_detected = (|syndrome) & ~error_corrected;\n")
+	fmt.Fprint(b, "endmodule\n")
+}
+
+// encodeTerm возвращает выражение для бита j кодового слова
+func (e *hdlEquations) encodeTerm(j int, lang HDLLanguage) string {
+	if e.checks[j] != nil {
+		return xorTerms("data", e.checks[j], lang)
+	}
+	for i, pos := range e.dataPos {
+		if pos == j {
+			return xorTerms("data", []int{i}, lang)
+		}
+	}
+	return xorTerms("data", nil, lang)
+}
+
+// vhdlPort выводит объявление порта std_logic_vector
+func vhdlPort(name, dir string, width int, last bool) string {
+	end := ";"
+	if last {
+		end = ""
+	}
+	return fmt.Sprintf("        %-15s : %-3s std_logic_vector(%d downto 0)%s\n", name, dir, width-1, end)
+}
+
+func (e *hdlEquations) writeVHDL(b *bytes.Buffer, module string) {
+	e.header(b, "--", "Encoder and syndrome decoder")
+	fmt.Fprint(b, "library ieee;\nuse ieee.std_logic_1164.all;\n\n")
+	fmt.Fprintf(b, "entity %s_encoder is\n    port (\n", module)
+	fmt.Fprint(b, vhdlPort("data", "in", e.k, false))
+	fmt.Fprint(b, vhdlPort("codeword", "out", e.n, true))
+	fmt.Fprintf(b, "    );\nend entity;\n\narchitecture rtl of %s_encoder is\nbegin\n", module)
+	for j := 0; j < e.n; j++ {
+		fmt.Fprintf(b, "    codeword(%d) <= %s;\n", j, e.encodeTerm(j, HDLVHDL))
+	}
+	fmt.Fprint(b, "end architecture;\n\n")
+
+	fmt.Fprint(b, "library ieee;\nuse ieee.std_logic_1164.all;\n\n")
+	fmt.Fprintf(b, "entity %s_decoder is\n    port (\n", module)
+	fmt.Fprint(b, vhdlPort("received", "in", e.n, false))
+	fmt.Fprint(b, vhdlPort("data", "out", e.k, false))
+	fmt.Fprint(b, vhdlPort("corrected", "out", e.n, false))
+	fmt.Fprint(b, vhdlPort("syndrome", "out", e.r, false))
+	fmt.Fprintf(b, "        %-15s : out std_logic;\n", "error_corrected")
+	fmt.Fprintf(b, "        %-15s : out std_logic\n", "error_detected")
+	fmt.Fprintf(b, "    );\nend entity;\n\narchitecture rtl of %s_decoder is\n", module)
+	fmt.Fprintf(b, "    signal s     : std_logic_vector(%d downto 0);\n", e.r-1)
+	fmt.Fprintf(b, "    signal flip  : std_logic_vector(%d downto 0);\n", e.n-1)
+	fmt.Fprintf(b, "    signal fixed : std_logic_vector(%d downto 0);\n", e.n-1)
+	fmt.Fprint(b, "    signal any_flip : std_logic;\nbegin\n")
+	for i, row := range e.rows {
+		fmt.Fprintf(b, "    s(%d) <= %s;\n", i, xorTerms("received", row, HDLVHDL))
+	}
+	fmt.Fprintln(b)
+	for j, column := range e.columns {
+		if column < 0 {
+			fmt.Fprintf(b, "    flip(%d) <= '0';\n", j)
+		} else {
+			fmt.Fprintf(b, "    flip(%d) <= '1' when s = \"%s\" else '0';\n", j, bitString(column, e.r))
+		}
+	}
+	fmt.Fprint(b, "\n    fixed <= received xor flip;\n")
+	fmt.Fprint(b, "    any_flip <= '0' when flip = (flip'range => '0') else '1';\n")
+	fmt.Fprint(b, "    syndrome <= s;\n    corrected <= fixed;\n")
+	for i, pos := range e.dataPos {
+		fmt.Fprintf(b, "    data(%d) <= fixed(%d);\n", i, pos)
+	}
+	fmt.Fprint(b, "    error_corrected <= any_flip;\n")
+	fmt.Fprint(b, "    error_detected <= '1' when s /= (s'range => '0') and any_flip = '0' else '0';\n")
+	fmt.Fprint(b, "end architecture;\n")
+}
+
+// WriteHDLTestbench выводит самопроверяющийся тестовый модуl <module>_tb:
+// он подаёт векторы на кодер и декодер и сравнивает выходы с результатами
+// кодера и декодера на Go. В конце печатается PASS или FAIL.
+func WriteHDLTestbench(w io.Writer, code LinearCode, lang HDLLanguage, module string, vectors []HDLVector) error {
+	if !hdlIdent.MatchString(module) {
+		return fmt.Errorf("недопустимое имя модуля %q", module)
+	}
+	e, err := newHDLEquations(code)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if lang == HDLVHDL {
+		e.writeVHDLTestbench(&b, module, vectors)
+	} else {
+		e.writeVerilogTestbench(&b, module, vectors)
+	}
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// statusBits возвращает ожидаемые флаги error_corrected и error_detected
+func statusBits(s DecodeStatus) (int, int) {
+	return boolToInt(s == StatusCorrected), boolToInt(s == StatusDetected)
+}
+
+func (e *hdlEquations) writeVerilogTestbench(b *bytes.Buffer, module string, vectors []HDLVector) {
+	e.header(b, "//", "Self-checking testbench")
+	fmt.Fprintf(b, "`timescale 1ns/1ps\n\nmodule %s_tb;\n", module)
+	fmt.Fprintf(b, "    reg  [%d:0] data;\n    wire [%d:0] codeword;\n", e.k-1, e.n-1)
+	fmt.Fprintf(b, "    reg  [%d:0] received;\n    wire [%d:0] decoded;\n", e.n-1, e.k-1)
+	fmt.Fprintf(b, "    wire [%d:0] corrected;\n    wire [%d:0] syndrome;\n", e.n-1, e.r-1)
+	fmt.Fprint(b, "    wire error_corrected, error_detected;\n    integer errors;\n\n")
+	fmt.Fprintf(b, "    %s_encoder enc (.data(data), .codeword(codeword));\n", module)
+	fmt.Fprintf(b, "    %s_decoder dec (.received(received), .data(decoded), .corrected(corrected),\n", module)
+	fmt.Fprint(b, "        .syndrome(syndrome), .error_corrected(error_corrected), .error_detected(error_detected));\n\n")
+
+	fmt.Fprint(b, "    task check;\n        input integer index;\n")
+	fmt.Fprintf(b, "        input [%d:0] d;\n        input [%d:0] c;\n        input [%d:0] r;\n", e.k-1, e.n-1, e.n-1)
+	fmt.Fprintf(b, "        input [%d:0] fixed;\n        input [%d:0] out;\n", e.n-1, e.k-1)
+	fmt.Fprint(b, "        input ec;\n        input ed;\n        begin\n")
+	fmt.Fprint(b, "            data = d;\n            received = r;\n            #1;\n")
+	fmt.Fprint(b, "            if (codeword !== c) begin\n")
+	b.WriteString("                $display(\"vector %0d: codeword %b, expected %b\", index, codeword, c);\n")
+	fmt.Fprint(b, "                errors = errors + 1;\n            end\n")
+	fmt.Fprint(b, "            if (corrected !== fixed || decoded !== out || error_corrected !== ec || error_detected !== ed) begin\n")
+	b.WriteString("                $display(\"vector %0d: decoded %b (corrected %b, flags %b%b), expected %b (%b, %b%b)\",\n")
+	fmt.Fprint(b, "                    index, decoded, corrected, error_corrected, error_detected, out, fixed, ec, ed);\n")
+	fmt.Fprint(b, "                errors = errors + 1;\n            end\n        end\n    endtask\n\n")
+
+	fmt.Fprint(b, "    initial begin\n        errors = 0;\n")
+	for i, v := range vectors {
+		ec, ed := statusBits(v.Result.Status)
+		fmt.Fprintf(b, "        check(%d, %d'b%s, %d'b%s, %d'b%s, %d'b%s, %d'b%s, 1'b%d, 1'b%d);\n", i,
+			e.k, msbFirst(v.Data), e.n, msbFirst(v.Codeword), e.n, msbFirst(v.Received),
+			e.n, msbFirst(v.Result.Codeword), e.k, msbFirst(v.Result.Data), ec, ed)
+	}
+	fmt.Fprintf(b, "        if (errors == 0)\n            $display(\"PASS: %d vectors\");\n", len(vectors))
+	b.WriteString("        else\n            $display(\"FAIL: %0d mismatches\", errors);\n")
+	fmt.Fprint(b, "        $finish;\n    end\nendmodule\n")
+}
+
+func (e *hdlEquations) writeVHDLTestbench(b *bytes.Buffer, module string, vectors []HDLVector) {
+	e.header(b, "--", "Self-checking testbench")
+	fmt.Fprint(b, "library ieee;\nuse ieee.std_logic_1164.all;\n\n")
+	fmt.Fprintf(b, "entity %s_tb is\nend entity;\n\narchitecture sim of %s_tb is\n", module, module)
+	fmt.Fprint(b, "    type vector_t is record\n")
+	fmt.Fprintf(b, "        data      : std_logic_vector(%d downto 0);\n", e.k-1)
+	fmt.Fprintf(b, "        codeword  : std_logic_vector(%d downto 0);\n", e.n-1)
+	fmt.Fprintf(b, "        received  : std_logic_vector(%d downto 0);\n", e.n-1)
+	fmt.Fprintf(b, "        corrected : std_logic_vector(%d downto 0);\n", e.n-1)
+	fmt.Fprintf(b, "        decoded   : std_logic_vector(%d downto 0);\n", e.k-1)
+	fmt.Fprint(b, "        ec, ed    : std_logic;\n    end record;\n")
+	fmt.Fprint(b, "    type vectors_t is array (natural range <>) of vector_t;\n")
+	fmt.Fprintf(b, "    constant VECTORS : vectors_t(0 to %d) := (\n", len(vectors)-1)
+	for i, v := range vectors {
+		ec, ed := statusBits(v.Result.Status)
+		sep := ","
+		if i == len(vectors)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(b, "        %d => (\"%s\", \"%s\", \"%s\", \"%s\", \"%s\", '%d', '%d')%s\n", i,
+			msbFirst(v.Data), msbFirst(v.Codeword), msbFirst(v.Received),
+			msbFirst(v.Result.Codeword), msbFirst(v.Result.Data), ec, ed, sep)
+	}
+	fmt.Fprint(b, "    );\n")
+	fmt.Fprintf(b, "    signal data, decoded : std_logic_vector(%d downto 0);\n", e.k-1)
+	fmt.Fprintf(b, "    signal codeword, received, corrected : std_logic_vector(%d downto 0);\n", e.n-1)
+	fmt.Fprintf(b, "    signal syndrome : std_logic_vector(%d downto 0);\n", e.r-1)
+	fmt.Fprint(b, "    signal error_corrected, error_detected : std_logic;\nbegin\n")
+	fmt.Fprintf(b, "    enc: entity work.%s_encoder port map (data => data, codeword => codeword);\n", module)
+	fmt.Fprintf(b, "    dec: entity work.%s_decoder port map (received => received, data => decoded,\n", module)
+	fmt.Fprint(b, "        corrected => corrected, syndrome => syndrome,\n")
+	fmt.Fprint(b, "        error_corrected => error_corrected, error_detected => error_detected);\n\n")
+	fmt.Fprint(b, "    process\n        variable errors : natural := 0;\n    begin\n")
+	fmt.Fprint(b, "        for i in VECTORS'range loop\n")
+	fmt.Fprint(b, "            data <= VECTORS(i).data;\n            received <= VECTORS(i).received;\n")
+	fmt.Fprint(b, "            wait for 1 ns;\n")
+	fmt.Fprint(b, "            if codeword /= VECTORS(i).codeword then\n")
+	fmt.Fprint(b, "                report \"vector \" & integer'image(i) & \": codeword mismatch\" severity error;\n")
+	fmt.Fprint(b, "                errors := errors + 1;\n            end if;\n")
+	fmt.Fprint(b, "            if corrected /= VECTORS(i).corrected or decoded /= VECTORS(i).decoded\n")
+	fmt.Fprint(b, "                    or error_corrected /= VECTORS(i).ec or error_detected /= VECTORS(i).ed then\n")
+	fmt.Fprint(b, "                report \"vector \" & integer'image(i) & \": decoder mismatch\" severity error;\n")
+	fmt.Fprint(b, "                errors := errors + 1;\n            end if;\n        end loop;\n")
+	fmt.Fprint(b, "        if errors = 0 then\n")
+	fmt.Fprint(b, "            report \"PASS: \" & integer'image(VECTORS'length) & \" vectors\";\n")
+	fmt.Fprint(b, "        else\n")
+	fmt.Fprint(b, "            report \"FAIL: \" & integer'image(errors) & \" mismatches\" severity failure;\n")
+	fmt.Fprint(b, "        end if;\n        wait;\n    end process;\nend architecture;\n")
+}
@@ -0,0 +1,148 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Interleaver — перестановка позиций: на позицию i выхода попадает бит
+// входа с номером p[i]
+type Interleaver []int
+
+// Interleave переставляет LLR: out[i] = in[p[i]]
+func (p Interleaver) Interleave(in []float64) []float64 {
+	out := make([]float64, len(p))
+	for i, j := range p {
+		out[i] = in[j]
+	}
+	return out
+}
+
+// Deinterleave выполняет обратную перестановку: out[p[i]] = in[i]
+func (p Interleaver) Deinterleave(in []float64) []float64 {
+	out := make([]float64, len(p))
+	for i, j := range p {
+		out[j] = in[i]
+	}
+	return out
+}
+
+// check проверяет, что p — перестановка чисел 0..len(p)-1
+func (p Interleaver) check() error {
+	seen := make([]bool, len(p))
+	for i, j := range p {
+		if j < 0 || j >= len(p) || seen[j] {
+			return fmt.Errorf("перемежитель не является перестановкой: позиция %d повторяется или вне диапазона (элемент %d)", j, i+1)
+		}
+		seen[j] = true
+	}
+	return nil
+}
+
+// RandomInterleaver возвращает случайную перестановку длины k
+func RandomInterleaver(k int, rng *rand.Rand) Interleaver {
+	return Interleaver(rng.Perm(k))
+}
+
+// BlockInterleaver записывает k = rows*cols бит по строкам таблицы и читает их по столбцам
+func BlockInterleaver(rows, cols int) (Interleaver, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, fmt.Errorf("размеры блочного перемежителя %d x %d должны быть положительными", rows, cols)
+	}
+	p := make(Interleaver, 0, rows*cols)
+	for c := 0; c < cols; c++ {
+		for r := 0; r < rows; r++ {
+			p = append(p, r*cols+c)
+		}
+	}
+	return p, nil
+}
+
+// SRandomInterleaver строит S-случайную перестановку: любые два входа,
+// отстоящие меньше чем на s позиций, на выходе также отстоят не меньше чем
+// на s. Элементы выбираются случайно; при неудаче построение повторяется.
+func SRandomInterleaver(k, s int, rng *rand.Rand) (Interleaver, error) {
+	if s < 1 {
+		return nil, fmt.Errorf("разнос S-случайного перемежителя должен быть больше 0, получено %d", s)
+	}
+	const attempts = 100
+	for a := 0; a < attempts; a++ {
+		pool := rng.Perm(k)
+		p := make(Interleaver, 0, k)
+		for len(pool) > 0 {
+			found := -1
+			for t, cand := range pool {
+				ok := true
+				for back := 1; back <= s && back <= len(p); back++ {
+					if d := p[len(p)-back] - cand; d < s && d > -s {
+						ok = false
+						break
+					}
+				}
+				if ok {
+					found = t
+					break
+				}
+			}
+			if found < 0 {
+				break
+			}
+			p = append(p, pool[found])
+			pool = append(pool[:found], pool[found+1:]...)
+		}
+		if len(p) == k {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("не удалось построить S-случайный перемежитель длины %d с разносом %d за %d попыток (уменьшите S)", k, s, attempts)
+}
+
+// QPPInterleaver строит перемежитель на квадратичном полиноме перестановки
+// p(i) = (f1*i + f2*i²) mod k (как в LTE); f1 и f2 должны давать перестановку
+func QPPInterleaver(k, f1, f2 int) (Interleaver, error) {
+	p := make(Interleaver, k)
+	for i := range p {
+		p[i] = int((int64(f1)*int64(i) + int64(f2)*int64(i)%int64(k)*int64(i)) % int64(k))
+	}
+	if err := p.check(); err != nil {
+		return nil, fmt.Errorf("QPP (%d, %d) для k = %d: %w", f1, f2, k, err)
+	}
+	return p, nil
+}
+
+// ParseInterleaver строит перемежитель длины k по описанию: random,
+// block:строк (число строк должно делить k), srandom или srandom:S
+// (по умолчанию S = sqrt(k/2)/2: жадное построение надёжно находит такой
+// разнос, а близкий к sqrt(k/2) — обычно нет), qpp:f1:f2
+func ParseInterleaver(spec string, k int, rng *rand.Rand) (Interleaver, error) {
+	fields := strings.Split(strings.TrimSpace(spec), ":")
+	params := make([]int, len(fields)-1)
+	for i := range params {
+		v, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("перемежитель %q: параметры должны быть целыми", spec)
+		}
+		params[i] = v
+	}
+	switch {
+	case fields[0] == "random" && len(params) == 0:
+		return RandomInterleaver(k, rng), nil
+	case fields[0] == "block" && len(params) == 1:
+		if params[0] <= 0 || k%params[0] != 0 {
+			return nil, fmt.Errorf("блочный перемежитель: число строк %d должно делить k = %d", params[0], k)
+		}
+		return BlockInterleaver(params[0], k/params[0])
+	case fields[0] == "srandom" && len(params) <= 1:
+		s := int(math.Sqrt(float64(k)/2) / 2)
+		if len(params) == 1 {
+			s = params[0]
+		}
+		return SRandomInterleaver(k, max(s, 1), rng)
+	case fields[0] == "qpp" && len(params) == 2:
+		return QPPInterleaver(k, params[0], params[1])
+	}
+	return nil, fmt.Errorf("неизвестный перемежитель %q (ожидается random, block:строк, srandom[:S] или qpp:f1:f2)", spec)
+}
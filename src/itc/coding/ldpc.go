@@ -0,0 +1,709 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LDPCAlgorithm — алгоритм декодирования LDPC-кода распространением доверия
+type LDPCAlgorithm int
+
+const (
+	SumProduct LDPCAlgorithm = iota // сумма-произведение (правило гиперболического тангенса)
+	MinSum                          // минимум-сумма с нормирующим множителем
+)
+
+// String возвращает название алгоритма
+func (a LDPCAlgorithm) String() string {
+	if a == MinSum {
+		return "min-sum"
+	}
+	return "sum-product"
+}
+
+// ParseLDPCAlgorithm разбирает название алгоритма: sum-product или min-sum
+func ParseLDPCAlgorithm(s string) (LDPCAlgorithm, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "sum-product", "spa":
+		return SumProduct, nil
+	case "min-sum", "ms":
+		return MinSum, nil
+	}
+	return 0, fmt.Errorf("неизвестный алгоритм %q (ожидается sum-product или min-sum)", s)
+}
+
+// LDPCDecoder — параметры декодера распространения доверия
+type LDPCDecoder struct {
+	Algorithm     LDPCAlgorithm
+	MaxIterations int     // предел числа итераций; декодирование останавливается раньше при нулевом синдроме
+	Scale         float64 // нормирующий множитель сообщений min-sum (1 — без нормировки)
+	Crossover     float64 // вероятность ошибки ДСК, по которой Decode переводит биты в LLR
+}
+
+// DefaultLDPCDecoder возвращает параметры декодера по умолчанию
+func DefaultLDPCDecoder() LDPCDecoder {
+	return LDPCDecoder{Algorithm: SumProduct, MaxIterations: 50, Scale: 1, Crossover: 0.05}
+}
+
+// check проверяет параметры декодера
+func (d LDPCDecoder) check() error {
+	if d.MaxIterations <= 0 {
+		return fmt.Errorf("число итераций должно быть больше 0, получено %d", d.MaxIterations)
+	}
+	if d.Scale <= 0 || d.Scale > 1 {
+		return fmt.Errorf("множитель min-sum %g вне полуинтервала (0, 1]", d.Scale)
+	}
+	if d.Crossover <= 0 || d.Crossover >= 0.5 {
+		return fmt.Errorf("вероятность ошибки канала %g вне интервала (0, 0.5)", d.Crossover)
+	}
+	return nil
+}
+
+// LDPC — код с малой плотностью проверок на чётность, заданный разреженной
+// проверочной матрицей H размера m x n (граф Таннера: проверки и переменные).
+// Строки H могут быть линейно зависимы, поэтому k = n - rank(H) может быть
+// больше n - m. Кодер систематический: информационные биты занимают позиции
+// столбцов H, не ставших ведущими при приведении H к ступенчатому виду.
+type LDPC struct {
+	name    string
+	n, k    int
+	checks  [][]int // checks[i] — переменные проверки i (с 0)
+	vars    [][]int // vars[j] — проверки, в которые входит переменная j
+	dataPos []int   // позиции информационных бит (с 0)
+	parity  []ldpcParity
+	decoder LDPCDecoder
+
+	edgeStart []int   // рёбра проверки i: edgeStart[i]..edgeStart[i+1]-1
+	varEdges  [][]int // рёбра переменной j
+}
+
+// ldpcParity — проверочный бит кодера: сумма информационных бит с номерами data
+type ldpcParity struct {
+	pos  int
+	data []int
+}
+
+// NewLDPC строит код по спискам переменных каждой проверки (с 0)
+func NewLDPC(n int, checks [][]int) (*LDPC, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("длина кода должна быть больше 0, получено %d", n)
+	}
+	c := &LDPC{n: n, decoder: DefaultLDPCDecoder(), vars: make([][]int, n)}
+	for i, row := range checks {
+		row = append([]int(nil), row...)
+		sort.Ints(row)
+		for t, j := range row {
+			if j < 0 || j >= n {
+				return nil, fmt.Errorf("проверка %d: переменная %d вне диапазона 1..%d", i+1, j+1, n)
+			}
+			if t > 0 && row[t-1] == j {
+				return nil, fmt.Errorf("проверка %d: переменная %d указана дважды", i+1, j+1)
+			}
+			c.vars[j] = append(c.vars[j], i)
+		}
+		c.checks = append(c.checks, row)
+	}
+	if err := c.buildEncoder(); err != nil {
+		return nil, err
+	}
+	c.buildEdges()
+	c.name = fmt.Sprintf("LDPC (%d,%d)", c.n, c.k)
+	return c, nil
+}
+
+// NewLDPCFromMatrix строит код по плотной проверочной матрице
+func NewLDPCFromMatrix(H [][]int) (*LDPC, error) {
+	if len(H) == 0 {
+		return nil, fmt.Errorf("проверочная матрица пуста")
+	}
+	checks := make([][]int, len(H))
+	for i, row := range H {
+		if len(row) != len(H[0]) {
+			return nil, fmt.Errorf("строка %d проверочной матрицы имеет длину %d, ожидалось %d", i+1, len(row), len(H[0]))
+		}
+		for j, v := range row {
+			if v != 0 {
+				checks[i] = append(checks[i], j)
+			}
+		}
+	}
+	return NewLDPC(len(H[0]), checks)
+}
+
+// buildEncoder приводит H к ступенчатому виду над GF(2): ведущие столбцы
+// становятся проверочными позициями, остальные — информационными
+func (c *LDPC) buildEncoder() error {
+	words := (c.n + 63) / 64
+	rows := make([][]uint64, len(c.checks))
+	for i, row := range c.checks {
+		rows[i] = make([]uint64, words)
+		for _, j := range row {
+			rows[i][j/64] |= 1 << (j % 64)
+		}
+	}
+	var pivots []int
+	rank := 0
+	for col := 0; col < c.n && rank < len(rows); col++ {
+		w, bit := col/64, uint64(1)<<(col%64)
+		p := rank
+		for p < len(rows) && rows[p][w]&bit == 0 {
+			p++
+		}
+		if p == len(rows) {
+			continue
+		}
+		rows[rank], rows[p] = rows[p], rows[rank]
+		for i := range rows {
+			if i != rank && rows[i][w]&bit != 0 {
+				for t := range rows[i] {
+					rows[i][t] ^= rows[rank][t]
+				}
+			}
+		}
+		pivots = append(pivots, col)
+		rank++
+	}
+	c.k = c.n - rank
+	if c.k == 0 {
+		return fmt.Errorf("ранг проверочной матрицы равен длине кода %d: код не содержит информационных бит", c.n)
+	}
+	isPivot := make([]bool, c.n)
+	for _, col := range pivots {
+		isPivot[col] = true
+	}
+	index := make([]int, c.n)
+	for j := 0; j < c.n; j++ {
+		if !isPivot[j] {
+			index[j] = len(c.dataPos)
+			c.dataPos = append(c.dataPos, j)
+		}
+	}
+	// Строка r приведённой матрицы: x_pivot = сумма x_j по неведущим столбцам j строки
+	for r, col := range pivots {
+		p := ldpcParity{pos: col}
+		for w, word := range rows[r] {
+			for word != 0 {
+				j := w*64 + bits.TrailingZeros64(word)
+				word &= word - 1
+				if !isPivot[j] {
+					p.data = append(p.data, index[j])
+				}
+			}
+		}
+		c.parity = append(c.parity, p)
+	}
+	return nil
+}
+
+// buildEdges нумерует рёбра графа Таннера по проверкам
+func (c *LDPC) buildEdges() {
+	c.edgeStart = make([]int, len(c.checks)+1)
+	c.varEdges = make([][]int, c.n)
+	e := 0
+	for i, row := range c.checks {
+		c.edgeStart[i] = e
+		for _, j := range row {
+			c.varEdges[j] = append(c.varEdges[j], e)
+			e++
+		}
+	}
+	c.edgeStart[len(c.checks)] = e
+}
+
+// NewGallagerLDPC строит регулярный код Галлагера: H состоит из wc полос по
+// n/wr строк, первая полоса — wr единиц подряд в каждой строке, остальные —
+// случайные перестановки столбцов первой
+func NewGallagerLDPC(n, wc, wr int, rng *rand.Rand) (*LDPC, error) {
+	if wc < 1 || wr < 2 || n <= 0 || n%wr != 0 {
+		return nil, fmt.Errorf("код Галлагера: ожидается wc >= 1, wr >= 2 и n, кратное wr; получено n = %d, wc = %d, wr = %d", n, wc, wr)
+	}
+	band := n / wr
+	checks := make([][]int, 0, band*wc)
+	perm := make([]int, n)
+	for j := range perm {
+		perm[j] = j
+	}
+	for b := 0; b < wc; b++ {
+		if b > 0 {
+			rng.Shuffle(n, func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+		}
+		for i := 0; i < band; i++ {
+			checks = append(checks, append([]int(nil), perm[i*wr:(i+1)*wr]...))
+		}
+	}
+	c, err := NewLDPC(n, checks)
+	if err != nil {
+		return nil, err
+	}
+	c.name += fmt.Sprintf(", Галлагер (%d,%d)", wc, wr)
+	return c, nil
+}
+
+// NewPEGLDPC строит код с m проверками и заданными степенями переменных
+// алгоритмом PEG (progressive edge growth): рёбра добавляются по одному, и
+// каждое ведёт в проверку, наиболее удалённую от переменной в уже
+// построенном графе (при равенстве — в проверку наименьшей степени). Так
+// локальный обхват графа получается наибольшим.
+func NewPEGLDPC(m int, degrees []int, rng *rand.Rand) (*LDPC, error) {
+	n := len(degrees)
+	if n == 0 || m <= 0 {
+		return nil, fmt.Errorf("PEG: ожидается n > 0 и m > 0, получено n = %d, m = %d", n, m)
+	}
+	for j, d := range degrees {
+		if d < 1 || d > m {
+			return nil, fmt.Errorf("PEG: степень переменной %d равна %d, ожидается 1..%d", j+1, d, m)
+		}
+	}
+	order := make([]int, n)
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool { return degrees[order[a]] < degrees[order[b]] })
+
+	varAdj := make([][]int, n)
+	checkAdj := make([][]int, m)
+	reached := make([]bool, m)
+	seenVar := make([]bool, n)
+	for _, j := range order {
+		for t := 0; t < degrees[j]; t++ {
+			var candidates []int
+			if t == 0 {
+				candidates = make([]int, m)
+				for i := range candidates {
+					candidates[i] = i
+				}
+			} else {
+				candidates = pegCandidates(j, varAdj, checkAdj, reached, seenVar)
+			}
+			best := -1
+			ties := 0
+			for _, i := range candidates {
+				switch {
+				case best < 0 || len(checkAdj[i]) < len(checkAdj[best]):
+					best, ties = i, 1
+				case len(checkAdj[i]) == len(checkAdj[best]):
+					ties++
+					if rng.Intn(ties) == 0 {
+						best = i
+					}
+				}
+			}
+			varAdj[j] = append(varAdj[j], best)
+			checkAdj[best] = append(checkAdj[best], j)
+		}
+	}
+	c, err := NewLDPC(n, checkAdj)
+	if err != nil {
+		return nil, err
+	}
+	c.name += ", PEG"
+	return c, nil
+}
+
+// pegCandidates обходит граф в ширину от переменной j и возвращает проверки,
+// недостижимые на последнем уровне обхода: все недостижимые проверки, если
+// обход остановился, или проверки, впервые достигнутые на последнем уровне,
+// если он покрыл все проверки
+func pegCandidates(j int, varAdj, checkAdj [][]int, reached, seenVar []bool) []int {
+	for i := range reached {
+		reached[i] = false
+	}
+	for v := range seenVar {
+		seenVar[v] = false
+	}
+	seenVar[j] = true
+	frontier := append([]int(nil), varAdj[j]...)
+	count := 0
+	for _, i := range frontier {
+		reached[i] = true
+		count++
+	}
+	for {
+		var next []int
+		for _, i := range frontier {
+			for _, v := range checkAdj[i] {
+				if seenVar[v] {
+					continue
+				}
+				seenVar[v] = true
+				for _, i2 := range varAdj[v] {
+					if !reached[i2] {
+						next = append(next, i2)
+						reached[i2] = true
+					}
+				}
+			}
+		}
+		if len(next) == 0 || count+len(next) == len(reached) {
+			// Недостижимые проверки; если уровень покрыл все — проверки этого уровня
+			var res []int
+			for i, r := range reached {
+				if !r {
+					res = append(res, i)
+				}
+			}
+			if len(res) == 0 {
+				res = next
+			}
+			return res
+		}
+		count += len(next)
+		frontier = next
+	}
+}
+
+// ParseVariableDegrees строит степени n переменных по описанию: одно число
+// для регулярного кода ("3") или распределение "степень:доля" через запятую
+// ("2:0.5,3:0.3,8:0.2"). Число переменных каждой степени округляется методом
+// наибольшего остатка; переменные идут по возрастанию степени.
+func ParseVariableDegrees(spec string, n int) ([]int, error) {
+	spec = strings.TrimSpace(spec)
+	if d, err := strconv.Atoi(spec); err == nil {
+		degrees := make([]int, n)
+		for j := range degrees {
+			degrees[j] = d
+		}
+		return degrees, nil
+	}
+	type part struct {
+		degree int
+		share  float64
+		count  int
+		rest   float64
+	}
+	var parts []part
+	total := 0.0
+	for _, item := range strings.Split(spec, ",") {
+		ds, fs, ok := strings.Cut(strings.TrimSpace(item), ":")
+		d, err1 := strconv.Atoi(ds)
+		f, err2 := strconv.ParseFloat(fs, 64)
+		if !ok || err1 != nil || err2 != nil || d < 1 || f < 0 {
+			return nil, fmt.Errorf("неверный элемент распределения степеней %q (ожидается степень:доля)", item)
+		}
+		parts = append(parts, part{degree: d, share: f})
+		total += f
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("сумма долей распределения степеней должна быть больше 0")
+	}
+	assigned := 0
+	for i := range parts {
+		exact := parts[i].share / total * float64(n)
+		parts[i].count = int(exact)
+		parts[i].rest = exact - float64(parts[i].count)
+		assigned += parts[i].count
+	}
+	byRest := make([]int, len(parts))
+	for i := range byRest {
+		byRest[i] = i
+	}
+	sort.SliceStable(byRest, func(a, b int) bool { return parts[byRest[a]].rest > parts[byRest[b]].rest })
+	for t := 0; assigned < n; t++ {
+		parts[byRest[t%len(parts)]].count++
+		assigned++
+	}
+	sort.SliceStable(parts, func(a, b int) bool { return parts[a].degree < parts[b].degree })
+	var degrees []int
+	for _, p := range parts {
+		for t := 0; t < p.count; t++ {
+			degrees = append(degrees, p.degree)
+		}
+	}
+	return degrees, nil
+}
+
+// Name возвращает название кода
+func (c *LDPC) Name() string { return c.name }
+
+// Length возвращает длину кодового слова n
+func (c *LDPC) Length() int { return c.n }
+
+// Dimension возвращает число информационных бит k = n - rank(H)
+func (c *LDPC) Dimension() int { return c.k }
+
+// Checks возвращает число проверок m (строк H)
+func (c *LDPC) Checks() int { return len(c.checks) }
+
+// Edges возвращает число рёбер графа Таннера (единиц в H)
+func (c *LDPC) Edges() int { return c.edgeStart[len(c.checks)] }
+
+// CheckVariables возвращает переменные проверки i (с 0)
+func (c *LDPC) CheckVariables(i int) []int { return append([]int(nil), c.checks[i]...) }
+
+// VariableChecks возвращает проверки переменной j (с 0)
+func (c *LDPC) VariableChecks(j int) []int { return append([]int(nil), c.vars[j]...) }
+
+// DataPositions возвращает позиции информационных бит (с 1)
+func (c *LDPC) DataPositions() []int {
+	res := make([]int, len(c.dataPos))
+	for i, pos := range c.dataPos {
+		res[i] = pos + 1
+	}
+	return res
+}
+
+// Decoder возвращает параметры декодера
+func (c *LDPC) Decoder() LDPCDecoder { return c.decoder }
+
+// SetDecoder задаёт параметры декодера
+func (c *LDPC) SetDecoder(d LDPCDecoder) error {
+	if err := d.check(); err != nil {
+		return err
+	}
+	c.decoder = d
+	return nil
+}
+
+// GeneratorMatrix возвращает систематическую производящую матрицу k x n
+func (c *LDPC) GeneratorMatrix() [][]int {
+	G := newMatrix(c.k, c.n)
+	for t, pos := range c.dataPos {
+		G[t][pos] = 1
+	}
+	for _, p := range c.parity {
+		for _, t := range p.data {
+			G[t][p.pos] = 1
+		}
+	}
+	return G
+}
+
+// ParityCheckMatrix возвращает проверочную матрицу H размера m x n; при
+// линейно зависимых строках m больше n - k
+func (c *LDPC) ParityCheckMatrix() [][]int {
+	H := newMatrix(len(c.checks), c.n)
+	for i, row := range c.checks {
+		for _, j := range row {
+			H[i][j] = 1
+		}
+	}
+	return H
+}
+
+// Encode размещает данные на информационных позициях и вычисляет проверочные биты
+func (c *LDPC) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, c.k); err != nil {
+		return nil, err
+	}
+	word := make([]int, c.n)
+	for t, pos := range c.dataPos {
+		word[pos] = data[t]
+	}
+	for _, p := range c.parity {
+		sum := 0
+		for _, t := range p.data {
+			sum ^= data[t]
+		}
+		word[p.pos] = sum
+	}
+	return word, nil
+}
+
+// Syndrome вычисляет синдром H * w длины m
+func (c *LDPC) Syndrome(word []int) []int {
+	s := make([]int, len(c.checks))
+	for i, row := range c.checks {
+		for _, j := range row {
+			s[i] ^= word[j]
+		}
+	}
+	return s
+}
+
+// Girth возвращает обхват графа Таннера — длину кратчайшего цикла (0, если циклов нет)
+func (c *LDPC) Girth() int {
+	m := len(c.checks)
+	best := 0
+	dist := make([]int, c.n+m)
+	parent := make([]int, c.n+m)
+	neighbours := func(v int) []int {
+		if v < c.n {
+			res := make([]int, len(c.vars[v]))
+			for t, i := range c.vars[v] {
+				res[t] = c.n + i
+			}
+			return res
+		}
+		return c.checks[v-c.n]
+	}
+	for start := 0; start < c.n; start++ {
+		for v := range dist {
+			dist[v] = -1
+		}
+		dist[start], parent[start] = 0, -1
+		queue := []int{start}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			if best > 0 && 2*dist[v]+1 >= best {
+				break
+			}
+			for _, u := range neighbours(v) {
+				switch {
+				case dist[u] < 0:
+					dist[u], parent[u] = dist[v]+1, v
+					queue = append(queue, u)
+				case u != parent[v]:
+					if l := dist[u] + dist[v] + 1; best == 0 || l < best {
+						best = l
+					}
+				}
+			}
+		}
+	}
+	return best
+}
+
+// Decode переводит принятые биты в LLR = ±ln((1-p)/p), где p — вероятность
+// ошибки из параметров декодера, и декодирует их распространением доверия
+func (c *LDPC) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.n); err != nil {
+		return DecodeResult{}, err
+	}
+	return c.DecodeLLR(BSCLLR(received, c.decoder.Crossover))
+}
+
+// DecodeLLR декодирует слово по логарифмам отношения правдоподобия
+// ln P(0)/P(1) каждого бита. Итерации прекращаются, как только жёсткие
+// решения дают нулевой синдром; если этого не произошло за MaxIterations
+// итераций, слово помечается как ошибка, которую нельзя исправить.
+func (c *LDPC) DecodeLLR(llr []float64) (DecodeResult, error) {
+	if len(llr) != c.n {
+		return DecodeResult{}, fmt.Errorf("длина вектора LLR %d, ожидалось %d", len(llr), c.n)
+	}
+	hard := make([]int, c.n)
+	for j, l := range llr {
+		if l < 0 {
+			hard[j] = 1
+		}
+	}
+	res := DecodeResult{Syndrome: c.Syndrome(hard)}
+
+	edges := c.Edges()
+	toCheck := make([]float64, edges) // сообщения переменная -> проверка
+	toVar := make([]float64, edges)   // сообщения проверка -> переменная
+	for j, es := range c.varEdges {
+		for _, e := range es {
+			toCheck[e] = llr[j]
+		}
+	}
+	word := append([]int(nil), hard...)
+	converged := isZero(res.Syndrome)
+	for !converged && res.Iterations < c.decoder.MaxIterations {
+		res.Iterations++
+		for i := range c.checks {
+			from, to := c.edgeStart[i], c.edgeStart[i+1]
+			if c.decoder.Algorithm == MinSum {
+				c.checkMinSum(toCheck[from:to], toVar[from:to])
+			} else {
+				checkSumProduct(toCheck[from:to], toVar[from:to])
+			}
+		}
+		for j, es := range c.varEdges {
+			total := llr[j]
+			for _, e := range es {
+				total += toVar[e]
+			}
+			for _, e := range es {
+				toCheck[e] = total - toVar[e]
+			}
+			word[j] = 0
+			if total < 0 {
+				word[j] = 1
+			}
+		}
+		converged = isZero(c.Syndrome(word))
+	}
+
+	res.Codeword = word
+	res.Data = make([]int, c.k)
+	for t, pos := range c.dataPos {
+		res.Data[t] = word[pos]
+	}
+	for j := range word {
+		if word[j] != hard[j] {
+			res.ErrorPositions = append(res.ErrorPositions, j+1)
+		}
+	}
+	switch {
+	case !converged:
+		res.Status = StatusDetected
+	case len(res.ErrorPositions) == 0:
+		res.Status = StatusOK
+	default:
+		res.Status = StatusCorrected
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
+
+// maxLLR ограничивает модуль сообщений, чтобы atanh не обращался в бесконечность
+const maxLLR = 30
+
+// checkSumProduct вычисляет сообщения проверки по правилу гиперболического
+// тангенса: tanh(out_e/2) — произведение tanh(in/2) по остальным рёбрам.
+// Произведения без текущего ребра считаются проходами слева и справа.
+func checkSumProduct(in, out []float64) {
+	t := make([]float64, len(in))
+	for e, v := range in {
+		t[e] = math.Tanh(v / 2)
+	}
+	prefix := 1.0
+	for e := range in {
+		out[e] = prefix
+		prefix *= t[e]
+	}
+	suffix := 1.0
+	for e := len(in) - 1; e >= 0; e-- {
+		p := out[e] * suffix
+		suffix *= t[e]
+		v := 2 * math.Atanh(p)
+		out[e] = math.Max(-maxLLR, math.Min(maxLLR, v))
+	}
+}
+
+// checkMinSum вычисляет сообщения проверки приближением минимум-сумма:
+// знак — произведение знаков, модуль — наименьший модуль по остальным
+// рёбрам, умноженный на Scale
+func (c *LDPC) checkMinSum(in, out []float64) {
+	sign := 1.0
+	min1, min2, minAt := math.Inf(1), math.Inf(1), -1
+	for e, v := range in {
+		if v < 0 {
+			sign = -sign
+		}
+		a := math.Abs(v)
+		switch {
+		case a < min1:
+			min2, min1, minAt = min1, a, e
+		case a < min2:
+			min2 = a
+		}
+	}
+	for e, v := range in {
+		m := min1
+		if e == minAt {
+			m = min2
+		}
+		s := sign
+		if v < 0 {
+			s = -s
+		}
+		out[e] = s * c.decoder.Scale * m
+	}
+}
+
+// isZero проверяет, что все биты вектора нулевые
+func isZero(v []int) bool {
+	for _, b := range v {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
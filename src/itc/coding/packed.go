@@ -0,0 +1,202 @@
+package coding
+
+import "fmt"
+
+// bitRun — отрезок информационных бит, которые идут в кодовом слове подряд
+type bitRun struct {
+	data, word, length int // начало в данных, начало в слове, длина (не больше 64)
+}
+
+// PackedCode — линейный код, работающий с упакованными векторами BitVec:
+// информационные биты переносятся в слово отрезками по 64 бита, проверочные
+// биты и синдром вычисляются как чётность AND с масками (popcount), а
+// однократная ошибка находится по синдрому в таблице столбцов H.
+// Строится по любому LinearCode, в котором информационные биты входят в
+// кодовое слово без изменений (систематический, позиционный код, SECDED).
+type PackedCode struct {
+	code       LinearCode
+	n, k, r    int
+	runs       []bitRun
+	checkPos   []int    // позиции проверочных бит (с 0)
+	checkMasks []BitVec // информационные биты, входящие в каждый проверочный бит
+	h          []BitVec // строки проверочной матрицы
+	errorPos   map[uint64]int
+}
+
+// PackedResult — результат декодирования упакованного слова
+type PackedResult struct {
+	Data     BitVec
+	Codeword BitVec
+	Syndrome uint64 // бит i — i-я строка H
+	ErrorPos int    // позиция исправленной ошибки (с 1), 0 — ошибка не исправлялась
+	Status   DecodeStatus
+}
+
+// dataPositioner — код, который сам сообщает позиции информационных бит (с 1)
+type dataPositioner interface {
+	DataPositions() []int
+}
+
+// dataPositions возвращает позиции информационных бит (с 0): столбцы G,
+// совпадающие с единичными векторами
+func dataPositions(code LinearCode, G [][]int) ([]int, error) {
+	n, k := code.Length(), code.Dimension()
+	dataPos := make([]int, k)
+	if dp, ok := code.(dataPositioner); ok {
+		for i, pos := range dp.DataPositions() {
+			dataPos[i] = pos - 1
+		}
+		return dataPos, nil
+	}
+	for i := range dataPos {
+		dataPos[i] = -1
+		for j := 0; j < n && dataPos[i] < 0; j++ {
+			unit := true
+			for row := 0; row < k && unit; row++ {
+				unit = G[row][j] == boolToInt(row == i)
+			}
+			if unit {
+				dataPos[i] = j
+			}
+		}
+		if dataPos[i] < 0 {
+			return nil, fmt.Errorf("%s: информационный бит %d не входит в кодовое слово без изменений", code.Name(), i+1)
+		}
+	}
+	return dataPos, nil
+}
+
+// dataRuns разбивает позиции информационных бит на отрезки подряд идущих позиций
+func dataRuns(dataPos []int) []bitRun {
+	var runs []bitRun
+	for i, pos := range dataPos {
+		last := len(runs) - 1
+		if last >= 0 && runs[last].word+runs[last].length == pos && runs[last].length < 64 {
+			runs[last].length++
+		} else {
+			runs = append(runs, bitRun{data: i, word: pos, length: 1})
+		}
+	}
+	return runs
+}
+
+// NewPackedCode строит упакованный вариант кода code
+func NewPackedCode(code LinearCode) (*PackedCode, error) {
+	if err := checkSingleError(code); err != nil {
+		return nil, err
+	}
+	G, H := code.GeneratorMatrix(), code.ParityCheckMatrix()
+	n, k := code.Length(), code.Dimension()
+	c := &PackedCode{code: code, n: n, k: k, r: len(H), errorPos: make(map[uint64]int, n)}
+	if c.r > 64 {
+		return nil, fmt.Errorf("%s: синдром из %d бит не помещается в машинное слово", code.Name(), c.r)
+	}
+
+	dataPos, err := dataPositions(code, G)
+	if err != nil {
+		return nil, err
+	}
+	c.runs = dataRuns(dataPos)
+	isData := make([]bool, n)
+	for _, pos := range dataPos {
+		isData[pos] = true
+	}
+
+	for j := 0; j < n; j++ {
+		if isData[j] {
+			continue
+		}
+		mask := NewBitVec(k)
+		for i := 0; i < k; i++ {
+			mask.Set(i, G[i][j])
+		}
+		c.checkPos = append(c.checkPos, j)
+		c.checkMasks = append(c.checkMasks, mask)
+	}
+
+	c.h = make([]BitVec, c.r)
+	for i, row := range H {
+		c.h[i] = BitVecFromBits(row)
+	}
+	for j := 0; j < n; j++ {
+		var column uint64
+		for i := range H {
+			column |= uint64(H[i][j]) << i
+		}
+		if _, dup := c.errorPos[column]; !dup && column != 0 {
+			c.errorPos[column] = j
+		}
+	}
+	return c, nil
+}
+
+// Name возвращает название кода
+func (c *PackedCode) Name() string { return c.code.Name() }
+
+// Length возвращает длину кодового слова n
+func (c *PackedCode) Length() int { return c.n }
+
+// Dimension возвращает число информационных бит k
+func (c *PackedCode) Dimension() int { return c.k }
+
+// EncodeTo кодирует data в слово word без выделения памяти
+func (c *PackedCode) EncodeTo(word, data BitVec) {
+	for _, run := range c.runs {
+		word.SetUint64(run.word, run.length, data.Uint64(run.data, run.length))
+	}
+	for i, pos := range c.checkPos {
+		word.Set(pos, data.AndParity(c.checkMasks[i]))
+	}
+}
+
+// Encode кодирует информационные биты
+func (c *PackedCode) Encode(data BitVec) (BitVec, error) {
+	if err := data.checkLen("данные", c.k); err != nil {
+		return BitVec{}, err
+	}
+	word := NewBitVec(c.n)
+	c.EncodeTo(word, data)
+	return word, nil
+}
+
+// Syndrome вычисляет синдром H * word; бит i результата — i-я строка H
+func (c *PackedCode) Syndrome(word BitVec) uint64 {
+	var s uint64
+	for i, row := range c.h {
+		s |= uint64(word.AndParity(row)) << i
+	}
+	return s
+}
+
+// Correct исправляет однократную ошибку в слове на месте и возвращает итог
+// и позицию ошибки (с 1)
+func (c *PackedCode) Correct(word BitVec) (uint64, DecodeStatus, int) {
+	s := c.Syndrome(word)
+	if s == 0 {
+		return s, StatusOK, 0
+	}
+	pos, ok := c.errorPos[s]
+	if !ok {
+		return s, StatusDetected, 0
+	}
+	word.Flip(pos)
+	return s, StatusCorrected, pos + 1
+}
+
+// ExtractTo переносит информационные биты слова word в data
+func (c *PackedCode) ExtractTo(data, word BitVec) {
+	for _, run := range c.runs {
+		data.SetUint64(run.data, run.length, word.Uint64(run.word, run.length))
+	}
+}
+
+// Decode исправляет однократную ошибку и извлекает информационные биты
+func (c *PackedCode) Decode(received BitVec) (PackedResult, error) {
+	if err := received.checkLen("принятое слово", c.n); err != nil {
+		return PackedResult{}, err
+	}
+	res := PackedResult{Codeword: received.Clone(), Data: NewBitVec(c.k)}
+	res.Syndrome, res.Status, res.ErrorPos = c.Correct(res.Codeword)
+	c.ExtractTo(res.Data, res.Codeword)
+	return res, nil
+}
@@ -0,0 +1,70 @@
+package coding
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// benchPackedWords — число заранее подготовленных слов в бенчмарках
+const benchPackedWords = 256
+
+// newBenchSECDED64 строит упакованный SECDED(64) и случайные данные и
+// принятые слова с одной ошибкой
+func newBenchSECDED64(b *testing.B) (*PackedCode, []BitVec, []BitVec) {
+	code, err := NewSECDED(64)
+	if err != nil {
+		b.Fatal(err)
+	}
+	pc, err := NewPackedCode(code)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	data := make([]BitVec, benchPackedWords)
+	received := make([]BitVec, benchPackedWords)
+	for i := range data {
+		data[i] = RandomBitVec(pc.Dimension(), rng)
+		word, err := pc.Encode(data[i])
+		if err != nil {
+			b.Fatal(err)
+		}
+		if received[i], _, err = InjectErrorsVec(word, 1, rng); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return pc, data, received
+}
+
+func BenchmarkPackedSECDED64Encode(b *testing.B) {
+	pc, data, _ := newBenchSECDED64(b)
+	word := NewBitVec(pc.Length())
+	b.SetBytes(8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pc.EncodeTo(word, data[i%benchPackedWords])
+	}
+}
+
+func BenchmarkPackedSECDED64Syndrome(b *testing.B) {
+	pc, _, received := newBenchSECDED64(b)
+	b.SetBytes(8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pc.Syndrome(received[i%benchPackedWords])
+	}
+}
+
+func BenchmarkPackedSECDED64Decode(b *testing.B) {
+	pc, _, received := newBenchSECDED64(b)
+	scratch, out := NewBitVec(pc.Length()), NewBitVec(pc.Dimension())
+	b.SetBytes(8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(scratch.Words(), received[i%benchPackedWords].Words())
+		pc.Correct(scratch)
+		pc.ExtractTo(out, scratch)
+	}
+}
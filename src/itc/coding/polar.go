@@ -0,0 +1,658 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// maxPolarLength — наибольшая длина полярного кода
+const maxPolarLength = 1 << 20
+
+// PolarChannel — канал, для которого выбираются замороженные биты
+type PolarChannel int
+
+const (
+	PolarBEC PolarChannel = iota // двоичный стирающий канал с вероятностью стирания ε
+	PolarBSC                     // двоичный симметричный канал с вероятностью ошибки p
+)
+
+// String возвращает название канала
+func (ch PolarChannel) String() string {
+	if ch == PolarBSC {
+		return "bsc"
+	}
+	return "bec"
+}
+
+// PolarMethod — способ оценки надёжности синтезированных каналов
+type PolarMethod int
+
+const (
+	Bhattacharyya    PolarMethod = iota // параметры Бхаттачарьи Z (для ДСК — верхние оценки)
+	DensityEvolution                    // эволюция плотностей LLR, вероятности ошибки решения
+)
+
+// String возвращает название способа
+func (m PolarMethod) String() string {
+	if m == DensityEvolution {
+		return "de"
+	}
+	return "bhattacharyya"
+}
+
+// PolarDesign — канал и способ построения полярного кода
+type PolarDesign struct {
+	Channel PolarChannel
+	Param   float64 // ε для BEC, p для BSC
+	Method  PolarMethod
+}
+
+// ParsePolarDesign разбирает канал (bec или bsc) и способ (bhattacharyya или de)
+func ParsePolarDesign(channel string, param float64, method string) (PolarDesign, error) {
+	var d PolarDesign
+	switch strings.ToLower(channel) {
+	case "bec":
+		d.Channel = PolarBEC
+	case "bsc":
+		d.Channel = PolarBSC
+	default:
+		return d, fmt.Errorf("неизвестный канал %q (ожидается bec или bsc)", channel)
+	}
+	switch strings.ToLower(method) {
+	case "bhattacharyya", "z":
+		d.Method = Bhattacharyya
+	case "de", "density-evolution":
+		d.Method = DensityEvolution
+	default:
+		return d, fmt.Errorf("неизвестный способ построения %q (ожидается bhattacharyya или de)", method)
+	}
+	d.Param = param
+	return d, d.check()
+}
+
+// check проверяет параметр канала
+func (d PolarDesign) check() error {
+	if d.Channel == PolarBSC && (d.Param <= 0 || d.Param >= 0.5) {
+		return fmt.Errorf("вероятность ошибки ДСК %g вне интервала (0, 0.5)", d.Param)
+	}
+	if d.Channel == PolarBEC && (d.Param <= 0 || d.Param >= 1) {
+		return fmt.Errorf("вероятность стирания %g вне интервала (0, 1)", d.Param)
+	}
+	return nil
+}
+
+// String описывает построение, например "BSC(0.11), эволюция плотностей"
+func (d PolarDesign) String() string {
+	method := "Бхаттачарья"
+	if d.Method == DensityEvolution {
+		method = "эволюция плотностей"
+	}
+	return fmt.Sprintf("%s(%g), %s", strings.ToUpper(d.Channel.String()), d.Param, method)
+}
+
+// WorstLevel возвращает значение показателя надёжности для бесполезного
+// канала: Z = 1 или вероятность ошибки 1/2 при эволюции плотностей
+func (d PolarDesign) WorstLevel() float64 {
+	if d.Method == DensityEvolution {
+		return 0.5
+	}
+	return 1
+}
+
+// crossover возвращает вероятность ошибки ДСК для перевода принятых бит в
+// LLR: для BEC стёртые символы заменяются случайными битами, что даёт ДСК с
+// p = ε/2
+func (d PolarDesign) crossover() float64 {
+	if d.Channel == PolarBSC {
+		return d.Param
+	}
+	return d.Param / 2
+}
+
+// PolarReliability оценивает надёжность n синтезированных каналов u_0..u_{n-1}
+// (n — степень двойки): для способа Bhattacharyya — параметры Z, для эволюции
+// плотностей — вероятности ошибки решения при известных предыдущих битах.
+// Чем меньше значение, тем надёжнее канал. Старший бит номера канала
+// соответствует первому шагу преобразования: 0 — худший канал W⁻, 1 — лучший W⁺.
+func PolarReliability(n int, d PolarDesign) ([]float64, error) {
+	if n < 2 || n > maxPolarLength || n&(n-1) != 0 {
+		return nil, fmt.Errorf("длина полярного кода %d должна быть степенью двойки от 2 до %d", n, maxPolarLength)
+	}
+	if err := d.check(); err != nil {
+		return nil, err
+	}
+	m := bits.TrailingZeros(uint(n))
+	if d.Method == DensityEvolution && d.Channel == PolarBSC {
+		return densityEvolution(m, d.Param), nil
+	}
+	// Для BEC параметр Z равен вероятности стирания и рекурсия точна, поэтому
+	// эволюция плотностей совпадает со способом Бхаттачарьи. Рекурсия ведётся
+	// в логарифмах, чтобы малые Z не обращались в 0:
+	// ln Z⁺ = 2 ln Z, ln Z⁻ = ln(2Z - Z²) = ln Z + ln(2 - Z).
+	z0 := d.Param
+	if d.Channel == PolarBSC {
+		z0 = 2 * math.Sqrt(d.Param*(1-d.Param))
+	}
+	level := []float64{math.Log(z0)}
+	for step := 0; step < m; step++ {
+		next := make([]float64, 2*len(level))
+		for i, lz := range level {
+			next[2*i] = lz + math.Log(2-math.Exp(lz))
+			next[2*i+1] = 2 * lz
+		}
+		level = next
+	}
+	res := make([]float64, n)
+	for i, lz := range level {
+		res[i] = math.Exp(lz)
+		if d.Method == DensityEvolution {
+			// Для BEC вероятность ошибки решения — половина вероятности стирания
+			res[i] /= 2
+		}
+	}
+	return res, nil
+}
+
+// Параметры квантованной эволюции плотностей: LLR принимают значения
+// (i - deBins) * deStep, i = 0..2*deBins
+const (
+	deStep = 0.25
+	deBins = 120
+)
+
+// densityEvolution вычисляет вероятности ошибки решения синтезированных
+// каналов ДСК по квантованным плотностям LLR (передаётся нулевое слово):
+// в узле W⁺ плотности сворачиваются (LLR складываются), в узле W⁻ LLR
+// объединяются правилом гиперболического тангенса
+func densityEvolution(m int, p float64) []float64 {
+	size := 2*deBins + 1
+	quantize := func(l float64) int {
+		i := int(math.Round(l/deStep)) + deBins
+		return max(0, min(size-1, i))
+	}
+	// Таблица квантованного результата операции ⊞ для пар значений
+	boxplus := make([][]int32, size)
+	for i := range boxplus {
+		boxplus[i] = make([]int32, size)
+		a := math.Tanh(float64(i-deBins) * deStep / 2)
+		for j := range boxplus[i] {
+			b := math.Tanh(float64(j-deBins) * deStep / 2)
+			boxplus[i][j] = int32(quantize(2 * math.Atanh(math.Max(-1+1e-15, math.Min(1-1e-15, a*b)))))
+		}
+	}
+
+	l0 := math.Log((1 - p) / p)
+	start := make([]float64, size)
+	start[quantize(l0)] += 1 - p
+	start[quantize(-l0)] += p
+	level := [][]float64{start}
+	for step := 0; step < m; step++ {
+		next := make([][]float64, 2*len(level))
+		for t, dens := range level {
+			minus := make([]float64, size)
+			plus := make([]float64, size)
+			for i, a := range dens {
+				if a == 0 {
+					continue
+				}
+				for j, b := range dens {
+					if b == 0 {
+						continue
+					}
+					minus[boxplus[i][j]] += a * b
+					plus[max(0, min(size-1, i+j-deBins))] += a * b
+				}
+			}
+			next[2*t], next[2*t+1] = minus, plus
+		}
+		level = next
+	}
+	res := make([]float64, len(level))
+	for t, dens := range level {
+		for i := 0; i < deBins; i++ {
+			res[t] += dens[i]
+		}
+		res[t] += dens[deBins] / 2
+	}
+	return res
+}
+
+// Polar — полярный код Арикана длины n = 2^m: x = u * F^{⊗m}, F = [1 0; 1 1].
+// Биты u на наименее надёжных синтезированных каналах заморожены (равны 0),
+// на остальных передаются данные, за которыми может следовать CRC.
+// Декодер — последовательное исключение (SC) или, при размере списка L > 1,
+// последовательное исключение со списком (SCL): из L путей выбирается путь
+// с наименьшей метрикой, а при наличии CRC — лучший путь с верной CRC.
+type Polar struct {
+	n, m, k     int
+	design      PolarDesign
+	reliability []float64
+	frozen      []bool
+	info        []int // позиции u для данных и CRC (по возрастанию)
+	crc         *CRC
+	list        int
+}
+
+// NewPolar строит полярный код длины n с k информационными битами; при
+// crcBits > 0 к данным добавляется CRC этой длины, и она занимает
+// дополнительные надёжные каналы
+func NewPolar(n, k, crcBits int, d PolarDesign) (*Polar, error) {
+	reliability, err := PolarReliability(n, d)
+	if err != nil {
+		return nil, err
+	}
+	c := &Polar{n: n, m: bits.TrailingZeros(uint(n)), k: k, design: d, reliability: reliability, list: 1}
+	if crcBits > 0 {
+		crc, err := NewCRC(crcBits)
+		if err != nil {
+			return nil, err
+		}
+		c.crc = &crc
+	}
+	if k < 1 || k+c.crcBits() > n {
+		return nil, fmt.Errorf("число информационных бит k = %d (и CRC %d) вне диапазона 1..%d", k, c.crcBits(), n)
+	}
+	// Данные — на k + crc каналах с наименьшей ненадёжностью (при равенстве — с большим номером)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := reliability[order[a]], reliability[order[b]]
+		if ra != rb {
+			return ra < rb
+		}
+		return order[a] > order[b]
+	})
+	c.info = append([]int(nil), order[:k+c.crcBits()]...)
+	sort.Ints(c.info)
+	c.frozen = make([]bool, n)
+	for i := range c.frozen {
+		c.frozen[i] = true
+	}
+	for _, i := range c.info {
+		c.frozen[i] = false
+	}
+	return c, nil
+}
+
+// crcBits возвращает длину CRC (0 без CRC)
+func (c *Polar) crcBits() int {
+	if c.crc == nil {
+		return 0
+	}
+	return c.crc.Bits
+}
+
+// SetListSize задаёт размер списка декодера: 1 — SC, больше 1 — SCL
+func (c *Polar) SetListSize(list int) error {
+	if list < 1 {
+		return fmt.Errorf("размер списка должен быть больше 0, получено %d", list)
+	}
+	c.list = list
+	return nil
+}
+
+// Name возвращает название кода с CRC и размером списка
+func (c *Polar) Name() string {
+	name := fmt.Sprintf("Полярный (%d,%d)", c.n, c.k)
+	if c.crc != nil {
+		name += ", " + c.crc.String()
+	}
+	if c.list > 1 {
+		name += fmt.Sprintf(", SCL L=%d", c.list)
+	}
+	return name
+}
+
+// Length возвращает длину кодового слова n
+func (c *Polar) Length() int { return c.n }
+
+// Dimension возвращает число информационных бит k (без CRC)
+func (c *Polar) Dimension() int { return c.k }
+
+// Design возвращает параметры построения
+func (c *Polar) Design() PolarDesign { return c.design }
+
+// Reliability возвращает показатели ненадёжности синтезированных каналов
+func (c *Polar) Reliability() []float64 { return append([]float64(nil), c.reliability...) }
+
+// Frozen сообщает, заморожен ли бит u_i
+func (c *Polar) Frozen(i int) bool { return c.frozen[i] }
+
+// InformationPositions возвращает позиции u (с 0), на которых передаются
+// данные и CRC: первые k — данные, остальные — CRC
+func (c *Polar) InformationPositions() []int { return append([]int(nil), c.info...) }
+
+// polarTransform вычисляет x = u * F^{⊗m} на месте; преобразование обратно само себе
+func polarTransform(x []int) {
+	for step := 1; step < len(x); step <<= 1 {
+		for j := range x {
+			if j&step == 0 {
+				x[j] ^= x[j|step]
+			}
+		}
+	}
+}
+
+// Encode размещает данные и CRC на информационных позициях u и вычисляет x
+func (c *Polar) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, c.k); err != nil {
+		return nil, err
+	}
+	payload := data
+	if c.crc != nil {
+		payload = append(append([]int(nil), data...), c.crc.Compute(data)...)
+	}
+	x := make([]int, c.n)
+	for t, pos := range c.info {
+		x[pos] = payload[t]
+	}
+	polarTransform(x)
+	return x, nil
+}
+
+// Syndrome вычисляет синдром слова: u = x * F^{⊗m}, затем значения u на
+// замороженных позициях и, при наличии CRC, разность принятой и вычисленной CRC
+func (c *Polar) Syndrome(word []int) []int {
+	u := append([]int(nil), word...)
+	polarTransform(u)
+	var s []int
+	for i, f := range c.frozen {
+		if f {
+			s = append(s, u[i])
+		}
+	}
+	if c.crc != nil {
+		payload := make([]int, len(c.info))
+		for t, pos := range c.info {
+			payload[t] = u[pos]
+		}
+		s = append(s, xorBits(payload[c.k:], c.crc.Compute(payload[:c.k]))...)
+	}
+	return s
+}
+
+// GeneratorMatrix возвращает производящую матрицу: кодовые слова единичных векторов данных
+func (c *Polar) GeneratorMatrix() [][]int {
+	G := make([][]int, c.k)
+	for t := range G {
+		data := make([]int, c.k)
+		data[t] = 1
+		G[t], _ = c.Encode(data)
+	}
+	return G
+}
+
+// ParityCheckMatrix возвращает проверочную матрицу, строки которой
+// соответствуют битам синдрома Syndrome. Столбец j матрицы F^{⊗m} содержит
+// единицы в строках i, биты которых включают биты j.
+func (c *Polar) ParityCheckMatrix() [][]int {
+	column := func(j int) []int {
+		col := make([]int, c.n)
+		for i := range col {
+			if i&j == j {
+				col[i] = 1
+			}
+		}
+		return col
+	}
+	var H [][]int
+	for j, f := range c.frozen {
+		if f {
+			H = append(H, column(j))
+		}
+	}
+	if c.crc != nil {
+		// Бит CRC r равен сумме бит данных t, от которых он зависит
+		deps := make([][]int, c.k)
+		for t := range deps {
+			data := make([]int, c.k)
+			data[t] = 1
+			deps[t] = c.crc.Compute(data)
+		}
+		for r := 0; r < c.crc.Bits; r++ {
+			row := column(c.info[c.k+r])
+			for t := 0; t < c.k; t++ {
+				if deps[t][r] == 1 {
+					row = xorBits(row, column(c.info[t]))
+				}
+			}
+			H = append(H, row)
+		}
+	}
+	return H
+}
+
+// Decode переводит принятые биты в LLR для ДСК с вероятностью ошибки из
+// построения кода (для BEC — ε/2) и декодирует их
+func (c *Polar) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.n); err != nil {
+		return DecodeResult{}, err
+	}
+	return c.DecodeLLR(BSCLLR(received, c.design.crossover()))
+}
+
+// polarPath — путь декодера: LLR и частичные суммы узлов текущей ветви дерева
+type polarPath struct {
+	alpha        [][]float64 // alpha[d] — LLR узла глубины d (длина n >> d)
+	betaL, betaR [][]int     // кодовые слова левого и правого потомков на глубине d
+	u            []int
+	metric       float64
+}
+
+func newPolarPath(c *Polar, llr []float64) *polarPath {
+	p := &polarPath{
+		alpha: make([][]float64, c.m+1),
+		betaL: make([][]int, c.m+1),
+		betaR: make([][]int, c.m+1),
+		u:     make([]int, c.n),
+	}
+	for d := 0; d <= c.m; d++ {
+		p.alpha[d] = make([]float64, c.n>>d)
+		p.betaL[d] = make([]int, c.n>>d)
+		p.betaR[d] = make([]int, c.n>>d)
+	}
+	copy(p.alpha[0], llr)
+	return p
+}
+
+// copyFrom копирует состояние пути p в q той же длины
+func (q *polarPath) copyFrom(p *polarPath) {
+	for d := range p.alpha {
+		copy(q.alpha[d], p.alpha[d])
+		copy(q.betaL[d], p.betaL[d])
+		copy(q.betaR[d], p.betaR[d])
+	}
+	copy(q.u, p.u)
+	q.metric = p.metric
+}
+
+// leafLLR вычисляет LLR бита u_i. Для i > 0 пересчёт начинается с узла, где
+// ветви листьев i-1 и i расходятся: его правый потомок получает LLR по
+// правилу g, а далее до листа идут левые потомки по правилу f.
+func (p *polarPath) leafLLR(m, i int) float64 {
+	d := 0
+	if i > 0 {
+		d = m - 1 - bits.TrailingZeros(uint(i))
+		in, out, left := p.alpha[d], p.alpha[d+1], p.betaL[d+1]
+		h := len(out)
+		for j := 0; j < h; j++ {
+			out[j] = in[j+h] + float64(1-2*left[j])*in[j]
+		}
+		d++
+	}
+	for ; d < m; d++ {
+		in, out := p.alpha[d], p.alpha[d+1]
+		h := len(out)
+		for j := 0; j < h; j++ {
+			a, b := in[j], in[j+h]
+			v := math.Min(math.Abs(a), math.Abs(b))
+			if (a < 0) != (b < 0) {
+				v = -v
+			}
+			out[j] = v
+		}
+	}
+	return p.alpha[m][0]
+}
+
+// setBit записывает решение u_i и поднимает частичные суммы: кодовое слово
+// узла, завершённого правым потомком, равно (левое ⊕ правое, правое)
+func (p *polarPath) setBit(m, i, bit int) {
+	p.u[i] = bit
+	v := []int{bit}
+	for d := m; d > 0; d-- {
+		if i>>(m-d)&1 == 0 {
+			copy(p.betaL[d], v)
+			return
+		}
+		copy(p.betaR[d], v)
+		h := len(v)
+		parent := make([]int, 2*h)
+		for j := 0; j < h; j++ {
+			parent[j] = p.betaL[d][j] ^ v[j]
+			parent[j+h] = v[j]
+		}
+		v = parent
+	}
+}
+
+// softplus возвращает ln(1 + e^x) без переполнения
+func softplus(x float64) float64 {
+	if x > 30 {
+		return x
+	}
+	return math.Log1p(math.Exp(x))
+}
+
+// DecodeLLR декодирует слово по LLR ln P(0)/P(1) каждого бита x. Метрика
+// пути — сумма ln(1 + e^(-(1-2u)λ)) по принятым решениям u, где λ — LLR бита u.
+func (c *Polar) DecodeLLR(llr []float64) (DecodeResult, error) {
+	if len(llr) != c.n {
+		return DecodeResult{}, fmt.Errorf("длина вектора LLR %d, ожидалось %d", len(llr), c.n)
+	}
+	type candidate struct {
+		path, bit int
+		metric    float64
+	}
+	paths := []*polarPath{newPolarPath(c, llr)}
+	var free []*polarPath
+	for i := 0; i < c.n; i++ {
+		lambdas := make([]float64, len(paths))
+		for t, p := range paths {
+			lambdas[t] = p.leafLLR(c.m, i)
+		}
+		if c.frozen[i] {
+			for t, p := range paths {
+				p.metric += softplus(-lambdas[t])
+				p.setBit(c.m, i, 0)
+			}
+			continue
+		}
+		if c.list == 1 {
+			bit := 0
+			if lambdas[0] < 0 {
+				bit = 1
+			}
+			paths[0].metric += softplus(-float64(1-2*bit) * lambdas[0])
+			paths[0].setBit(c.m, i, bit)
+			continue
+		}
+		cands := make([]candidate, 0, 2*len(paths))
+		for t, p := range paths {
+			cands = append(cands,
+				candidate{t, 0, p.metric + softplus(-lambdas[t])},
+				candidate{t, 1, p.metric + softplus(lambdas[t])})
+		}
+		sort.SliceStable(cands, func(a, b int) bool { return cands[a].metric < cands[b].metric })
+		if len(cands) > c.list {
+			cands = cands[:c.list]
+		}
+		// Отброшенные пути освобождают память для копий продолжаемых
+		used := make([]int, len(paths))
+		for _, cd := range cands {
+			used[cd.path]++
+		}
+		for t, p := range paths {
+			if used[t] == 0 {
+				free = append(free, p)
+			}
+		}
+		next := make([]*polarPath, 0, len(cands))
+		taken := make([]bool, len(paths))
+		for _, cd := range cands {
+			p := paths[cd.path]
+			if taken[cd.path] {
+				var q *polarPath
+				if len(free) > 0 {
+					q, free = free[len(free)-1], free[:len(free)-1]
+				} else {
+					q = newPolarPath(c, llr)
+				}
+				q.copyFrom(p)
+				p = q
+			}
+			taken[cd.path] = true
+			next = append(next, p)
+		}
+		// Решения записываются после клонирования, чтобы копии не разделяли состояние
+		for t, cd := range cands {
+			next[t].metric = cd.metric
+			next[t].setBit(c.m, i, cd.bit)
+		}
+		paths = next
+	}
+
+	payload := func(p *polarPath) []int {
+		res := make([]int, len(c.info))
+		for t, pos := range c.info {
+			res[t] = p.u[pos]
+		}
+		return res
+	}
+	best := 0
+	for t := range paths {
+		if paths[t].metric < paths[best].metric {
+			best = t
+		}
+	}
+	crcOK := true
+	if c.crc != nil {
+		crcOK = false
+		for t := range paths {
+			if c.crc.Check(payload(paths[t])) && (!crcOK || paths[t].metric < paths[best].metric) {
+				best, crcOK = t, true
+			}
+		}
+	}
+
+	hard := make([]int, c.n)
+	for j, l := range llr {
+		if l < 0 {
+			hard[j] = 1
+		}
+	}
+	res := DecodeResult{Syndrome: c.Syndrome(hard)}
+	res.Data = payload(paths[best])[:c.k]
+	res.Codeword = append([]int(nil), paths[best].u...)
+	polarTransform(res.Codeword)
+	for j := range hard {
+		if hard[j] != res.Codeword[j] {
+			res.ErrorPositions = append(res.ErrorPositions, j+1)
+		}
+	}
+	switch {
+	case !crcOK:
+		res.Status = StatusDetected
+	case len(res.ErrorPositions) == 0:
+		res.Status = StatusOK
+	default:
+		res.Status = StatusCorrected
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
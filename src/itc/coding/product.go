@@ -0,0 +1,199 @@
+package coding
+
+import "fmt"
+
+// Product — произведение двух линейных кодов: информационные биты
+// записываются в таблицу k2 x k1, каждая строка кодируется кодом строк
+// (n1, k1), затем каждый столбец — кодом столбцов (n2, k2). Кодовое слово —
+// таблица n2 x n1 по строкам; все её строки — слова кода строк, все
+// столбцы — слова кода столбцов, минимальное расстояние равно d1·d2.
+// Декодирование итеративное: строки и столбцы поочерёдно декодируются
+// декодерами составляющих кодов, пока таблица не перестанет меняться.
+type Product struct {
+	row, col   LinearCode
+	rowH, colH [][]int
+	iterations int
+	checks     checkMatrix
+}
+
+// NewProduct строит произведение кода строк row и кода столбцов col
+func NewProduct(row, col LinearCode, iterations int) (*Product, error) {
+	c := &Product{row: row, col: col, rowH: row.ParityCheckMatrix(), colH: col.ParityCheckMatrix()}
+	if err := c.SetIterations(iterations); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Name возвращает название кода
+func (c *Product) Name() string {
+	return fmt.Sprintf("Произведение [%s] x [%s]", c.row.Name(), c.col.Name())
+}
+
+// Length возвращает длину кодового слова n1·n2
+func (c *Product) Length() int { return c.row.Length() * c.col.Length() }
+
+// Dimension возвращает число информационных бит k1·k2
+func (c *Product) Dimension() int { return c.row.Dimension() * c.col.Dimension() }
+
+// Components возвращает код строк и код столбцов
+func (c *Product) Components() (LinearCode, LinearCode) { return c.row, c.col }
+
+// Iterations возвращает наибольшее число итераций декодера
+func (c *Product) Iterations() int { return c.iterations }
+
+// SetIterations задаёт наибольшее число итераций «строки, затем столбцы»
+func (c *Product) SetIterations(iterations int) error {
+	if iterations < 1 {
+		return fmt.Errorf("число итераций должно быть больше 0, получено %d", iterations)
+	}
+	c.iterations = iterations
+	return nil
+}
+
+// column возвращает столбец j таблицы с шириной строки n1
+func column(word []int, j, n1, n2 int) []int {
+	col := make([]int, n2)
+	for i := range col {
+		col[i] = word[i*n1+j]
+	}
+	return col
+}
+
+// Encode кодирует строки таблицы данных, затем столбцы
+func (c *Product) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, c.Dimension()); err != nil {
+		return nil, err
+	}
+	n1, k1 := c.row.Length(), c.row.Dimension()
+	n2, k2 := c.col.Length(), c.col.Dimension()
+	rows := make([]int, 0, k2*n1)
+	for i := 0; i < k2; i++ {
+		r, err := c.row.Encode(data[i*k1 : (i+1)*k1])
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, r...)
+	}
+	word := make([]int, n1*n2)
+	for j := 0; j < n1; j++ {
+		col, err := c.col.Encode(column(rows, j, n1, k2))
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range col {
+			word[i*n1+j] = v
+		}
+	}
+	return word, nil
+}
+
+// GeneratorMatrix возвращает кронекерово произведение G2 ⊗ G1 в порядке
+// бит таблицы по строкам
+func (c *Product) GeneratorMatrix() [][]int {
+	G1, G2 := c.row.GeneratorMatrix(), c.col.GeneratorMatrix()
+	n1, k1 := c.row.Length(), c.row.Dimension()
+	G := newMatrix(c.Dimension(), c.Length())
+	for a, g2 := range G2 {
+		for b, g1 := range G1 {
+			for i, x := range g2 {
+				if x == 0 {
+					continue
+				}
+				for j, y := range g1 {
+					G[a*k1+b][i*n1+j] = y
+				}
+			}
+		}
+	}
+	return G
+}
+
+// ParityCheckMatrix возвращает проверочную матрицу — базис ядра G
+func (c *Product) ParityCheckMatrix() [][]int { return nullSpace(c.GeneratorMatrix()) }
+
+// Syndrome возвращает синдром слова по проверочной матрице
+func (c *Product) Syndrome(word []int) []int { return c.checks.syndrome(c, word) }
+
+// Decode исправляет ошибки поочерёдным декодированием строк и столбцов
+func (c *Product) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.Length()); err != nil {
+		return DecodeResult{}, err
+	}
+	n1, n2 := c.row.Length(), c.col.Length()
+	word := append([]int(nil), received...)
+	// fix декодирует вектор, если его синдром ненулевой, и сообщает, изменился ли он
+	fix := func(code LinearCode, H [][]int, v []int) ([]int, bool, error) {
+		if isZero(Multiply(H, v)) {
+			return v, false, nil
+		}
+		res, err := code.Decode(v)
+		if err != nil {
+			return nil, false, err
+		}
+		return res.Codeword, !BitsEqual(res.Codeword, v), nil
+	}
+	res := DecodeResult{Syndrome: c.Syndrome(received)}
+	for res.Iterations < c.iterations {
+		res.Iterations++
+		changed := false
+		for i := 0; i < n2; i++ {
+			r, ch, err := fix(c.row, c.rowH, word[i*n1:(i+1)*n1])
+			if err != nil {
+				return DecodeResult{}, err
+			}
+			copy(word[i*n1:], r)
+			changed = changed || ch
+		}
+		for j := 0; j < n1; j++ {
+			col, ch, err := fix(c.col, c.colH, column(word, j, n1, n2))
+			if err != nil {
+				return DecodeResult{}, err
+			}
+			for i, v := range col {
+				word[i*n1+j] = v
+			}
+			changed = changed || ch
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// Данные: столбцы дают k2 строк слов кода строк, строки — данные
+	k2 := c.col.Dimension()
+	rows := make([]int, k2*n1)
+	for j := 0; j < n1; j++ {
+		cr, err := c.col.Decode(column(word, j, n1, n2))
+		if err != nil {
+			return DecodeResult{}, err
+		}
+		for i, v := range cr.Data {
+			rows[i*n1+j] = v
+		}
+	}
+	for i := 0; i < k2; i++ {
+		rr, err := c.row.Decode(rows[i*n1 : (i+1)*n1])
+		if err != nil {
+			return DecodeResult{}, err
+		}
+		res.Data = append(res.Data, rr.Data...)
+	}
+	res.Codeword, _ = c.Encode(res.Data)
+	switch {
+	case !BitsEqual(word, res.Codeword):
+		// Итерации не привели к кодовому слову
+		res.Status = StatusDetected
+	case BitsEqual(word, received):
+		res.Status = StatusOK
+	default:
+		res.Status = StatusCorrected
+		for j := range received {
+			if received[j] != word[j] {
+				res.ErrorPositions = append(res.ErrorPositions, j+1)
+			}
+		}
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
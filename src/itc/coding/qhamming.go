@@ -0,0 +1,209 @@
+package coding
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// QaryHamming — код Хэмминга над GF(q) с r проверочными символами: длина
+// n = (q^r - 1)/(q - 1), k = n - r. Столбцы проверочной матрицы — по одному
+// представителю каждого одномерного подпространства GF(q)^r (первый ненулевой
+// элемент равен 1), поэтому любые два столбца линейно независимы и код
+// исправляет одну ошибку в символе. Форма систематическая, как у
+// SystematicHamming: H = [A | I_r], G = [I_k | -A^T]. При q = 2 получается
+// двоичный код Хэмминга.
+type QaryHamming struct {
+	f       *GFq
+	n, k, r int
+	g, h    [][]int
+	// errorPos — позиция (с 0) по нормированному синдрому, записанному числом
+	// по основанию q (первая строка H — младший разряд)
+	errorPos map[int]int
+}
+
+// maxQaryHammingLength — наибольшая длина кода: таблица синдромов и
+// матрицы строятся целиком
+const maxQaryHammingLength = 1 << 14
+
+// NewQaryHamming строит код Хэмминга над GF(q) с r >= 2 проверочными символами
+func NewQaryHamming(q, r int) (*QaryHamming, error) {
+	f, err := NewGFq(q)
+	if err != nil {
+		return nil, err
+	}
+	if r < 2 {
+		return nil, fmt.Errorf("число проверочных символов r = %d, ожидается не меньше 2", r)
+	}
+	n := 1
+	for i := 1; i < r; i++ {
+		n = n*q + 1
+		if n > maxQaryHammingLength {
+			return nil, fmt.Errorf("код Хэмминга над %s с r = %d длиннее %d символов", f, r, maxQaryHammingLength)
+		}
+	}
+	c := &QaryHamming{f: f, n: n, k: n - r, r: r, errorPos: make(map[int]int, n)}
+
+	// Нормированные столбцы длины r, кроме единичных, — в A; единичные — в конце
+	var columns [][]int
+	for lead := r - 1; lead >= 0; lead-- {
+		// Столбцы с первой единицей в строке r-1-lead: хвост из lead символов
+		tails := 1
+		for i := 0; i < lead; i++ {
+			tails *= q
+		}
+		for t := 1; t < tails; t++ {
+			col := make([]int, r)
+			col[r-1-lead] = 1
+			for i, v := r-lead, t; i < r; i, v = i+1, v/q {
+				col[i] = v % q
+			}
+			columns = append(columns, col)
+		}
+	}
+	for i := 0; i < r; i++ {
+		col := make([]int, r)
+		col[i] = 1
+		columns = append(columns, col)
+	}
+
+	c.h = newMatrix(r, n)
+	for j, col := range columns {
+		for i, v := range col {
+			c.h[i][j] = v
+		}
+		c.errorPos[c.syndromeIndex(col)] = j
+	}
+	c.g = newMatrix(c.k, n)
+	for i := 0; i < c.k; i++ {
+		c.g[i][i] = 1
+		for j := 0; j < r; j++ {
+			c.g[i][c.k+j] = f.Neg(c.h[j][i])
+		}
+	}
+	return c, nil
+}
+
+// syndromeIndex записывает вектор над GF(q) числом по основанию q
+func (c *QaryHamming) syndromeIndex(s []int) int {
+	index := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		index = index*c.f.q + s[i]
+	}
+	return index
+}
+
+// Name возвращает название кода
+func (c *QaryHamming) Name() string {
+	return fmt.Sprintf("Хэмминг (%d,%d) над %s", c.n, c.k, c.f)
+}
+
+// Field возвращает поле символов
+func (c *QaryHamming) Field() *GFq { return c.f }
+
+// Length возвращает длину кодового слова n в символах
+func (c *QaryHamming) Length() int { return c.n }
+
+// Dimension возвращает число информационных символов k
+func (c *QaryHamming) Dimension() int { return c.k }
+
+// Redundancy возвращает число проверочных символов r
+func (c *QaryHamming) Redundancy() int { return c.r }
+
+// GeneratorMatrix возвращает копию производящей матрицы G над GF(q)
+func (c *QaryHamming) GeneratorMatrix() [][]int { return copyMatrix(c.g) }
+
+// ParityCheckMatrix возвращает копию проверочной матрицы H над GF(q)
+func (c *QaryHamming) ParityCheckMatrix() [][]int { return copyMatrix(c.h) }
+
+// Encode добавляет к сообщению r проверочных символов: c = m·G
+func (c *QaryHamming) Encode(msg []int) ([]int, error) {
+	if err := c.f.checkSymbols("сообщение", msg, c.k); err != nil {
+		return nil, err
+	}
+	codeword := make([]int, c.n)
+	copy(codeword, msg)
+	for j := 0; j < c.r; j++ {
+		sum := 0
+		for i, m := range msg {
+			sum = c.f.Add(sum, c.f.Mul(m, c.g[i][c.k+j]))
+		}
+		codeword[c.k+j] = sum
+	}
+	return codeword, nil
+}
+
+// Syndrome вычисляет синдром H·y принятого слова
+func (c *QaryHamming) Syndrome(received []int) []int {
+	syndrome := make([]int, c.r)
+	for i, row := range c.h {
+		sum := 0
+		for j, v := range row {
+			sum = c.f.Add(sum, c.f.Mul(v, received[j]))
+		}
+		syndrome[i] = sum
+	}
+	return syndrome
+}
+
+// FindError возвращает позицию (с 0) и значение ошибки по синдрому:
+// синдром однократной ошибки e в позиции j равен e·h_j, а первый ненулевой
+// элемент столбца h_j равен 1, поэтому e — первый ненулевой элемент
+// синдрома, а h_j = s/e
+func (c *QaryHamming) FindError(syndrome []int) (int, int, bool) {
+	value := 0
+	for _, s := range syndrome {
+		if s != 0 {
+			value = s
+			break
+		}
+	}
+	if value == 0 {
+		return 0, 0, false
+	}
+	normalized := make([]int, len(syndrome))
+	for i, s := range syndrome {
+		normalized[i] = c.f.Div(s, value)
+	}
+	pos, ok := c.errorPos[c.syndromeIndex(normalized)]
+	return pos, value, ok
+}
+
+// Decode вычисляет синдром, исправляет ошибку в одном символе и извлекает
+// сообщение. Код совершенный: любой ненулевой синдром соответствует
+// однократной ошибке, поэтому при двух и более ошибках слово исправляется
+// неверно.
+func (c *QaryHamming) Decode(received []int) (DecodeResult, error) {
+	if err := c.f.checkSymbols("принятое слово", received, c.n); err != nil {
+		return DecodeResult{}, err
+	}
+	res := DecodeResult{
+		Codeword: append([]int(nil), received...),
+		Syndrome: c.Syndrome(received),
+	}
+	if pos, value, found := c.FindError(res.Syndrome); found {
+		res.Codeword[pos] = c.f.Sub(res.Codeword[pos], value)
+		res.ErrorPos = pos + 1
+		res.ErrorPositions = []int{pos + 1}
+		res.Status = StatusCorrected
+	}
+	res.Data = append([]int(nil), res.Codeword[:c.k]...)
+	return res, nil
+}
+
+// InjectSymbolErrors прибавляет к count различным случайным символам слова
+// случайные ненулевые элементы поля и возвращает искажённую копию и номера
+// искажённых символов (с 1)
+func (c *QaryHamming) InjectSymbolErrors(word []int, count int, rng *rand.Rand) ([]int, []int, error) {
+	if count < 0 || count > len(word) {
+		return nil, nil, fmt.Errorf("число ошибок %d вне диапазона 0..%d", count, len(word))
+	}
+	noisy := append([]int(nil), word...)
+	positions := rng.Perm(len(word))[:count]
+	for i := range positions {
+		noisy[positions[i]] = c.f.Add(noisy[positions[i]], 1+rng.Intn(c.f.q-1))
+		positions[i]++
+	}
+	sort.Ints(positions)
+	return noisy, positions, nil
+}
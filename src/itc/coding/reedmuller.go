@@ -0,0 +1,278 @@
+package coding
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// maxRMVariables — наибольшее число переменных m: длина слова 2^m
+const maxRMVariables = 16
+
+// ReedMuller — код Рида — Маллера RM(r,m) длины n = 2^m. Кодовые слова —
+// таблицы значений булевых функций от m переменных степени не выше r:
+// позиция x (с 0) соответствует набору переменных x_1..x_m, где x_i —
+// бит i-1 числа x. Информационные биты — коэффициенты функции при
+// мономах, упорядоченных по степени, а внутри степени — по возрастанию
+// маски переменных: 1, x_1, ..., x_m, x_1x_2, x_1x_3, x_2x_3, ...
+//
+// Код первого порядка декодируется быстрым преобразованием Адамара
+// (декодирование по максимуму правдоподобия), коды старших порядков —
+// мажоритарным алгоритмом Рида. Кодовое расстояние d = 2^(m-r), декодер
+// исправляет до 2^(m-r-1) - 1 ошибок.
+type ReedMuller struct {
+	r, m, n   int
+	monomials []int // маски переменных мономов в порядке информационных бит
+	checks    []int // мономы степени не выше m-r-1: строки проверочной матрицы
+}
+
+// NewReedMuller строит код RM(r,m), 0 <= r <= m
+func NewReedMuller(r, m int) (*ReedMuller, error) {
+	if m < 1 || m > maxRMVariables {
+		return nil, fmt.Errorf("число переменных m = %d вне диапазона 1..%d", m, maxRMVariables)
+	}
+	if r < 0 || r > m {
+		return nil, fmt.Errorf("порядок r = %d вне диапазона 0..%d", r, m)
+	}
+	n := 1 << m
+	return &ReedMuller{r: r, m: m, n: n, monomials: rmMonomials(m, r), checks: rmMonomials(m, m-r-1)}, nil
+}
+
+// rmMonomials возвращает маски мономов от m переменных степени не выше r
+func rmMonomials(m, r int) []int {
+	var res []int
+	for mask := 0; mask < 1<<m; mask++ {
+		if bits.OnesCount(uint(mask)) <= r {
+			res = append(res, mask)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return bits.OnesCount(uint(res[i])) < bits.OnesCount(uint(res[j]))
+	})
+	return res
+}
+
+// Name возвращает название кода
+func (c *ReedMuller) Name() string {
+	return fmt.Sprintf("Рид — Маллер RM(%d,%d), (%d,%d)", c.r, c.m, c.n, len(c.monomials))
+}
+
+// Length возвращает длину кодового слова n = 2^m
+func (c *ReedMuller) Length() int { return c.n }
+
+// Dimension возвращает число информационных бит: число мономов степени не выше r
+func (c *ReedMuller) Dimension() int { return len(c.monomials) }
+
+// Order возвращает порядок кода r
+func (c *ReedMuller) Order() int { return c.r }
+
+// Variables возвращает число переменных m
+func (c *ReedMuller) Variables() int { return c.m }
+
+// MinDistance возвращает кодовое расстояние d = 2^(m-r)
+func (c *ReedMuller) MinDistance() int { return 1 << (c.m - c.r) }
+
+// CorrectableErrors возвращает число гарантированно исправляемых ошибок (d-1)/2
+func (c *ReedMuller) CorrectableErrors() int { return (c.MinDistance() - 1) / 2 }
+
+// monomialMatrix строит матрицу значений мономов: элемент (i, x) равен 1,
+// если все переменные монома i в наборе x равны 1
+func (c *ReedMuller) monomialMatrix(monomials []int) [][]int {
+	M := newMatrix(len(monomials), c.n)
+	for i, mask := range monomials {
+		for x := 0; x < c.n; x++ {
+			if x&mask == mask {
+				M[i][x] = 1
+			}
+		}
+	}
+	return M
+}
+
+// GeneratorMatrix возвращает производящую матрицу: значения мономов степени не выше r
+func (c *ReedMuller) GeneratorMatrix() [][]int { return c.monomialMatrix(c.monomials) }
+
+// ParityCheckMatrix возвращает проверочную матрицу — производящую матрицу
+// двойственного кода RM(m-r-1,m)
+func (c *ReedMuller) ParityCheckMatrix() [][]int { return c.monomialMatrix(c.checks) }
+
+// mobius выполняет преобразование Мёбиуса над GF(2) на месте: f(x) заменяется
+// суммой f(y) по всем y, биты которых входят в x. Преобразование обратно само
+// себе и переводит коэффициенты полинома Жегалкина в таблицу значений и обратно.
+func mobius(f []int) {
+	for step := 1; step < len(f); step <<= 1 {
+		for x := range f {
+			if x&step != 0 {
+				f[x] ^= f[x^step]
+			}
+		}
+	}
+}
+
+// Encode вычисляет таблицу значений функции с коэффициентами data
+func (c *ReedMuller) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, len(c.monomials)); err != nil {
+		return nil, err
+	}
+	word := make([]int, c.n)
+	for i, mask := range c.monomials {
+		word[mask] = data[i]
+	}
+	mobius(word)
+	return word, nil
+}
+
+// Syndrome вычисляет синдром H * w. Строка монома S проверочной матрицы
+// суммирует w(x) по наборам x, в которых все переменные S равны 1, поэтому
+// все суммы считаются одним преобразованием по надмножествам за O(n log n).
+func (c *ReedMuller) Syndrome(received []int) []int {
+	f := make([]int, c.n)
+	for x := range f {
+		f[(c.n-1)^x] = received[x]
+	}
+	mobius(f)
+	syndrome := make([]int, len(c.checks))
+	for i, mask := range c.checks {
+		syndrome[i] = f[(c.n-1)^mask]
+	}
+	return syndrome
+}
+
+// Decode восстанавливает коэффициенты функции: для r = 1 — быстрым
+// преобразованием Адамара, для остальных порядков — мажоритарным алгоритмом
+// Рида. Если решение неоднозначно (равенство голосов или нескольких
+// максимумов спектра), слово помечается как ошибка, которую нельзя исправить,
+// а в Data возвращается один из равновероятных вариантов.
+func (c *ReedMuller) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.n); err != nil {
+		return DecodeResult{}, err
+	}
+	var data []int
+	var tie bool
+	if c.r == 1 {
+		data, tie = c.decodeHadamard(received)
+	} else {
+		data, tie = c.decodeMajority(received)
+	}
+	codeword, err := c.Encode(data)
+	if err != nil {
+		return DecodeResult{}, err
+	}
+	res := DecodeResult{Data: data, Codeword: codeword, Syndrome: c.Syndrome(received)}
+	for x := range received {
+		if received[x] != codeword[x] {
+			res.ErrorPositions = append(res.ErrorPositions, x+1)
+		}
+	}
+	switch {
+	case tie:
+		res.Status = StatusDetected
+	case len(res.ErrorPositions) == 0:
+		res.Status = StatusOK
+	default:
+		res.Status = StatusCorrected
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
+
+// decodeHadamard декодирует код первого порядка: спектр Уолша — Адамара
+// W(u) = сумма по x (-1)^(w(x) + u·x) равен n - 2 d(w, l_u), где l_u —
+// линейная функция u·x, поэтому ближайшее кодовое слово даёт максимум |W(u)|.
+// Знак W(u) определяет свободный член.
+func (c *ReedMuller) decodeHadamard(received []int) ([]int, bool) {
+	spectrum := make([]int, c.n)
+	for x, bit := range received {
+		spectrum[x] = 1 - 2*bit
+	}
+	for step := 1; step < c.n; step <<= 1 {
+		for x := range spectrum {
+			if x&step == 0 {
+				a, b := spectrum[x], spectrum[x|step]
+				spectrum[x], spectrum[x|step] = a+b, a-b
+			}
+		}
+	}
+	best, tie := 0, false
+	for u := 1; u < c.n; u++ {
+		switch a, b := abs(spectrum[u]), abs(spectrum[best]); {
+		case a > b:
+			best, tie = u, false
+		case a == b:
+			tie = true
+		}
+	}
+	if spectrum[best] == 0 {
+		tie = true
+	}
+	data := make([]int, len(c.monomials))
+	if spectrum[best] < 0 {
+		data[0] = 1
+	}
+	for i := 0; i < c.m; i++ {
+		data[1+i] = best >> i & 1
+	}
+	return data, tie
+}
+
+// decodeMajority — мажоритарный алгоритм Рида. Коэффициенты находятся от
+// старшей степени к младшей: сумма значений по подкубу, в котором меняются
+// только переменные монома S степени d, равна коэффициенту при S, если из
+// слова уже вычтены мономы степени больше d. Таких подкубов 2^(m-d), и
+// коэффициент выбирается большинством их голосов.
+func (c *ReedMuller) decodeMajority(received []int) ([]int, bool) {
+	y := append([]int(nil), received...)
+	data := make([]int, len(c.monomials))
+	tie := false
+	full := c.n - 1
+	end := len(c.monomials)
+	for degree := c.r; degree >= 0; degree-- {
+		start := end
+		for start > 0 && bits.OnesCount(uint(c.monomials[start-1])) == degree {
+			start--
+		}
+		found := make([]int, c.n)
+		for i := start; i < end; i++ {
+			mask := c.monomials[i]
+			rest := full &^ mask
+			ones, votes := 0, 0
+			// Перебор подмножеств rest: фиксированные значения остальных переменных
+			for b := rest; ; b = (b - 1) & rest {
+				sum := 0
+				for s := mask; ; s = (s - 1) & mask {
+					sum ^= y[b|s]
+					if s == 0 {
+						break
+					}
+				}
+				ones += sum
+				votes++
+				if b == 0 {
+					break
+				}
+			}
+			if 2*ones == votes {
+				tie = true
+			}
+			if 2*ones > votes {
+				data[i] = 1
+				found[mask] = 1
+			}
+		}
+		// Вычитание найденных мономов степени degree из слова
+		mobius(found)
+		for x := range y {
+			y[x] ^= found[x]
+		}
+		end = start
+	}
+	return data, tie
+}
+
+// abs возвращает модуль целого числа
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,257 @@
+package coding
+
+import "fmt"
+
+// ReedSolomon — код Рида — Соломона RS(n, k) над GF(2^m) в узком смысле:
+// корни порождающего многочлена g(x) = (x - α)(x - α²)...(x - α^(n-k)).
+// Кодирование систематическое: k информационных символов, затем n-k
+// проверочных (остаток от деления D(x)·x^(n-k) на g(x)). При n < 2^m - 1 код
+// укороченный. Как двоичный код каждый символ занимает m бит, старший первым.
+type ReedSolomon struct {
+	gf   *GF
+	n, k int
+	gen  []int // g(x), коэффициенты от младшей степени
+}
+
+// NewReedSolomon строит код RS(n, k) над GF(2^m), 1 <= k < n <= 2^m - 1
+func NewReedSolomon(m, n, k int) (*ReedSolomon, error) {
+	gf, err := NewGF(m)
+	if err != nil {
+		return nil, err
+	}
+	if k < 1 || k >= n || n > gf.Order() {
+		return nil, fmt.Errorf("RS(%d,%d) над %s: ожидается 1 <= k < n <= %d", n, k, gf, gf.Order())
+	}
+	c := &ReedSolomon{gf: gf, n: n, k: k, gen: []int{1}}
+	for j := 1; j <= n-k; j++ {
+		// Умножение на (x + α^j)
+		next := make([]int, len(c.gen)+1)
+		for i, g := range c.gen {
+			next[i+1] ^= g
+			next[i] ^= gf.Mul(g, gf.Exp(j))
+		}
+		c.gen = next
+	}
+	return c, nil
+}
+
+// Name возвращает название кода
+func (c *ReedSolomon) Name() string {
+	return fmt.Sprintf("Рид — Соломон (%d,%d) над %s", c.n, c.k, c.gf)
+}
+
+// Field возвращает поле символов
+func (c *ReedSolomon) Field() *GF { return c.gf }
+
+// SymbolLength возвращает длину кода n в символах
+func (c *ReedSolomon) SymbolLength() int { return c.n }
+
+// SymbolDimension возвращает число информационных символов k
+func (c *ReedSolomon) SymbolDimension() int { return c.k }
+
+// Length возвращает длину двоичного образа кода n·m
+func (c *ReedSolomon) Length() int { return c.n * c.gf.m }
+
+// Dimension возвращает число информационных бит k·m
+func (c *ReedSolomon) Dimension() int { return c.k * c.gf.m }
+
+// MinDistance возвращает минимальное расстояние в символах n - k + 1
+func (c *ReedSolomon) MinDistance() int { return c.n - c.k + 1 }
+
+// CorrectableErrors возвращает число исправляемых символьных ошибок
+// t = (n-k)/2; столько же гарантированно исправляется и ошибок в битах
+func (c *ReedSolomon) CorrectableErrors() int { return (c.n - c.k) / 2 }
+
+// toSymbols собирает символы из бит (m бит на символ, старший первым)
+func (c *ReedSolomon) toSymbols(bits []int) []int {
+	m := c.gf.m
+	symbols := make([]int, len(bits)/m)
+	for i := range symbols {
+		for _, b := range bits[i*m : (i+1)*m] {
+			symbols[i] = symbols[i]<<1 | b
+		}
+	}
+	return symbols
+}
+
+// toBits раскладывает символы на биты
+func (c *ReedSolomon) toBits(symbols []int) []int {
+	m := c.gf.m
+	bits := make([]int, 0, len(symbols)*m)
+	for _, s := range symbols {
+		for b := m - 1; b >= 0; b-- {
+			bits = append(bits, s>>b&1)
+		}
+	}
+	return bits
+}
+
+// EncodeSymbols кодирует k информационных символов
+func (c *ReedSolomon) EncodeSymbols(data []int) ([]int, error) {
+	if len(data) != c.k {
+		return nil, fmt.Errorf("ожидалось %d информационных символов, получено %d", c.k, len(data))
+	}
+	r := c.n - c.k
+	rem := make([]int, r) // остаток, старший коэффициент первым
+	for i, d := range data {
+		if d < 0 || d > c.gf.Order() {
+			return nil, fmt.Errorf("символ %d равен %d и не принадлежит %s", i+1, d, c.gf)
+		}
+		fb := d ^ rem[0]
+		copy(rem, rem[1:])
+		rem[r-1] = 0
+		if fb != 0 {
+			for j := 0; j < r; j++ {
+				rem[r-1-j] ^= c.gf.Mul(fb, c.gen[j])
+			}
+		}
+	}
+	return append(append([]int(nil), data...), rem...), nil
+}
+
+// syndromes вычисляет S_j = r(α^j), j = 1..n-k; первый символ слова —
+// коэффициент при x^(n-1)
+func (c *ReedSolomon) syndromes(word []int) []int {
+	s := make([]int, c.n-c.k)
+	for j := range s {
+		x := c.gf.Exp(j + 1)
+		acc := 0
+		for _, v := range word {
+			acc = c.gf.Mul(acc, x) ^ v
+		}
+		s[j] = acc
+	}
+	return s
+}
+
+// DecodeSymbols исправляет до t символьных ошибок алгоритмами Берлекэмпа —
+// Мэсси (многочлен локаторов), Ченя (поиск корней) и Форни (значения
+// ошибок). Возвращает исправленное слово, номера исправленных символов (с 0)
+// и итог; при StatusDetected слово возвращается без изменений.
+func (c *ReedSolomon) DecodeSymbols(received []int) ([]int, []int, DecodeStatus) {
+	gf := c.gf
+	word := append([]int(nil), received...)
+	s := c.syndromes(word)
+	if isZero(s) {
+		return word, nil, StatusOK
+	}
+	// Берлекэмп — Мэсси: Λ(x) — многочлен локаторов ошибок
+	lambda, prev := []int{1}, []int{1}
+	L, shift, prevDisc := 0, 1, 1
+	for step := range s {
+		d := s[step]
+		for i := 1; i <= L && i < len(lambda); i++ {
+			d ^= gf.Mul(lambda[i], s[step-i])
+		}
+		if d == 0 {
+			shift++
+			continue
+		}
+		coef := gf.Div(d, prevDisc)
+		next := append([]int(nil), lambda...)
+		for len(next) < len(prev)+shift {
+			next = append(next, 0)
+		}
+		for i, p := range prev {
+			next[i+shift] ^= gf.Mul(coef, p)
+		}
+		if 2*L <= step {
+			prev, L, prevDisc, shift = lambda, step+1-L, d, 1
+		} else {
+			shift++
+		}
+		lambda = next
+	}
+	if L > c.CorrectableErrors() {
+		return append([]int(nil), received...), nil, StatusDetected
+	}
+	// Чень: символ i соответствует степени e = n-1-i и локатору X = α^e
+	var positions []int
+	for i := 0; i < c.n; i++ {
+		if gf.polyEval(lambda, gf.Exp(-(c.n-1-i))) == 0 {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) != L {
+		return append([]int(nil), received...), nil, StatusDetected
+	}
+	// Форни: Ω(x) = S(x)Λ(x) mod x^(n-k), значение ошибки Ω(X⁻¹)/Λ'(X⁻¹)
+	omega := make([]int, len(s))
+	for i, sv := range s {
+		for j, lv := range lambda {
+			if i+j < len(omega) {
+				omega[i+j] ^= gf.Mul(sv, lv)
+			}
+		}
+	}
+	deriv := make([]int, len(lambda))
+	for i := 1; i < len(lambda); i += 2 {
+		deriv[i-1] = lambda[i]
+	}
+	for _, i := range positions {
+		xInv := gf.Exp(-(c.n - 1 - i))
+		den := gf.polyEval(deriv, xInv)
+		if den == 0 {
+			return append([]int(nil), received...), nil, StatusDetected
+		}
+		word[i] ^= gf.Div(gf.polyEval(omega, xInv), den)
+	}
+	if !isZero(c.syndromes(word)) {
+		return append([]int(nil), received...), nil, StatusDetected
+	}
+	return word, positions, StatusCorrected
+}
+
+// Encode кодирует k·m информационных бит
+func (c *ReedSolomon) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, c.Dimension()); err != nil {
+		return nil, err
+	}
+	word, err := c.EncodeSymbols(c.toSymbols(data))
+	if err != nil {
+		return nil, err
+	}
+	return c.toBits(word), nil
+}
+
+// Syndrome возвращает синдромы S_1..S_(n-k) слова, разложенные на биты
+func (c *ReedSolomon) Syndrome(word []int) []int {
+	return c.toBits(c.syndromes(c.toSymbols(word)))
+}
+
+// GeneratorMatrix возвращает двоичную производящую матрицу (k·m x n·m)
+func (c *ReedSolomon) GeneratorMatrix() [][]int { return unitEncodings(c) }
+
+// ParityCheckMatrix возвращает двоичную проверочную матрицу: столбец j —
+// синдром слова с единственной единицей в бите j
+func (c *ReedSolomon) ParityCheckMatrix() [][]int {
+	n := c.Length()
+	H := newMatrix(n-c.Dimension(), n)
+	for j := 0; j < n; j++ {
+		e := make([]int, n)
+		e[j] = 1
+		for i, v := range c.Syndrome(e) {
+			H[i][j] = v
+		}
+	}
+	return H
+}
+
+// Decode исправляет до t символьных ошибок в двоичном образе слова
+func (c *ReedSolomon) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.Length()); err != nil {
+		return DecodeResult{}, err
+	}
+	word, _, status := c.DecodeSymbols(c.toSymbols(received))
+	res := DecodeResult{Syndrome: c.Syndrome(received), Status: status, Codeword: c.toBits(word)}
+	res.Data = res.Codeword[:c.Dimension()]
+	if status == StatusCorrected {
+		for j := range received {
+			if received[j] != res.Codeword[j] {
+				res.ErrorPositions = append(res.ErrorPositions, j+1)
+			}
+		}
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
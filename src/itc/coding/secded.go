@@ -0,0 +1,260 @@
+package coding
+
+import "fmt"
+
+// PositionalHamming — код Хэмминга с проверочными битами на позициях-степенях
+// двойки (лабораторная работа 5). Позиции нумеруются с 1, синдром равен
+// номеру ошибочной позиции. Длина кода n = k + p, поэтому при k < 2^p - p - 1
+// получается укороченный код.
+type PositionalHamming struct {
+	n, k, p   int
+	parityPos []int // позиции проверочных бит (1, 2, 4, 8, ...)
+	dataPos   []int // позиции информационных бит
+}
+
+// MinP вычисляет минимальное p такое, что (2^p - p - 1) >= k
+func MinP(k int) int {
+	p := 2
+	for (1<<p)-p-1 < k {
+		p++
+	}
+	return p
+}
+
+// NewPositionalHamming строит позиционный код Хэмминга для k информационных бит
+func NewPositionalHamming(k int) (*PositionalHamming, error) {
+	if k <= 0 || k > 1<<20 {
+		return nil, fmt.Errorf("число информационных бит k = %d вне диапазона 1..%d", k, 1<<20)
+	}
+	p := MinP(k)
+	c := &PositionalHamming{n: k + p, k: k, p: p}
+	for pos := 1; pos <= c.n; pos++ {
+		if pos&(pos-1) == 0 {
+			c.parityPos = append(c.parityPos, pos)
+		} else {
+			c.dataPos = append(c.dataPos, pos)
+		}
+	}
+	return c, nil
+}
+
+// Name возвращает название кода
+func (c *PositionalHamming) Name() string {
+	return fmt.Sprintf("Хэмминг (%d,%d)", c.n, c.k)
+}
+
+// Length возвращает длину кодового слова n
+func (c *PositionalHamming) Length() int { return c.n }
+
+// Dimension возвращает число информационных бит k
+func (c *PositionalHamming) Dimension() int { return c.k }
+
+// Redundancy возвращает число проверочных бит p
+func (c *PositionalHamming) Redundancy() int { return c.p }
+
+// DataPositions возвращает позиции информационных бит (с 1)
+func (c *PositionalHamming) DataPositions() []int { return append([]int(nil), c.dataPos...) }
+
+// ParityPositions возвращает позиции проверочных бит (с 1)
+func (c *PositionalHamming) ParityPositions() []int { return append([]int(nil), c.parityPos...) }
+
+// ParityCheckMatrix строит проверочную матрицу H размера p x n:
+// H[i][j] = 1, если (j+1)-й бит участвует в i-м проверочном уравнении
+func (c *PositionalHamming) ParityCheckMatrix() [][]int {
+	H := newMatrix(c.p, c.n)
+	for col := 1; col <= c.n; col++ {
+		for row := 0; row < c.p; row++ {
+			H[row][col-1] = (col >> row) & 1
+		}
+	}
+	return H
+}
+
+// GeneratorMatrix строит производящую матрицу: i-я строка — кодовое слово
+// для i-го единичного сообщения
+func (c *PositionalHamming) GeneratorMatrix() [][]int {
+	G := make([][]int, c.k)
+	unit := make([]int, c.k)
+	for i := range G {
+		unit[i] = 1
+		G[i], _ = c.Encode(unit)
+		unit[i] = 0
+	}
+	return G
+}
+
+// Encode заполняет информационные позиции, затем вычисляет проверочные биты
+func (c *PositionalHamming) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, c.k); err != nil {
+		return nil, err
+	}
+	word := make([]int, c.n)
+
+	// Заполнение информационных бит
+	for i := 0; i < c.k; i++ {
+		word[c.dataPos[i]-1] = data[i]
+	}
+
+	// Вычисление проверочных бит
+	for j, parityPosition := range c.parityPos {
+		parity := 0
+		for pos := 1; pos <= c.n; pos++ {
+			if ((pos >> j) & 1) == 1 {
+				parity ^= word[pos-1]
+			}
+		}
+		word[parityPosition-1] = parity & 1
+	}
+	return word, nil
+}
+
+// Syndrome вычисляет синдром S = H * word (младший бит первый)
+func (c *PositionalHamming) Syndrome(word []int) []int {
+	s := make([]int, c.p)
+	for pos := 1; pos <= c.n; pos++ {
+		if word[pos-1] == 1 {
+			for row := 0; row < c.p; row++ {
+				s[row] ^= (pos >> row) & 1
+			}
+		}
+	}
+	return s
+}
+
+// ExtractData извлекает k информационных бит из кодового слова длины n
+func (c *PositionalHamming) ExtractData(word []int) []int {
+	data := make([]int, c.k)
+	for i := 0; i < c.k; i++ {
+		data[i] = word[c.dataPos[i]-1]
+	}
+	return data
+}
+
+// Decode исправляет однократную ошибку по синдрому. Если синдром указывает
+// на позицию за пределами укороченного кода, ошибка только обнаруживается.
+func (c *PositionalHamming) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.n); err != nil {
+		return DecodeResult{}, err
+	}
+	res := DecodeResult{
+		Codeword: append([]int(nil), received...),
+		Syndrome: c.Syndrome(received),
+	}
+	synIndex := SyndromeIndex(res.Syndrome)
+	switch {
+	case synIndex == 0:
+		res.Status = StatusOK
+	case synIndex <= c.n:
+		res.Codeword[synIndex-1] ^= 1
+		res.ErrorPos = synIndex
+		res.Status = StatusCorrected
+	default:
+		res.Status = StatusDetected
+	}
+	res.Data = c.ExtractData(res.Codeword)
+	return res, nil
+}
+
+// SECDED — расширенный код Хэмминга: к позиционному коду добавляется общий
+// паритетный бит, что позволяет исправлять одиночные ошибки и обнаруживать
+// двойные (d_min = 4)
+type SECDED struct {
+	*PositionalHamming
+}
+
+// NewSECDED строит расширенный код Хэмминга для k информационных бит
+func NewSECDED(k int) (*SECDED, error) {
+	h, err := NewPositionalHamming(k)
+	if err != nil {
+		return nil, err
+	}
+	return &SECDED{PositionalHamming: h}, nil
+}
+
+// Name возвращает название кода
+func (c *SECDED) Name() string {
+	return fmt.Sprintf("SECDED (%d,%d)", c.n+1, c.k)
+}
+
+// Length возвращает длину расширенного кодового слова n + 1
+func (c *SECDED) Length() int { return c.n + 1 }
+
+// ParityCheckMatrix возвращает проверочную матрицу расширенного кода:
+// к H добавляются нулевой столбец общего паритета и строка из единиц
+func (c *SECDED) ParityCheckMatrix() [][]int {
+	H := c.PositionalHamming.ParityCheckMatrix()
+	for i := range H {
+		H[i] = append(H[i], 0)
+	}
+	all := make([]int, c.n+1)
+	for i := range all {
+		all[i] = 1
+	}
+	return append(H, all)
+}
+
+// GeneratorMatrix возвращает производящую матрицу расширенного кода
+func (c *SECDED) GeneratorMatrix() [][]int {
+	G := make([][]int, c.k)
+	unit := make([]int, c.k)
+	for i := range G {
+		unit[i] = 1
+		G[i], _ = c.Encode(unit)
+		unit[i] = 0
+	}
+	return G
+}
+
+// AddOverallParity добавляет общий паритетный бит к коду Хэмминга (SEC → SECDED)
+func AddOverallParity(hammingWord []int) []int {
+	overall := 0
+	for _, v := range hammingWord {
+		overall ^= v
+	}
+	return append(append([]int(nil), hammingWord...), overall)
+}
+
+// Encode кодирует данные кодом Хэмминга и добавляет общий паритет
+func (c *SECDED) Encode(data []int) ([]int, error) {
+	word, err := c.PositionalHamming.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+	return AddOverallParity(word), nil
+}
+
+// Decode анализирует синдром и общую чётность принятого слова:
+// синдром 0 и чётность 0 — ошибок нет; синдром ≠ 0 и чётность 1 — одиночная
+// ошибка; синдром 0 и чётность 1 — ошибка в общем паритетном бите;
+// синдром ≠ 0 и чётность 0 — двукратная ошибка (только обнаруживается).
+func (c *SECDED) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.n+1); err != nil {
+		return DecodeResult{}, err
+	}
+	res := DecodeResult{
+		Codeword: append([]int(nil), received...),
+		Syndrome: c.Syndrome(received[:c.n]),
+	}
+	synIndex := SyndromeIndex(res.Syndrome)
+	overallParity := 0
+	for _, v := range received {
+		overallParity ^= v
+	}
+
+	switch {
+	case synIndex == 0 && overallParity == 0:
+		res.Status = StatusOK
+	case synIndex != 0 && overallParity == 1 && synIndex <= c.n:
+		res.Codeword[synIndex-1] ^= 1
+		res.ErrorPos = synIndex
+		res.Status = StatusCorrected
+	case synIndex == 0 && overallParity == 1:
+		res.Codeword[c.n] ^= 1
+		res.ErrorPos = c.n + 1
+		res.Status = StatusCorrected
+	default:
+		res.Status = StatusDetected
+	}
+	res.Data = c.ExtractData(res.Codeword[:c.n])
+	return res, nil
+}
@@ -0,0 +1,154 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// ErrorRates — итоги передачи слов кода через канал с помехами
+type ErrorRates struct {
+	Words       int // передано слов
+	DataBits    int // передано информационных бит
+	BitErrors   int // информационных бит, декодированных с ошибкой
+	FrameErrors int // слов, в информационных битах которых осталась ошибка
+	Iterations  int // суммарное число итераций итеративного декодера
+}
+
+// BER возвращает долю ошибочных информационных бит
+func (r ErrorRates) BER() float64 {
+	return float64(r.BitErrors) / float64(r.DataBits)
+}
+
+// FER возвращает долю слов, декодированных с ошибкой
+func (r ErrorRates) FER() float64 {
+	return float64(r.FrameErrors) / float64(r.Words)
+}
+
+// AvgIterations возвращает среднее число итераций декодера на слово
+func (r ErrorRates) AvgIterations() float64 {
+	return float64(r.Iterations) / float64(r.Words)
+}
+
+// add учитывает одно декодированное слово
+func (r *ErrorRates) add(data []int, res DecodeResult) {
+	wrong := 0
+	for j := range data {
+		if data[j] != res.Data[j] {
+			wrong++
+		}
+	}
+	r.BitErrors += wrong
+	if wrong > 0 {
+		r.FrameErrors++
+	}
+	r.Iterations += res.Iterations
+}
+
+// SimulateBSC передаёт words случайных слов кода через двоичный симметричный
+// канал с вероятностью ошибки p и подсчитывает ошибки после декодирования.
+// Слова, в которых ошибка обнаружена, но не исправлена, учитываются с теми
+// информационными битами, которые вернул декодер.
+func SimulateBSC(code BlockCode, p float64, words int, rng *rand.Rand) (ErrorRates, error) {
+	if p < 0 || p > 1 {
+		return ErrorRates{}, fmt.Errorf("вероятность ошибки в канале %g вне отрезка [0, 1]", p)
+	}
+	if words <= 0 {
+		return ErrorRates{}, fmt.Errorf("число слов должно быть больше 0, получено %d", words)
+	}
+	rates := ErrorRates{Words: words, DataBits: words * code.Dimension()}
+	for i := 0; i < words; i++ {
+		data := RandomBits(code.Dimension(), rng)
+		word, err := code.Encode(data)
+		if err != nil {
+			return ErrorRates{}, err
+		}
+		received, _ := TransmitBSC(word, p, rng)
+		res, err := code.Decode(received)
+		if err != nil {
+			return ErrorRates{}, err
+		}
+		rates.add(data, res)
+	}
+	return rates, nil
+}
+
+// SoftDecoder — декодер, принимающий мягкие решения канала: логарифмы
+// отношения правдоподобия LLR = ln P(0)/P(1) для каждого бита слова
+type SoftDecoder interface {
+	DecodeLLR(llr []float64) (DecodeResult, error)
+}
+
+// BSCLLR переводит биты, принятые из двоичного симметричного канала с
+// вероятностью ошибки p, в LLR: ±ln((1-p)/p)
+func BSCLLR(received []int, p float64) []float64 {
+	l := math.Log((1 - p) / p)
+	llr := make([]float64, len(received))
+	for i, bit := range received {
+		llr[i] = l * float64(1-2*bit)
+	}
+	return llr
+}
+
+// AWGNSigma возвращает среднеквадратичное отклонение шума канала с
+// аддитивным белым гауссовским шумом при двоичной фазовой модуляции
+// (символы ±1) для отношения Eb/N0 в децибелах и скорости кода rate:
+// σ² = 1 / (2 R Eb/N0)
+func AWGNSigma(ebn0dB, rate float64) float64 {
+	return math.Sqrt(1 / (2 * rate * math.Pow(10, ebn0dB/10)))
+}
+
+// TransmitAWGN передаёт слово через гауссовский канал: бит 0 передаётся
+// символом +1, бит 1 — символом -1, к каждому добавляется шум N(0, σ²).
+// Возвращает LLR принятых символов 2y/σ².
+func TransmitAWGN(word []int, sigma float64, rng *rand.Rand) []float64 {
+	llr := make([]float64, len(word))
+	for i, bit := range word {
+		y := float64(1-2*bit) + sigma*rng.NormFloat64()
+		llr[i] = 2 * y / (sigma * sigma)
+	}
+	return llr
+}
+
+// UncodedAWGNBER возвращает долю ошибочных бит без кодирования при двоичной
+// фазовой модуляции: Q(sqrt(2 Eb/N0)) = erfc(sqrt(Eb/N0)) / 2
+func UncodedAWGNBER(ebn0dB float64) float64 {
+	return math.Erfc(math.Sqrt(math.Pow(10, ebn0dB/10))) / 2
+}
+
+// SimulateAWGN передаёт words случайных слов кода через гауссовский канал с
+// отношением Eb/N0 (дБ), пересчитанным на скорость кода. Коды с мягким
+// декодером (SoftDecoder) получают LLR, остальные — жёсткие решения по знаку.
+func SimulateAWGN(code BlockCode, ebn0dB float64, words int, rng *rand.Rand) (ErrorRates, error) {
+	if words <= 0 {
+		return ErrorRates{}, fmt.Errorf("число слов должно быть больше 0, получено %d", words)
+	}
+	sigma := AWGNSigma(ebn0dB, float64(code.Dimension())/float64(code.Length()))
+	soft, isSoft := code.(SoftDecoder)
+	rates := ErrorRates{Words: words, DataBits: words * code.Dimension()}
+	for i := 0; i < words; i++ {
+		data := RandomBits(code.Dimension(), rng)
+		word, err := code.Encode(data)
+		if err != nil {
+			return ErrorRates{}, err
+		}
+		llr := TransmitAWGN(word, sigma, rng)
+		var res DecodeResult
+		if isSoft {
+			res, err = soft.DecodeLLR(llr)
+		} else {
+			hard := make([]int, len(llr))
+			for j, l := range llr {
+				if l < 0 {
+					hard[j] = 1
+				}
+			}
+			res, err = code.Decode(hard)
+		}
+		if err != nil {
+			return ErrorRates{}, err
+		}
+		rates.add(data, res)
+	}
+	return rates, nil
+}
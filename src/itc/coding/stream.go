@@ -0,0 +1,321 @@
+package coding
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Формат файла, закодированного SECDED:
+//
+//	заголовок: "ITCS", версия (1 байт), k (1 байт), контрольный байт версия^k^0xA5
+//	блоки данных: поток бит исходного файла (старший бит байта первым),
+//	    разбитый на блоки по k бит; последний блок дополнен нулями
+//	блоки длины: 64-битная длина исходного файла в байтах, закодированная
+//	    так же, в ceil(64/k) блоках
+//	дополнение нулями до целого байта
+//
+// Длина записывается в конце, поэтому кодировать можно поток неизвестной
+// длины, например стандартный ввод.
+const (
+	streamMagic      = "ITCS"
+	streamVersion    = 1
+	streamHeaderSize = len(streamMagic) + 3
+	lengthBits       = 64
+)
+
+// BlockEvent — блок, в котором при декодировании найдена ошибка
+type BlockEvent struct {
+	Block      int          // номер блока с 0
+	Offset     int64        // байт закодированного файла, в котором начинается блок
+	DataOffset int64        // смещение первого байта данных блока в исходном файле; -1 для блоков длины
+	Status     DecodeStatus // ошибка исправлена или только обнаружена
+	ErrorPos   int          // позиция исправленной ошибки в слове (с 1)
+}
+
+// StreamReport — итоги декодирования потока
+type StreamReport struct {
+	K         int
+	Blocks    int   // всего блоков, включая блоки длины
+	Bytes     int64 // длина восстановленного файла
+	Corrected int
+	Detected  int
+	Events    []BlockEvent
+}
+
+// bitWriter записывает биты в поток байтов, старший бит первым
+type bitWriter struct {
+	w     *bufio.Writer
+	cur   byte
+	nbits int
+}
+
+func (b *bitWriter) writeBits(bits []int) error {
+	for _, bit := range bits {
+		b.cur = b.cur<<1 | byte(bit)
+		b.nbits++
+		if b.nbits == 8 {
+			if err := b.w.WriteByte(b.cur); err != nil {
+				return err
+			}
+			b.cur, b.nbits = 0, 0
+		}
+	}
+	return nil
+}
+
+// flush дополняет последний байт нулями и сбрасывает буфер
+func (b *bitWriter) flush() error {
+	if b.nbits > 0 {
+		if err := b.w.WriteByte(b.cur << (8 - b.nbits)); err != nil {
+			return err
+		}
+		b.cur, b.nbits = 0, 0
+	}
+	return b.w.Flush()
+}
+
+// bitReader читает биты из потока байтов, старший бит первым
+type bitReader struct {
+	r     *bufio.Reader
+	cur   byte
+	nbits int
+}
+
+// readBits заполняет dst битами; возвращает число прочитанных бит и io.EOF,
+// если поток закончился раньше
+func (b *bitReader) readBits(dst []int) (int, error) {
+	for i := range dst {
+		if b.nbits == 0 {
+			c, err := b.r.ReadByte()
+			if err != nil {
+				return i, err
+			}
+			b.cur, b.nbits = c, 8
+		}
+		b.nbits--
+		dst[i] = int(b.cur>>b.nbits) & 1
+	}
+	return len(dst), nil
+}
+
+// byteBits добавляет к bits 8 бит байта c, старший первым
+func byteBits(bits []int, c byte) []int {
+	for i := 7; i >= 0; i-- {
+		bits = append(bits, int(c>>i)&1)
+	}
+	return bits
+}
+
+// lengthBlocks возвращает число блоков длины для кода с k информационными битами
+func lengthBlocks(k int) int {
+	return (lengthBits + k - 1) / k
+}
+
+// EncodeSECDEDStream кодирует поток r кодом SECDED с k информационными
+// битами и записывает результат с заголовком в w. Возвращает длину исходного
+// потока в байтах.
+func EncodeSECDEDStream(w io.Writer, r io.Reader, k int) (int64, error) {
+	code, err := NewSECDED(k)
+	if err != nil {
+		return 0, err
+	}
+	if k > 255 {
+		return 0, fmt.Errorf("k = %d не помещается в заголовок", k)
+	}
+	bw := &bitWriter{w: bufio.NewWriter(w)}
+	header := append([]byte(streamMagic), streamVersion, byte(k), streamVersion^byte(k)^0xA5)
+	if _, err := bw.w.Write(header); err != nil {
+		return 0, err
+	}
+
+	encode := func(data []int) error {
+		word, err := code.Encode(data)
+		if err != nil {
+			return err
+		}
+		return bw.writeBits(word)
+	}
+	br := bufio.NewReader(r)
+	var length int64
+	pending := make([]int, 0, k+8)
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return length, err
+		}
+		length++
+		pending = byteBits(pending, c)
+		for len(pending) >= k {
+			if err := encode(pending[:k]); err != nil {
+				return length, err
+			}
+			pending = append(pending[:0], pending[k:]...)
+		}
+	}
+	if len(pending) > 0 {
+		if err := encode(append(pending, make([]int, k-len(pending))...)); err != nil {
+			return length, err
+		}
+	}
+
+	var lenBytes [8]byte
+	binary.BigEndian.PutUint64(lenBytes[:], uint64(length))
+	bits := make([]int, 0, lengthBlocks(k)*k)
+	for _, c := range lenBytes {
+		bits = byteBits(bits, c)
+	}
+	bits = append(bits, make([]int, lengthBlocks(k)*k-lengthBits)...)
+	for i := 0; i < len(bits); i += k {
+		if err := encode(bits[i : i+k]); err != nil {
+			return length, err
+		}
+	}
+	return length, bw.flush()
+}
+
+// readStreamHeader читает и проверяет заголовок, возвращает k
+func readStreamHeader(r io.Reader) (int, error) {
+	var header [streamHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, fmt.Errorf("заголовок: %w", err)
+	}
+	if string(header[:len(streamMagic)]) != streamMagic {
+		return 0, errors.New("заголовок: файл не закодирован командой secded encode")
+	}
+	version, k, check := header[4], header[5], header[6]
+	if version != streamVersion {
+		return 0, fmt.Errorf("заголовок: неподдерживаемая версия формата %d", version)
+	}
+	if check != version^k^0xA5 {
+		return 0, errors.New("заголовок повреждён: контрольный байт не совпадает")
+	}
+	return int(k), nil
+}
+
+// DecodeSECDEDStream декодирует поток, записанный EncodeSECDEDStream,
+// исправляя одиночные ошибки в блоках, и записывает исходные данные в w.
+// Блоки с неисправимыми ошибками декодируются как есть и попадают в отчёт.
+func DecodeSECDEDStream(w io.Writer, r io.Reader) (StreamReport, error) {
+	br := bufio.NewReader(r)
+	k, err := readStreamHeader(br)
+	if err != nil {
+		return StreamReport{}, err
+	}
+	code, err := NewSECDED(k)
+	if err != nil {
+		return StreamReport{}, fmt.Errorf("заголовок: %w", err)
+	}
+	n := code.Length()
+	rep := StreamReport{K: k}
+	bwr := bufio.NewWriter(w)
+
+	// Последние блоки потока — блоки длины, поэтому блок декодируется как блок
+	// данных, только когда после него прочитано ещё tail блоков. Последние
+	// байты данных придерживаются: в них может оказаться дополнение нулями
+	// длиной до k-1 бит.
+	tail := lengthBlocks(k)
+	keep := (k + 7) / 8
+	var queue [][]int
+	var held []byte
+	var written int64
+	pending := make([]int, 0, k+8)
+
+	decode := func(word []int, dataOffset int64) ([]int, error) {
+		res, err := code.Decode(word)
+		if err != nil {
+			return nil, err
+		}
+		if res.Status != StatusOK {
+			ev := BlockEvent{
+				Block:      rep.Blocks,
+				Offset:     int64(streamHeaderSize) + int64(rep.Blocks)*int64(n)/8,
+				DataOffset: dataOffset,
+				Status:     res.Status,
+				ErrorPos:   res.ErrorPos,
+			}
+			rep.Events = append(rep.Events, ev)
+			if res.Status == StatusCorrected {
+				rep.Corrected++
+			} else {
+				rep.Detected++
+			}
+		}
+		rep.Blocks++
+		return res.Data, nil
+	}
+
+	bits := &bitReader{r: br}
+	for {
+		word := make([]int, n)
+		got, err := bits.readBits(word)
+		if err == io.EOF {
+			if got >= 8 {
+				return rep, errors.New("файл обрезан: последний блок неполон")
+			}
+			break
+		}
+		if err != nil {
+			return rep, err
+		}
+		queue = append(queue, word)
+		if len(queue) <= tail {
+			continue
+		}
+		data, err := decode(queue[0], int64(rep.Blocks)*int64(k)/8)
+		if err != nil {
+			return rep, err
+		}
+		queue = queue[1:]
+		pending = append(pending, data...)
+		for len(pending) >= 8 {
+			var c byte
+			for _, bit := range pending[:8] {
+				c = c<<1 | byte(bit)
+			}
+			pending = pending[8:]
+			held = append(held, c)
+		}
+		if len(held) > keep {
+			if _, err := bwr.Write(held[:len(held)-keep]); err != nil {
+				return rep, err
+			}
+			written += int64(len(held) - keep)
+			held = append(held[:0], held[len(held)-keep:]...)
+		}
+	}
+	if len(queue) != tail {
+		return rep, fmt.Errorf("файл обрезан: ожидалось не меньше %d блоков длины, найдено %d", tail, len(queue))
+	}
+
+	dataBlocks := rep.Blocks
+	var lenBits []int
+	for _, word := range queue {
+		data, err := decode(word, -1)
+		if err != nil {
+			return rep, err
+		}
+		lenBits = append(lenBits, data...)
+	}
+	var length uint64
+	for _, bit := range lenBits[:lengthBits] {
+		length = length<<1 | uint64(bit)
+	}
+	if want := (length*8 + uint64(k) - 1) / uint64(k); want != uint64(dataBlocks) || uint64(written) > length {
+		return rep, fmt.Errorf("повреждена длина файла: %d байт не соответствует %d блокам данных", length, dataBlocks)
+	}
+	rest := length - uint64(written)
+	if rest > uint64(len(held)) {
+		return rep, fmt.Errorf("повреждена длина файла: %d байт, восстановлено %d", length, written+int64(len(held)))
+	}
+	if _, err := bwr.Write(held[:rest]); err != nil {
+		return rep, err
+	}
+	rep.Bytes = int64(length)
+	return rep, bwr.Flush()
+}
@@ -0,0 +1,102 @@
+package coding
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestSECDEDStreamRoundTrip проверяет, что неповреждённый поток
+// восстанавливается для разных k и длин, в том числе когда дополнение
+// последнего блока данных длиннее 8 байт
+func TestSECDEDStreamRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, k := range []int{1, 4, 8, 11, 57, 64, 80, 100, 120, 200, 247, 255} {
+		for _, size := range []int{0, 1, 5, 8, 9, 31, 100, 1000} {
+			data := make([]byte, size)
+			rng.Read(data)
+			var encoded, decoded bytes.Buffer
+			if _, err := EncodeSECDEDStream(&encoded, bytes.NewReader(data), k); err != nil {
+				t.Fatalf("k = %d, %d байт: кодирование: %v", k, size, err)
+			}
+			rep, err := DecodeSECDEDStream(&decoded, &encoded)
+			if err != nil {
+				t.Fatalf("k = %d, %d байт: декодирование: %v", k, size, err)
+			}
+			if !bytes.Equal(decoded.Bytes(), data) {
+				t.Errorf("k = %d, %d байт: восстановленные данные не совпадают с исходными", k, size)
+			}
+			if rep.Bytes != int64(size) || rep.Corrected != 0 || rep.Detected != 0 {
+				t.Errorf("k = %d, %d байт: отчёт %+v", k, size, rep)
+			}
+		}
+	}
+}
+
+// TestSECDEDStreamReport проверяет отчёт о повреждениях: однократная ошибка
+// в одном блоке данных исправляется, двукратная в другом только
+// обнаруживается, а смещения блоков указаны верно
+func TestSECDEDStreamReport(t *testing.T) {
+	// При k = 64 слово занимает 72 бита — ровно 9 байт, блок данных — 8 байт
+	const k, wordBytes, blockData = 64, 9, 8
+	data := make([]byte, 8*blockData)
+	rand.New(rand.NewSource(1)).Read(data)
+	var encoded bytes.Buffer
+	if _, err := EncodeSECDEDStream(&encoded, bytes.NewReader(data), k); err != nil {
+		t.Fatal(err)
+	}
+	raw := encoded.Bytes()
+	corrected, detected := 2, 5
+	correctedOffset := int64(streamHeaderSize + corrected*wordBytes)
+	detectedOffset := int64(streamHeaderSize + detected*wordBytes)
+	raw[correctedOffset] ^= 0x10  // бит 4 слова
+	raw[detectedOffset+3] ^= 0x81 // биты 25 и 32 слова
+
+	var decoded bytes.Buffer
+	rep, err := DecodeSECDEDStream(&decoded, bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BlockEvent{
+		{Block: corrected, Offset: correctedOffset, DataOffset: int64(corrected * blockData), Status: StatusCorrected, ErrorPos: 4},
+		{Block: detected, Offset: detectedOffset, DataOffset: int64(detected * blockData), Status: StatusDetected},
+	}
+	if rep.Corrected != 1 || rep.Detected != 1 || len(rep.Events) != len(want) {
+		t.Fatalf("исправлено %d, обнаружено %d, событий %d: %+v", rep.Corrected, rep.Detected, len(rep.Events), rep.Events)
+	}
+	for i, ev := range rep.Events {
+		if ev != want[i] {
+			t.Errorf("событие %d: %+v, ожидалось %+v", i, ev, want[i])
+		}
+	}
+	out := decoded.Bytes()
+	if len(out) != len(data) {
+		t.Fatalf("восстановлено %d байт, ожидалось %d", len(out), len(data))
+	}
+	// Все блоки, кроме блока с неисправимой ошибкой, включая исправленный, совпадают с исходными
+	lo, hi := detected*blockData, (detected+1)*blockData
+	if !bytes.Equal(out[:lo], data[:lo]) || !bytes.Equal(out[hi:], data[hi:]) {
+		t.Error("данные вне блока с неисправимой ошибкой не восстановлены")
+	}
+}
+
+// TestSECDEDStreamHeader проверяет, что повреждённый заголовок отвергается
+func TestSECDEDStreamHeader(t *testing.T) {
+	var encoded bytes.Buffer
+	if _, err := EncodeSECDEDStream(&encoded, bytes.NewReader([]byte("ITC")), 11); err != nil {
+		t.Fatal(err)
+	}
+	for i, what := range []string{"сигнатура", "сигнатура", "сигнатура", "сигнатура", "версия", "k", "контрольный байт"} {
+		raw := bytes.Clone(encoded.Bytes())
+		raw[i] ^= 0x01
+		_, err := DecodeSECDEDStream(io.Discard, bytes.NewReader(raw))
+		if err == nil || !strings.Contains(err.Error(), "заголов") {
+			t.Errorf("%s (байт %d) повреждён: ошибка %v, ожидалась ошибка заголовка", what, i, err)
+		}
+	}
+	if _, err := DecodeSECDEDStream(io.Discard, bytes.NewReader(encoded.Bytes()[:streamHeaderSize-1])); err == nil {
+		t.Error("обрезанный заголовок принят")
+	}
+}
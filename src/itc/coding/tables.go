@@ -0,0 +1,152 @@
+package coding
+
+import "fmt"
+
+// maxTableRedundancy ограничивает число проверочных бит, для которого строится
+// таблица синдромов из 2^r элементов
+const maxTableRedundancy = 20
+
+// Tables — таблицы для кодирования и декодирования за O(1) на слово:
+// проверочные биты и синдром складываются (XOR) из вкладов отдельных байтов,
+// а позиция ошибки берётся из таблицы по значению синдрома. Биты в байтах
+// нумеруются с младшего, как в BitVec: байт j содержит биты 8j..8j+7.
+type Tables struct {
+	Name          string
+	N, K, R       int
+	DataPos       []int         // позиции информационных бит в слове (с 0)
+	CheckPos      []int         // позиции проверочных бит в слове (с 0)
+	CheckBytes    [][256]uint64 // байт данных j -> проверочные биты (бит i — позиция CheckPos[i])
+	SyndromeBytes [][256]uint64 // байт принятого слова j -> вклад в синдром (бит i — i-я строка H)
+	ErrorPos      []int32       // синдром -> позиция ошибки (с 1); 0 — ошибок нет, -1 — ошибка неисправима
+	runs          []bitRun
+}
+
+// NewTables строит таблицы кода code
+func NewTables(code LinearCode) (*Tables, error) {
+	if err := checkSingleError(code); err != nil {
+		return nil, err
+	}
+	G, H := code.GeneratorMatrix(), code.ParityCheckMatrix()
+	n, k, r := code.Length(), code.Dimension(), len(H)
+	if r > maxTableRedundancy {
+		return nil, fmt.Errorf("%s: таблица синдромов для %d проверочных бит слишком велика (не больше %d)",
+			code.Name(), r, maxTableRedundancy)
+	}
+	dataPos, err := dataPositions(code, G)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tables{Name: code.Name(), N: n, K: k, R: r, DataPos: dataPos, runs: dataRuns(dataPos)}
+	isData := make([]bool, n)
+	for _, pos := range dataPos {
+		isData[pos] = true
+	}
+	for j := 0; j < n; j++ {
+		if !isData[j] {
+			t.CheckPos = append(t.CheckPos, j)
+		}
+	}
+
+	// Проверочные биты линейны по данным: вклад байта — сумма вкладов его единичных бит
+	t.CheckBytes = make([][256]uint64, (k+7)/8)
+	for j := range t.CheckBytes {
+		for v := 1; v < 256; v++ {
+			var check uint64
+			for b := 0; b < 8 && 8*j+b < k; b++ {
+				if v>>b&1 == 1 {
+					for i, pos := range t.CheckPos {
+						check ^= uint64(G[8*j+b][pos]) << i
+					}
+				}
+			}
+			t.CheckBytes[j][v] = check
+		}
+	}
+
+	columns := make([]uint64, n)
+	for j := range columns {
+		for i := range H {
+			columns[j] |= uint64(H[i][j]) << i
+		}
+	}
+	t.SyndromeBytes = make([][256]uint64, (n+7)/8)
+	for j := range t.SyndromeBytes {
+		for v := 1; v < 256; v++ {
+			var s uint64
+			for b := 0; b < 8 && 8*j+b < n; b++ {
+				if v>>b&1 == 1 {
+					s ^= columns[8*j+b]
+				}
+			}
+			t.SyndromeBytes[j][v] = s
+		}
+	}
+
+	t.ErrorPos = make([]int32, 1<<r)
+	for s := range t.ErrorPos {
+		t.ErrorPos[s] = -1
+	}
+	t.ErrorPos[0] = 0
+	for j := n - 1; j >= 0; j-- {
+		if columns[j] != 0 {
+			t.ErrorPos[columns[j]] = int32(j + 1)
+		}
+	}
+	return t, nil
+}
+
+// byteAt возвращает байт j вектора; биты за пределами длины равны нулю
+func byteAt(v BitVec, j int) int {
+	return int(v.words[j/8] >> (8 * (j % 8)) & 0xff)
+}
+
+// Checks возвращает проверочные биты для данных data
+func (t *Tables) Checks(data BitVec) uint64 {
+	var check uint64
+	for j := range t.CheckBytes {
+		check ^= t.CheckBytes[j][byteAt(data, j)]
+	}
+	return check
+}
+
+// EncodeTo кодирует data в слово word по таблицам
+func (t *Tables) EncodeTo(word, data BitVec) {
+	for _, run := range t.runs {
+		word.SetUint64(run.word, run.length, data.Uint64(run.data, run.length))
+	}
+	check := t.Checks(data)
+	for i, pos := range t.CheckPos {
+		word.Set(pos, int(check>>i)&1)
+	}
+}
+
+// Syndrome вычисляет синдром принятого слова по таблицам
+func (t *Tables) Syndrome(word BitVec) uint64 {
+	var s uint64
+	for j := range t.SyndromeBytes {
+		s ^= t.SyndromeBytes[j][byteAt(word, j)]
+	}
+	return s
+}
+
+// Correct исправляет однократную ошибку в слове на месте; возвращает синдром,
+// итог декодирования и позицию ошибки (с 1)
+func (t *Tables) Correct(word BitVec) (uint64, DecodeStatus, int) {
+	s := t.Syndrome(word)
+	switch pos := t.ErrorPos[s]; {
+	case pos == 0:
+		return s, StatusOK, 0
+	case pos < 0:
+		return s, StatusDetected, 0
+	default:
+		word.Flip(int(pos) - 1)
+		return s, StatusCorrected, int(pos)
+	}
+}
+
+// ExtractTo переносит информационные биты слова word в data
+func (t *Tables) ExtractTo(data, word BitVec) {
+	for _, run := range t.runs {
+		data.SetUint64(run.data, run.length, word.Uint64(run.word, run.length))
+	}
+}
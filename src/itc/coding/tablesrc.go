@@ -0,0 +1,213 @@
+package coding
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"math/bits"
+	"strings"
+)
+
+// TableLanguage — язык исходного текста таблиц
+type TableLanguage string
+
+const (
+	TableGo TableLanguage = "go"
+	TableC  TableLanguage = "c"
+)
+
+// ParseTableLanguage проверяет название языка
+func ParseTableLanguage(s string) (TableLanguage, error) {
+	switch l := TableLanguage(strings.ToLower(s)); l {
+	case TableGo, TableC:
+		return l, nil
+	}
+	return "", fmt.Errorf("неизвестный язык %q (ожидается go или c)", s)
+}
+
+// uintBits возвращает наименьшую ширину беззнакового типа, вмещающего n бит
+func uintBits(n int) int {
+	for _, w := range []int{8, 16, 32} {
+		if n <= w {
+			return w
+		}
+	}
+	return 64
+}
+
+// intBits возвращает наименьшую ширину знакового типа для чисел от -1 до max
+func intBits(max int) int {
+	for _, w := range []int{8, 16} {
+		if max < 1<<(w-1) {
+			return w
+		}
+	}
+	return 32
+}
+
+// tableSource выводит таблицы на Go или C; различия языков собраны в методах
+type tableSource struct {
+	w      *bytes.Buffer
+	lang   TableLanguage
+	prefix string
+}
+
+// ident возвращает имя с префиксом: secdedCheck в Go, secded_check в C
+func (s *tableSource) ident(name string) string {
+	if s.lang == TableC {
+		return s.prefix + "_" + strings.ToLower(name)
+	}
+	return s.prefix + name
+}
+
+// constant выводит целочисленную константу
+func (s *tableSource) constant(name, comment string, v int) {
+	if s.lang == TableC {
+		fmt.Fprintf(s.w, "#define %s %d /* %s */\n", strings.ToUpper(s.ident(name)), v, comment)
+		return
+	}
+	fmt.Fprintf(s.w, "\t%s = %d // %s\n", s.ident(name), v, comment)
+}
+
+// typeName возвращает имя целочисленного типа ширины width
+func (s *tableSource) typeName(signed bool, width int) string {
+	name := fmt.Sprintf("int%d", width)
+	if !signed {
+		name = "u" + name
+	}
+	if s.lang == TableC {
+		name += "_t"
+	}
+	return name
+}
+
+// array выводит массив значений; dims — размеры, values — элементы подряд
+func (s *tableSource) array(name, comment string, signed bool, width int, dims []int, values []int64, hex bool) {
+	typ := s.typeName(signed, width)
+	format := "%d"
+	if hex {
+		format = fmt.Sprintf("0x%%0%dx", width/4)
+	}
+	if s.lang == TableC {
+		fmt.Fprintf(s.w, "\n/* %s */\nstatic const %s %s", comment, typ, s.ident(name))
+		for _, d := range dims {
+			fmt.Fprintf(s.w, "[%d]", d)
+		}
+		fmt.Fprint(s.w, " = ")
+	} else {
+		fmt.Fprintf(s.w, "\n// %s — %s\nvar %s = ", s.ident(name), comment, s.ident(name))
+		for _, d := range dims {
+			fmt.Fprintf(s.w, "[%d]", d)
+		}
+		fmt.Fprint(s.w, typ)
+	}
+	s.elements(dims, values, format, 0)
+	if s.lang == TableC {
+		fmt.Fprint(s.w, ";")
+	}
+	fmt.Fprintln(s.w)
+}
+
+// elements выводит вложенные фигурные скобки для массива размеров dims
+func (s *tableSource) elements(dims []int, values []int64, format string, depth int) {
+	indent := strings.Repeat("\t", depth+1)
+	if len(dims) == 1 {
+		fmt.Fprint(s.w, "{")
+		for i, v := range values {
+			if i%16 == 0 {
+				fmt.Fprint(s.w, "\n", indent)
+			} else {
+				fmt.Fprint(s.w, " ")
+			}
+			fmt.Fprintf(s.w, format+",", v)
+		}
+		fmt.Fprint(s.w, "\n", indent[1:], "}")
+		return
+	}
+	size := len(values) / dims[0]
+	fmt.Fprint(s.w, "{\n")
+	for i := 0; i < dims[0]; i++ {
+		fmt.Fprint(s.w, indent)
+		s.elements(dims[1:], values[i*size:(i+1)*size], format, depth+1)
+		fmt.Fprint(s.w, ",\n")
+	}
+	fmt.Fprint(s.w, indent[1:], "}")
+}
+
+// WriteSource выводит таблицы t исходным текстом на языке lang. Имена
+// констант и массивов начинаются с prefix; pkg — имя пакета Go.
+func (t *Tables) WriteSource(w io.Writer, lang TableLanguage, pkg, prefix string) error {
+	s := &tableSource{w: &bytes.Buffer{}, lang: lang, prefix: prefix}
+	checkBits, posBits := uintBits(t.R), uintBits(bits.Len(uint(t.N)))
+	errBits := intBits(t.N)
+	var err error
+
+	description := []string{
+		fmt.Sprintf("Таблицы кода %s: n = %d, k = %d, r = %d.", t.Name, t.N, t.K, t.R),
+		"Биты нумеруются с 0, байт j содержит биты 8j..8j+7, младший бит байта — бит 8j.",
+		"Кодирование: информационный бит i записывается в позицию DataPos[i], проверочные",
+		"биты — XOR значений Check[j][байт j данных], бит i результата — в позицию CheckPos[i].",
+		"Декодирование: синдром — XOR значений Syndrome[j][байт j принятого слова];",
+		"ErrorPos[синдром] — позиция ошибки с 1, 0 — ошибок нет, -1 — ошибка неисправима.",
+	}
+	if lang == TableC {
+		fmt.Fprintln(s.w, "/* Сгенерировано командой itc tables, не редактировать. */")
+		fmt.Fprintln(s.w)
+		fmt.Fprintln(s.w, "/*")
+		for _, line := range description {
+			fmt.Fprintln(s.w, " * "+line)
+		}
+		fmt.Fprintln(s.w, " */")
+		guard := strings.ToUpper(prefix) + "_TABLES_H"
+		fmt.Fprintf(s.w, "#ifndef %s\n#define %s\n\n#include <stdint.h>\n\n", guard, guard)
+	} else {
+		fmt.Fprintln(s.w, "// Code generated by itc tables. DO NOT EDIT.")
+		fmt.Fprintln(s.w)
+		for _, line := range description {
+			fmt.Fprintln(s.w, "// "+line)
+		}
+		fmt.Fprintf(s.w, "\npackage %s\n\nconst (\n", pkg)
+	}
+	s.constant("N", "длина кодового слова", t.N)
+	s.constant("K", "число информационных бит", t.K)
+	s.constant("R", "число проверочных бит", t.R)
+	if lang == TableGo {
+		fmt.Fprintln(s.w, ")")
+	}
+
+	ints := func(a []int) []int64 {
+		v := make([]int64, len(a))
+		for i, x := range a {
+			v[i] = int64(x)
+		}
+		return v
+	}
+	flatten := func(tables [][256]uint64) []int64 {
+		v := make([]int64, 0, 256*len(tables))
+		for _, table := range tables {
+			for _, x := range table {
+				v = append(v, int64(x))
+			}
+		}
+		return v
+	}
+	errorPos := make([]int64, len(t.ErrorPos))
+	for i, pos := range t.ErrorPos {
+		errorPos[i] = int64(pos)
+	}
+	s.array("DataPos", "позиции информационных бит в слове", false, posBits, []int{t.K}, ints(t.DataPos), false)
+	s.array("CheckPos", "позиции проверочных бит в слове", false, posBits, []int{t.R}, ints(t.CheckPos), false)
+	s.array("Check", "проверочные биты по байтам данных", false, checkBits, []int{len(t.CheckBytes), 256}, flatten(t.CheckBytes), true)
+	s.array("Syndrome", "вклад байтов принятого слова в синдром", false, checkBits, []int{len(t.SyndromeBytes), 256}, flatten(t.SyndromeBytes), true)
+	s.array("ErrorPos", "позиция ошибки по синдрому", true, errBits, []int{len(t.ErrorPos)}, errorPos, false)
+	src := s.w.Bytes()
+	if lang == TableC {
+		fmt.Fprintf(s.w, "\n#endif\n")
+		src = s.w.Bytes()
+	} else if src, err = format.Source(src); err != nil {
+		return fmt.Errorf("сгенерированный текст на Go: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
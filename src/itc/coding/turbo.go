@@ -0,0 +1,478 @@
+package coding
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// BCJRAlgorithm — вариант алгоритма BCJR для составляющих декодеров
+type BCJRAlgorithm int
+
+const (
+	LogMAP    BCJRAlgorithm = iota // точная операция max* = max + ln(1 + e^-|a-b|)
+	MaxLogMAP                      // приближение max* ≈ max
+)
+
+// String возвращает название алгоритма
+func (a BCJRAlgorithm) String() string {
+	if a == MaxLogMAP {
+		return "max-log-map"
+	}
+	return "log-map"
+}
+
+// ParseBCJRAlgorithm разбирает название алгоритма: log-map или max-log-map
+func ParseBCJRAlgorithm(s string) (BCJRAlgorithm, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "log-map", "logmap":
+		return LogMAP, nil
+	case "max-log-map", "maxlogmap":
+		return MaxLogMAP, nil
+	}
+	return 0, fmt.Errorf("неизвестный алгоритм %q (ожидается log-map или max-log-map)", s)
+}
+
+// TurboDecoder — параметры итеративного декодера турбокода
+type TurboDecoder struct {
+	Algorithm     BCJRAlgorithm
+	MaxIterations int     // наибольшее число итераций; DecodeLLR останавливается, когда решения перестают меняться
+	Crossover     float64 // вероятность ошибки ДСК, по которой Decode переводит биты в LLR
+}
+
+// DefaultTurboDecoder возвращает параметры декодера по умолчанию
+func DefaultTurboDecoder() TurboDecoder {
+	return TurboDecoder{Algorithm: LogMAP, MaxIterations: 8, Crossover: 0.05}
+}
+
+// check проверяет параметры декодера
+func (d TurboDecoder) check() error {
+	if d.MaxIterations <= 0 {
+		return fmt.Errorf("число итераций должно быть больше 0, получено %d", d.MaxIterations)
+	}
+	if d.Crossover <= 0 || d.Crossover >= 0.5 {
+		return fmt.Errorf("вероятность ошибки канала %g вне интервала (0, 0.5)", d.Crossover)
+	}
+	return nil
+}
+
+// RSC — рекурсивный систематический свёрточный кодер скорости 1/2 с памятью
+// m. Полиномы обратной связи и прямой ветви заданы восьмеричными числами из
+// m+1 бит, старший бит — коэффициент при D^0: (13, 15) — это 1 + D² + D³ и
+// 1 + D + D³ (LTE). Состояние хранит m предыдущих бит регистра, бит i-1 —
+// значение i тактов назад.
+type RSC struct {
+	Memory            int
+	Feedback, Forward int // полиномы в двоичной записи (печатаются восьмерично)
+
+	next, parity [][2]int // переход и проверочный бит из состояния s при входе u
+	tail         []int    // вход, при котором регистр получает 0 (завершение решётки)
+}
+
+// NewRSC строит кодер по полиномам в восьмеричной записи, например "13" и "15"
+func NewRSC(feedback, forward string) (*RSC, error) {
+	fb, err1 := strconv.ParseUint(feedback, 8, 16)
+	ff, err2 := strconv.ParseUint(forward, 8, 16)
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("полиномы RSC %q и %q должны быть восьмеричными числами", feedback, forward)
+	}
+	m := max(bits.Len64(fb), bits.Len64(ff)) - 1
+	if m < 1 || m > 8 || fb>>m&1 == 0 {
+		return nil, fmt.Errorf("полиномы RSC %s и %s: ожидается память 1..8 и единичный коэффициент при D^0 у обратной связи", feedback, forward)
+	}
+	c := &RSC{Memory: m, Feedback: int(fb), Forward: int(ff)}
+	coef := func(g, i int) int { return g >> (m - i) & 1 }
+	states := 1 << m
+	c.next = make([][2]int, states)
+	c.parity = make([][2]int, states)
+	c.tail = make([]int, states)
+	for s := 0; s < states; s++ {
+		fbSum := 0
+		for i := 1; i <= m; i++ {
+			fbSum ^= coef(c.Feedback, i) & (s >> (i - 1))
+		}
+		c.tail[s] = fbSum
+		for u := 0; u < 2; u++ {
+			a := u ^ fbSum
+			p := coef(c.Forward, 0) & a
+			for i := 1; i <= m; i++ {
+				p ^= coef(c.Forward, i) & (s >> (i - 1))
+			}
+			c.next[s][u] = (s<<1 | a) & (states - 1)
+			c.parity[s][u] = p
+		}
+	}
+	return c, nil
+}
+
+// String возвращает полиномы кодера, например "RSC (13,15)"
+func (c *RSC) String() string { return fmt.Sprintf("RSC (%o,%o)", c.Feedback, c.Forward) }
+
+// encode кодирует биты u и завершает решётку: возвращает проверочные биты и
+// m пар (систематический, проверочный) бит завершения
+func (c *RSC) encode(u []int) (parity, tail []int) {
+	s := 0
+	parity = make([]int, len(u))
+	for i, bit := range u {
+		parity[i] = c.parity[s][bit]
+		s = c.next[s][bit]
+	}
+	for t := 0; t < c.Memory; t++ {
+		bit := c.tail[s]
+		tail = append(tail, bit, c.parity[s][bit])
+		s = c.next[s][bit]
+	}
+	return parity, tail
+}
+
+// Turbo — турбокод: параллельное соединение двух одинаковых RSC-кодеров,
+// второй из которых получает данные через перемежитель. Кодовое слово:
+// k систематических бит, проверочные биты первого и второго кодеров (при
+// выкалывании — поочерёдно, скорость 1/2 вместо 1/3), затем по 2m бит
+// завершения решётки каждого кодера.
+type Turbo struct {
+	k        int
+	rsc      *RSC
+	perm     Interleaver
+	puncture bool
+	decoder  TurboDecoder
+}
+
+// NewTurbo строит турбокод с k информационными битами
+func NewTurbo(rsc *RSC, perm Interleaver, puncture bool) (*Turbo, error) {
+	if len(perm) == 0 {
+		return nil, fmt.Errorf("перемежитель пуст")
+	}
+	if err := perm.check(); err != nil {
+		return nil, err
+	}
+	return &Turbo{k: len(perm), rsc: rsc, perm: perm, puncture: puncture, decoder: DefaultTurboDecoder()}, nil
+}
+
+// Name возвращает название кода
+func (c *Turbo) Name() string {
+	name := fmt.Sprintf("Турбо (%d,%d), %s", c.Length(), c.k, c.rsc)
+	if c.puncture {
+		name += ", выкалывание"
+	}
+	return name
+}
+
+// Length возвращает длину кодового слова
+func (c *Turbo) Length() int {
+	n := 3*c.k + 4*c.rsc.Memory
+	if c.puncture {
+		n -= c.k
+	}
+	return n
+}
+
+// Dimension возвращает число информационных бит k
+func (c *Turbo) Dimension() int { return c.k }
+
+// Decoder возвращает параметры декодера
+func (c *Turbo) Decoder() TurboDecoder { return c.decoder }
+
+// SetDecoder задаёт параметры декодера
+func (c *Turbo) SetDecoder(d TurboDecoder) error {
+	if err := d.check(); err != nil {
+		return err
+	}
+	c.decoder = d
+	return nil
+}
+
+// parityPos возвращает позиции проверочных бит i первого и второго кодеров
+// (-1 — бит выколот)
+func (c *Turbo) parityPos(i int) (int, int) {
+	if !c.puncture {
+		return c.k + i, 2*c.k + i
+	}
+	if i%2 == 0 {
+		return c.k + i, -1
+	}
+	return -1, c.k + i
+}
+
+// tailPos возвращает позицию начала бит завершения кодера enc (0 или 1)
+func (c *Turbo) tailPos(enc int) int {
+	return c.Length() - 4*c.rsc.Memory + enc*2*c.rsc.Memory
+}
+
+// Encode кодирует данные двумя RSC-кодерами
+func (c *Turbo) Encode(data []int) ([]int, error) {
+	if err := checkBits("данные", data, c.k); err != nil {
+		return nil, err
+	}
+	permuted := make([]int, c.k)
+	for i, j := range c.perm {
+		permuted[i] = data[j]
+	}
+	p1, t1 := c.rsc.encode(data)
+	p2, t2 := c.rsc.encode(permuted)
+	word := make([]int, c.Length())
+	copy(word, data)
+	for i := 0; i < c.k; i++ {
+		if a, b := c.parityPos(i); a >= 0 {
+			word[a] = p1[i]
+			if b >= 0 {
+				word[b] = p2[i]
+			}
+		} else {
+			word[b] = p2[i]
+		}
+	}
+	copy(word[c.tailPos(0):], t1)
+	copy(word[c.tailPos(1):], t2)
+	return word, nil
+}
+
+// Syndrome сравнивает принятые проверочные биты и биты завершения с
+// вычисленными заново по принятым систематическим битам: для
+// систематического кода с G = [I | P] это синдром по H = [Pᵀ | I]
+func (c *Turbo) Syndrome(word []int) []int {
+	expected, _ := c.Encode(word[:c.k])
+	return xorBits(word[c.k:], expected[c.k:])
+}
+
+// GeneratorMatrix возвращает систематическую производящую матрицу [I | P]
+func (c *Turbo) GeneratorMatrix() [][]int {
+	G := make([][]int, c.k)
+	for t := range G {
+		data := make([]int, c.k)
+		data[t] = 1
+		G[t], _ = c.Encode(data)
+	}
+	return G
+}
+
+// ParityCheckMatrix возвращает проверочную матрицу [Pᵀ | I]
+func (c *Turbo) ParityCheckMatrix() [][]int {
+	G := c.GeneratorMatrix()
+	n := c.Length()
+	H := newMatrix(n-c.k, n)
+	for i := range H {
+		for t := 0; t < c.k; t++ {
+			H[i][t] = G[t][c.k+i]
+		}
+		H[i][c.k+i] = 1
+	}
+	return H
+}
+
+// Decode переводит принятые биты в LLR по вероятности ошибки из параметров
+// декодера и декодирует их
+func (c *Turbo) Decode(received []int) (DecodeResult, error) {
+	if err := checkBits("принятое слово", received, c.Length()); err != nil {
+		return DecodeResult{}, err
+	}
+	return c.DecodeLLR(BSCLLR(received, c.decoder.Crossover))
+}
+
+// DecodeLLR декодирует слово итеративно; итерации прекращаются, когда
+// решения о данных перестают меняться, или по достижении MaxIterations
+func (c *Turbo) DecodeLLR(llr []float64) (DecodeResult, error) {
+	var prev []int
+	iterations := 0
+	err := c.iterate(llr, func(it int, data []int) bool {
+		iterations = it
+		stop := prev != nil && BitsEqual(prev, data)
+		prev = data
+		return !stop
+	})
+	if err != nil {
+		return DecodeResult{}, err
+	}
+	hard := make([]int, len(llr))
+	for j, l := range llr {
+		if l < 0 {
+			hard[j] = 1
+		}
+	}
+	res := DecodeResult{Data: prev, Syndrome: c.Syndrome(hard), Iterations: iterations}
+	res.Codeword, _ = c.Encode(prev)
+	for j := range hard {
+		if hard[j] != res.Codeword[j] {
+			res.ErrorPositions = append(res.ErrorPositions, j+1)
+		}
+	}
+	// Решение всегда кодовое слово: без внешней проверки (CRC) турбодекодер
+	// не отличает исправленное слово от ошибочного
+	res.Status = StatusOK
+	if len(res.ErrorPositions) > 0 {
+		res.Status = StatusCorrected
+		res.ErrorPos = res.ErrorPositions[0]
+	}
+	return res, nil
+}
+
+// DecodeTrace выполняет MaxIterations итераций без ранней остановки и
+// возвращает решения о данных после каждой итерации
+func (c *Turbo) DecodeTrace(llr []float64) ([][]int, error) {
+	var trace [][]int
+	err := c.iterate(llr, func(_ int, data []int) bool {
+		trace = append(trace, data)
+		return true
+	})
+	return trace, err
+}
+
+// iterate выполняет итерации турбодекодера: первый декодер BCJR получает
+// внешнюю информацию второго как априорную, второй — внешнюю информацию
+// первого через перемежитель. После каждой итерации вызывается each с
+// жёсткими решениями о данных; false прекращает итерации.
+func (c *Turbo) iterate(llr []float64, each func(it int, data []int) bool) error {
+	if len(llr) != c.Length() {
+		return fmt.Errorf("длина вектора LLR %d, ожидалось %d", len(llr), c.Length())
+	}
+	m := c.rsc.Memory
+	sys := llr[:c.k]
+	par1 := make([]float64, c.k+m)
+	par2 := make([]float64, c.k+m)
+	for i := 0; i < c.k; i++ {
+		a, b := c.parityPos(i)
+		if a >= 0 {
+			par1[i] = llr[a]
+		}
+		if b >= 0 {
+			par2[i] = llr[b]
+		}
+	}
+	sys1 := append(append([]float64(nil), sys...), make([]float64, m)...)
+	sys2 := append(c.perm.Interleave(sys), make([]float64, m)...)
+	for t := 0; t < m; t++ {
+		t1, t2 := c.tailPos(0)+2*t, c.tailPos(1)+2*t
+		sys1[c.k+t], par1[c.k+t] = llr[t1], llr[t1+1]
+		sys2[c.k+t], par2[c.k+t] = llr[t2], llr[t2+1]
+	}
+
+	apriori1 := make([]float64, c.k)
+	for it := 1; it <= c.decoder.MaxIterations; it++ {
+		ext1 := c.bcjr(sys1, par1, apriori1)
+		ext2 := c.bcjr(sys2, par2, c.perm.Interleave(ext1))
+		apriori1 = c.perm.Deinterleave(ext2)
+		data := make([]int, c.k)
+		for i := range data {
+			if sys[i]+ext1[i]+apriori1[i] < 0 {
+				data[i] = 1
+			}
+		}
+		if !each(it, data) {
+			break
+		}
+	}
+	return nil
+}
+
+// maxStar возвращает ln(e^a + e^b) для Log-MAP или max(a, b) для Max-Log-MAP
+func (c *Turbo) maxStar(a, b float64) float64 {
+	if math.IsInf(a, -1) {
+		return b
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+	if c.decoder.Algorithm == MaxLogMAP {
+		return math.Max(a, b)
+	}
+	return math.Max(a, b) + math.Log1p(math.Exp(-math.Abs(a-b)))
+}
+
+// bcjr — составляющий декодер BCJR в логарифмической области. Решётка
+// начинается и заканчивается в нулевом состоянии; последние m шагов —
+// завершение, на них вход определяется состоянием. Метрика ветви
+// γ = ((1-2u)(Lsys + La) + (1-2p) Lpar) / 2. Возвращает внешнюю информацию
+// L(u) - Lsys - La для k информационных шагов.
+func (c *Turbo) bcjr(lsys, lpar, apriori []float64) []float64 {
+	states := len(c.rsc.next)
+	steps := len(lsys)
+	negInf := math.Inf(-1)
+	// Половины входных LLR: γ = ±sys ± par
+	sys := make([]float64, steps)
+	par := make([]float64, steps)
+	for t := range sys {
+		sys[t] = lsys[t] / 2
+		if t < c.k {
+			sys[t] += apriori[t] / 2
+		}
+		par[t] = lpar[t] / 2
+	}
+	gamma := func(t, s, u int) float64 {
+		g := sys[t]
+		if u == 1 {
+			g = -g
+		}
+		if c.rsc.parity[s][u] == 0 {
+			return g + par[t]
+		}
+		return g - par[t]
+	}
+	// allowed сообщает, возможен ли вход u: на шагах завершения вход задан состоянием
+	allowed := func(t, s, u int) bool { return t < c.k || c.rsc.tail[s] == u }
+
+	alpha := make([]float64, (steps+1)*states)
+	for i := range alpha {
+		alpha[i] = negInf
+	}
+	alpha[0] = 0
+	for t := 0; t < steps; t++ {
+		cur, next := alpha[t*states:(t+1)*states], alpha[(t+1)*states:(t+2)*states]
+		for s, a := range cur {
+			if math.IsInf(a, -1) {
+				continue
+			}
+			for u := 0; u < 2; u++ {
+				if allowed(t, s, u) {
+					ns := c.rsc.next[s][u]
+					next[ns] = c.maxStar(next[ns], a+gamma(t, s, u))
+				}
+			}
+		}
+		// Нормировка, чтобы метрики не росли неограниченно
+		normalize(next)
+	}
+
+	beta := make([]float64, states)
+	prev := make([]float64, states)
+	for s := range beta {
+		beta[s] = negInf
+	}
+	beta[0] = 0
+	ext := make([]float64, c.k)
+	for t := steps - 1; t >= 0; t-- {
+		cur := alpha[t*states : (t+1)*states]
+		lu := [2]float64{negInf, negInf}
+		for s := range prev {
+			prev[s] = negInf
+			for u := 0; u < 2; u++ {
+				if !allowed(t, s, u) {
+					continue
+				}
+				v := gamma(t, s, u) + beta[c.rsc.next[s][u]]
+				prev[s] = c.maxStar(prev[s], v)
+				if t < c.k {
+					lu[u] = c.maxStar(lu[u], cur[s]+v)
+				}
+			}
+		}
+		if t < c.k {
+			ext[t] = lu[0] - lu[1] - lsys[t] - apriori[t]
+		}
+		normalize(prev)
+		beta, prev = prev, beta
+	}
+	return ext
+}
+
+// normalize вычитает из метрик состояний наибольшую
+func normalize(metrics []float64) {
+	best := math.Inf(-1)
+	for _, v := range metrics {
+		best = math.Max(best, v)
+	}
+	for i := range metrics {
+		metrics[i] -= best
+	}
+}
@@ -0,0 +1,200 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"itc/report"
+)
+
+// Размеры страницы A4 в двадцатых долях пункта и ширина текста между полями
+const (
+	textWidth = 9355 // 11906 - 1701 - 850
+	// emuPerPixel — EMU на пиксель при 96 точках на дюйм
+	emuPerPixel = 9525
+	// maxImageWidth — наибольшая ширина рисунка в EMU (16 см)
+	maxImageWidth = 16 * 360000
+)
+
+// builder хранит состояние одного документа: рисунки и нумерацию таблиц
+type builder struct {
+	media   [][]byte
+	tables  int
+	figures int
+}
+
+func (b *builder) funcs() template.FuncMap {
+	return template.FuncMap{
+		"heading":   b.heading,
+		"paragraph": b.paragraph,
+		"params":    b.params,
+		"table":     b.table,
+		"chart":     b.chart,
+		"pageBreak": b.pageBreak,
+	}
+}
+
+// escape экранирует текст для XML
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// run возвращает фрагмент текста абзаца
+func run(text string, bold bool) string {
+	props := ""
+	if bold {
+		props = "<w:rPr><w:b/></w:rPr>"
+	}
+	return fmt.Sprintf(`<w:r>%s<w:t xml:space="preserve">%s</w:t></w:r>`, props, escape(text))
+}
+
+// para возвращает абзац со стилем style и выравниванием jc (пусто — по умолчанию)
+func para(style, jc string, runs ...string) string {
+	var props string
+	if style != "" {
+		props += fmt.Sprintf(`<w:pStyle w:val="%s"/>`, style)
+	}
+	if jc != "" {
+		props += fmt.Sprintf(`<w:jc w:val="%s"/>`, jc)
+	}
+	if props != "" {
+		props = "<w:pPr>" + props + "</w:pPr>"
+	}
+	return "<w:p>" + props + strings.Join(runs, "") + "</w:p>"
+}
+
+func (b *builder) heading(level int, text string) (string, error) {
+	if level < 1 || level > 3 {
+		return "", fmt.Errorf("heading: уровень заголовка должен быть от 1 до 3, получено %d", level)
+	}
+	return para(fmt.Sprintf("Heading%d", level), "", run(text, false)), nil
+}
+
+func (b *builder) paragraph(text string) string {
+	return para("", "", run(text, false))
+}
+
+func (b *builder) pageBreak() string {
+	return `<w:p><w:r><w:br w:type="page"/></w:r></w:p>`
+}
+
+// grid возвращает таблицу с заголовком header и строками rows; right задаёт
+// выравнивание столбцов вправо
+func grid(header []string, rows [][]string, right []bool) string {
+	cols := len(header)
+	if cols == 0 {
+		return ""
+	}
+	width := textWidth / cols
+	// Мелкий шрифт, чтобы широкие таблицы (матрицы) помещались на странице
+	size := 20
+	switch {
+	case cols > 24:
+		size = 10
+	case cols > 12:
+		size = 14
+	}
+	cell := func(text string, right, header bool) string {
+		jc := ""
+		if right {
+			jc = "right"
+		}
+		props := fmt.Sprintf(`<w:tcPr><w:tcW w:w="%d" w:type="dxa"/>`, width)
+		bold := ""
+		if header {
+			props += `<w:shd w:val="clear" w:color="auto" w:fill="D9D9D9"/>`
+			bold = "<w:b/>"
+		}
+		props += "</w:tcPr>"
+		r := fmt.Sprintf(`<w:r><w:rPr>%s<w:sz w:val="%d"/></w:rPr><w:t xml:space="preserve">%s</w:t></w:r>`,
+			bold, size, escape(text))
+		return "<w:tc>" + props + para("TableText", jc, r) + "</w:tc>"
+	}
+
+	var buf strings.Builder
+	buf.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/><w:tblLayout w:type="fixed"/></w:tblPr><w:tblGrid>`)
+	for range header {
+		fmt.Fprintf(&buf, `<w:gridCol w:w="%d"/>`, width)
+	}
+	buf.WriteString(`</w:tblGrid><w:tr><w:trPr><w:tblHeader/></w:trPr>`)
+	for j, h := range header {
+		buf.WriteString(cell(h, right[j], true))
+	}
+	buf.WriteString("</w:tr>")
+	for _, row := range rows {
+		buf.WriteString("<w:tr>")
+		for j, v := range row {
+			buf.WriteString(cell(v, right[j], false))
+		}
+		buf.WriteString("</w:tr>")
+	}
+	buf.WriteString("</w:tbl>")
+	return buf.String()
+}
+
+func (b *builder) params(params []Param) string {
+	rows := make([][]string, len(params))
+	for i, p := range params {
+		rows[i] = []string{"--" + p.Name, p.Value, p.Usage}
+	}
+	return grid([]string{"Флаг", "Значение", "Описание"}, rows, []bool{false, false, false})
+}
+
+// caption возвращает подпись таблицы или рисунка
+func caption(kind string, number int, title string) string {
+	text := fmt.Sprintf("%s %d", kind, number)
+	if title != "" {
+		text += " — " + title
+	}
+	return para("Caption", "", run(text, false))
+}
+
+func (b *builder) table(t *report.RecordedTable) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("table: таблица не найдена")
+	}
+	b.tables++
+	header := make([]string, len(t.Table.Columns))
+	right := make([]bool, len(t.Table.Columns))
+	for j, c := range t.Table.Columns {
+		header[j] = c.Title
+		right[j] = c.RightAligned()
+	}
+	var buf strings.Builder
+	buf.WriteString(caption("Таблица", b.tables, t.Table.Title))
+	buf.WriteString(grid(header, t.Cells(), right))
+	for _, f := range t.Summary {
+		buf.WriteString(para("", "", run(f.Title+": ", true), run(f.Text(), false)))
+	}
+	return buf.String(), nil
+}
+
+func (b *builder) chart(c Chart) (string, error) {
+	if len(c.PNG) == 0 || c.Width <= 0 || c.Height <= 0 {
+		return "", fmt.Errorf("chart: пустой рисунок %q", c.Title)
+	}
+	b.media = append(b.media, c.PNG)
+	b.figures++
+	cx := int64(c.Width) * emuPerPixel
+	cy := int64(c.Height) * emuPerPixel
+	if cx > maxImageWidth {
+		cy = cy * maxImageWidth / cx
+		cx = maxImageWidth
+	}
+	id := len(b.media)
+	drawing := fmt.Sprintf(`<w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0">`+
+		`<wp:extent cx="%[1]d" cy="%[2]d"/><wp:docPr id="%[3]d" name="Рисунок %[3]d"/>`+
+		`<wp:cNvGraphicFramePr><a:graphicFrameLocks noChangeAspect="1"/></wp:cNvGraphicFramePr>`+
+		`<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:pic>`+
+		`<pic:nvPicPr><pic:cNvPr id="%[3]d" name="%[4]s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+		`<pic:blipFill><a:blip r:embed="%[5]s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+		`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%[1]d" cy="%[2]d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>`+
+		`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`,
+		cx, cy, id, mediaName(id-1), mediaID(id-1))
+	return para("", "center", drawing) + caption("Рисунок", b.figures, c.Title), nil
+}
@@ -0,0 +1,140 @@
+// Package docx формирует отчёты о лабораторных работах в формате DOCX
+// (Office Open XML). Вид отчёта задаёт шаблон text/template: он выводит тело
+// документа WordprocessingML с помощью функций heading, paragraph, params,
+// table и chart, а пакет собирает из тела, стилей и рисунков архив .docx.
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"text/template"
+
+	"itc/report"
+)
+
+// DefaultTemplate — шаблон отчёта по умолчанию
+//
+//go:embed default.tmpl
+var DefaultTemplate string
+
+// Param — параметр эксперимента (флаг команды)
+type Param struct {
+	Name  string
+	Value string
+	Usage string
+}
+
+// Chart — график, встраиваемый в отчёт изображением PNG
+type Chart struct {
+	Title  string
+	PNG    []byte
+	Width  int // размер изображения в пикселях
+	Height int
+}
+
+// Report — данные отчёта, доступные шаблону
+type Report struct {
+	Title   string
+	Command string
+	Date    string
+	Params  []Param
+	Tables  []report.RecordedTable
+	Charts  []Chart
+}
+
+// Table возвращает таблицу результатов с именем name или nil; позволяет
+// шаблону выводить таблицы в произвольном порядке
+func (r *Report) Table(name string) *report.RecordedTable {
+	for i := range r.Tables {
+		if r.Tables[i].Table.Name == name {
+			return &r.Tables[i]
+		}
+	}
+	return nil
+}
+
+// parse разбирает шаблон, связывая его функции с документом b
+func parse(layout string, b *builder) (*template.Template, error) {
+	tmpl, err := template.New("report").Funcs(b.funcs()).Parse(layout)
+	if err != nil {
+		return nil, fmt.Errorf("шаблон отчёта: %w", err)
+	}
+	return tmpl, nil
+}
+
+// CheckTemplate проверяет синтаксис шаблона до запуска эксперимента
+func CheckTemplate(layout string) error {
+	_, err := parse(layout, &builder{})
+	return err
+}
+
+// Write формирует документ по шаблону layout и записывает архив .docx в w
+func Write(w io.Writer, layout string, r *Report) error {
+	b := &builder{}
+	tmpl, err := parse(layout, b)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, r); err != nil {
+		return fmt.Errorf("шаблон отчёта: %w", err)
+	}
+	return b.writePackage(w, body.Bytes())
+}
+
+// writePackage собирает части документа в архив
+func (b *builder) writePackage(w io.Writer, body []byte) error {
+	z := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		f, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+
+	var rels bytes.Buffer
+	rels.WriteString(xmlHeader)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	rels.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	for i := range b.media {
+		fmt.Fprintf(&rels, `<Relationship Id="%s" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/%s"/>`,
+			mediaID(i), mediaName(i))
+	}
+	rels.WriteString(`</Relationships>`)
+
+	var doc bytes.Buffer
+	doc.WriteString(xmlHeader)
+	doc.WriteString(documentStart)
+	doc.Write(body)
+	doc.WriteString(documentEnd)
+
+	parts := []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", []byte(contentTypes)},
+		{"_rels/.rels", []byte(packageRels)},
+		{"word/document.xml", doc.Bytes()},
+		{"word/styles.xml", []byte(styles)},
+		{"word/_rels/document.xml.rels", rels.Bytes()},
+	}
+	for _, p := range parts {
+		if err := add(p.name, p.data); err != nil {
+			return err
+		}
+	}
+	for i, img := range b.media {
+		if err := add("word/media/"+mediaName(i), img); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+func mediaID(i int) string   { return fmt.Sprintf("rIdImage%d", i+1) }
+func mediaName(i int) string { return fmt.Sprintf("image%d.png", i+1) }
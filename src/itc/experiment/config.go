@@ -0,0 +1,366 @@
+// Package experiment описывает серии экспериментов в файлах конфигурации
+// YAML, JSON или TOML: команду itc, постоянные флаги и параметры, заданные
+// значением, списком или диапазоном. Серия разворачивается в декартово
+// произведение значений параметров — точки, в каждой из которых команда
+// запускается один раз.
+//
+// Пример конфигурации в YAML:
+//
+//	command: channel
+//	fixed:
+//	  iterations: 6
+//	  seed: 1
+//	params:
+//	  n: {from: 8, to: 64, step: 8}
+//	  p-max: {from: 0.5, to: 1, step: 0.05}
+//	  source: [uniform, random]
+//	output:
+//	  dir: results
+//	  format: json
+package experiment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// maxPoints ограничивает число точек серии, чтобы опечатка в шаге диапазона
+// не запустила миллионы экспериментов
+const maxPoints = 100000
+
+// Assignment — значение флага команды
+type Assignment struct {
+	Name  string
+	Value string
+}
+
+// Param — параметр серии и все его значения
+type Param struct {
+	Name   string
+	Values []string
+}
+
+// Output — куда и в каком формате сохранять результаты точек
+type Output struct {
+	Dir    string
+	Format string
+}
+
+// Config — серия экспериментов
+type Config struct {
+	Command []string     // команда itc с подкомандами, например [hamming simulate]
+	Fixed   []Assignment // флаги, одинаковые во всех точках
+	Params  []Param      // параметры, по которым строится сетка точек
+	Output  Output
+}
+
+// Point — набор значений параметров в одной точке серии
+type Point []Assignment
+
+func (p Point) String() string {
+	strs := make([]string, len(p))
+	for i, a := range p {
+		strs[i] = a.Name + "=" + a.Value
+	}
+	return strings.Join(strs, " ")
+}
+
+// Load читает конфигурацию; формат определяется расширением файла
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	cfg, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse разбирает конфигурацию в формате yaml (yml), json или toml
+func Parse(data []byte, format string) (*Config, error) {
+	var raw map[string]any
+	var err error
+	switch format {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &raw)
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&raw)
+	case "toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("неизвестный формат конфигурации %q (ожидается yaml, json или toml)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromMap(raw)
+}
+
+// fromMap строит Config из разобранного документа
+func fromMap(raw map[string]any) (*Config, error) {
+	cfg := &Config{Output: Output{Dir: "results", Format: "json"}}
+	for key := range raw {
+		switch key {
+		case "command", "fixed", "params", "output":
+		default:
+			return nil, fmt.Errorf("неизвестный раздел конфигурации %q (ожидается command, fixed, params, output)", key)
+		}
+	}
+
+	command, ok := raw["command"].(string)
+	if !ok || strings.TrimSpace(command) == "" {
+		return nil, fmt.Errorf("не задана команда (command), например command: channel")
+	}
+	cfg.Command = strings.Fields(command)
+
+	fixed, err := section(raw, "fixed")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(fixed) {
+		value, err := scalar(fixed[name])
+		if err != nil {
+			return nil, fmt.Errorf("fixed.%s: %w", name, err)
+		}
+		cfg.Fixed = append(cfg.Fixed, Assignment{Name: name, Value: value})
+	}
+
+	params, err := section(raw, "params")
+	if err != nil {
+		return nil, err
+	}
+	points := 1
+	for _, name := range sortedKeys(params) {
+		if _, ok := fixed[name]; ok {
+			return nil, fmt.Errorf("params.%s: флаг уже задан в разделе fixed", name)
+		}
+		values, err := expand(params[name])
+		if err != nil {
+			return nil, fmt.Errorf("params.%s: %w", name, err)
+		}
+		points *= len(values)
+		if points > maxPoints {
+			return nil, fmt.Errorf("слишком много точек серии: больше %d", maxPoints)
+		}
+		cfg.Params = append(cfg.Params, Param{Name: name, Values: values})
+	}
+
+	output, err := section(raw, "output")
+	if err != nil {
+		return nil, err
+	}
+	for key, v := range output {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("output.%s: ожидается строка", key)
+		}
+		switch key {
+		case "dir":
+			cfg.Output.Dir = s
+		case "format":
+			cfg.Output.Format = s
+		default:
+			return nil, fmt.Errorf("неизвестный ключ output.%s (ожидается dir или format)", key)
+		}
+	}
+	return cfg, nil
+}
+
+// section возвращает раздел документа; отсутствующий раздел пуст
+func section(raw map[string]any, name string) (map[string]any, error) {
+	v, ok := raw[name]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("раздел %s должен быть таблицей ключ-значение", name)
+	}
+	return m, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatFloat выводит число без артефактов накопления шага (0.30000000000000004)
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', 12, 64)
+}
+
+// number приводит числовое значение документа к float64
+func number(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float64:
+		return x, true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// scalar приводит значение документа к строке — значению флага
+func scalar(v any) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case json.Number:
+		return x.String(), nil
+	}
+	if f, ok := number(v); ok {
+		return formatFloat(f), nil
+	}
+	return "", fmt.Errorf("ожидается число, строка или логическое значение, получено %v", v)
+}
+
+// expand возвращает все значения параметра: одно значение, список или
+// диапазон {from, to, step} либо {from, to, count[, log]}
+func expand(v any) ([]string, error) {
+	switch x := v.(type) {
+	case []any:
+		if len(x) == 0 {
+			return nil, fmt.Errorf("пустой список значений")
+		}
+		values := make([]string, len(x))
+		for i, item := range x {
+			s, err := scalar(item)
+			if err != nil {
+				return nil, fmt.Errorf("значение %d: %w", i+1, err)
+			}
+			values[i] = s
+		}
+		return values, nil
+	case map[string]any:
+		return expandRange(x)
+	}
+	s, err := scalar(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+// expandRange разворачивает диапазон значений
+func expandRange(r map[string]any) ([]string, error) {
+	nums := make(map[string]float64)
+	logScale := false
+	for key, v := range r {
+		switch key {
+		case "from", "to", "step", "count":
+			f, ok := number(v)
+			if !ok {
+				return nil, fmt.Errorf("диапазон: %s должно быть числом", key)
+			}
+			nums[key] = f
+		case "log":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("диапазон: log должно быть логическим значением")
+			}
+			logScale = b
+		default:
+			return nil, fmt.Errorf("диапазон: неизвестный ключ %q (ожидается from, to, step, count, log)", key)
+		}
+	}
+	from, okFrom := nums["from"]
+	to, okTo := nums["to"]
+	step, okStep := nums["step"]
+	count, okCount := nums["count"]
+	if !okFrom || !okTo || okStep == okCount {
+		return nil, fmt.Errorf("диапазон задаётся ключами from, to и одним из step или count")
+	}
+	if from > to {
+		return nil, fmt.Errorf("диапазон: from = %g больше to = %g", from, to)
+	}
+
+	var values []string
+	if okStep {
+		if step <= 0 || logScale {
+			return nil, fmt.Errorf("диапазон: step должен быть больше 0 и не сочетается с log")
+		}
+		n := int(math.Floor((to-from)/step+1e-9)) + 1
+		if n > maxPoints {
+			return nil, fmt.Errorf("диапазон: слишком много значений (%d)", n)
+		}
+		for i := 0; i < n; i++ {
+			values = append(values, formatFloat(from+float64(i)*step))
+		}
+		return values, nil
+	}
+	if count < 1 || count != math.Trunc(count) || count > maxPoints {
+		return nil, fmt.Errorf("диапазон: count должно быть целым числом от 1 до %d", maxPoints)
+	}
+	if logScale && from <= 0 {
+		return nil, fmt.Errorf("диапазон: при log = true значение from должно быть больше 0")
+	}
+	n := int(count)
+	for i := 0; i < n; i++ {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		v := from + t*(to-from)
+		if logScale {
+			v = from * math.Pow(to/from, t)
+		}
+		values = append(values, formatFloat(v))
+	}
+	return values, nil
+}
+
+// Points возвращает все точки серии — декартово произведение значений
+// параметров; последний параметр меняется быстрее всего
+func (c *Config) Points() []Point {
+	points := []Point{nil}
+	for _, p := range c.Params {
+		next := make([]Point, 0, len(points)*len(p.Values))
+		for _, point := range points {
+			for _, v := range p.Values {
+				next = append(next, append(append(Point(nil), point...), Assignment{Name: p.Name, Value: v}))
+			}
+		}
+		points = next
+	}
+	return points
+}
+
+// Args возвращает аргументы командной строки itc для точки p
+func (c *Config) Args(p Point) []string {
+	args := append([]string(nil), c.Command...)
+	for _, a := range c.Fixed {
+		args = append(args, "--"+a.Name+"="+a.Value)
+	}
+	for _, a := range p {
+		args = append(args, "--"+a.Name+"="+a.Value)
+	}
+	return args
+}
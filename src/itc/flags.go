@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"itc/infotheory"
+	"itc/report"
+)
+
+// intList — значение флага вида "8,9,10"
+type intList []int
+
+func (l *intList) String() string {
+	strs := make([]string, len(*l))
+	for i, v := range *l {
+		strs[i] = strconv.Itoa(v)
+	}
+	return strings.Join(strs, ",")
+}
+
+func (l *intList) Set(s string) error {
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return fmt.Errorf("ожидается список целых чисел через запятую: %q", part)
+		}
+		values = append(values, v)
+	}
+	*l = values
+	return nil
+}
+
+// sourceFlag — значение флага --source с законом распределения источника
+type sourceFlag struct {
+	spec infotheory.SourceSpec
+}
+
+func (f *sourceFlag) String() string { return f.spec.String() }
+
+func (f *sourceFlag) Set(s string) error {
+	spec, err := infotheory.ParseSourceSpec(s)
+	if err != nil {
+		return err
+	}
+	f.spec = spec
+	return nil
+}
+
+// unitFlag — значение флага --base с единицей измерения информации
+type unitFlag struct {
+	unit infotheory.Unit
+}
+
+func (f *unitFlag) String() string { return f.unit.String() }
+
+func (f *unitFlag) Set(s string) error {
+	unit, err := infotheory.ParseUnit(s)
+	if err != nil {
+		return err
+	}
+	f.unit = unit
+	return nil
+}
+
+// addSourceFlag добавляет флаг --source
+func addSourceFlag(fs *flag.FlagSet) *sourceFlag {
+	f := &sourceFlag{spec: infotheory.DefaultSource}
+	fs.Var(f, "source", "закон распределения источника: "+joinNames(infotheory.SourceKinds))
+	return f
+}
+
+// addUnitFlag добавляет флаг --base
+func addUnitFlag(fs *flag.FlagSet, what string) *unitFlag {
+	f := &unitFlag{unit: infotheory.Bits}
+	fs.Var(f, "base", "единица измерения "+what+": bits, nats или hartleys")
+	return f
+}
+
+// addSeedFlag добавляет флаг --seed; функция-результат создаёт генератор
+// случайных чисел после разбора флагов
+func addSeedFlag(fs *flag.FlagSet) func() *rand.Rand {
+	seed := fs.Int64("seed", 0, "начальное значение генератора случайных чисел (0 — по текущему времени)")
+	return func() *rand.Rand {
+		if *seed == 0 {
+			return rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		return rand.New(rand.NewSource(*seed))
+	}
+}
+
+// capture перехватывает результаты подкоманды, запущенной командой report:
+// таблицы сохраняются в recorder вместо стандартного вывода, а разобранные
+// флаги становятся параметрами отчёта
+type capture struct {
+	recorder report.Recorder
+	flags    *flag.FlagSet
+}
+
+// activeCapture — перехват текущего запуска или nil при обычном выводе
+var activeCapture *capture
+
+// addFormatFlag добавляет флаги формата вывода и вида таблиц; функция-результат
+// создаёт Writer для стандартного вывода после разбора флагов
+func addFormatFlag(fs *flag.FlagSet) func() (report.Writer, error) {
+	format := fs.String("format", string(report.FormatText), "формат вывода: "+joinNames(report.Formats))
+	precision := fs.Int("precision", -1, "знаков после запятой у вещественных чисел (-1 — как задано в таблице)")
+	widths := fs.String("widths", "", "ширины столбцов по ключам, например entropy=12,n=4")
+	headers := fs.String("headers", "", "заголовки столбцов по ключам, например entropy=H(X)")
+	return func() (report.Writer, error) {
+		f, err := report.ParseFormat(*format)
+		if err != nil {
+			return nil, fmt.Errorf("--format: %w", err)
+		}
+		opts := report.DefaultOptions
+		opts.Precision = *precision
+		if opts.Widths, err = report.ParseWidths(*widths); err != nil {
+			return nil, fmt.Errorf("--widths: %w", err)
+		}
+		if opts.Headers, err = report.ParseAssignments(*headers); err != nil {
+			return nil, fmt.Errorf("--headers: %w", err)
+		}
+		if activeCapture != nil {
+			return report.WithOptions(&activeCapture.recorder, opts), nil
+		}
+		return report.NewWriterWithOptions(os.Stdout, f, opts)
+	}
+}
+
+// parseFlags разбирает флаги и запрещает лишние позиционные аргументы
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if activeCapture != nil {
+		activeCapture.flags = fs
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("лишние аргументы: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// checkPositive проверяет, что параметр флага больше нуля
+func checkPositive(name string, values ...int) error {
+	for _, v := range values {
+		if v <= 0 {
+			return fmt.Errorf("--%s: значение должно быть больше 0, получено %d", name, v)
+		}
+	}
+	return nil
+}
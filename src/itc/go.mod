@@ -0,0 +1,22 @@
+module itc
+
+go 1.25
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/olekukonko/tablewriter v1.1.0
+	golang.org/x/image v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/fatih/color v1.15.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/olekukonko/errors v1.1.0 // indirect
+	github.com/olekukonko/ll v0.0.9 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+	golang.org/x/text v0.19.0 // indirect
+)
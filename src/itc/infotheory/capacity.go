@@ -0,0 +1,75 @@
+package infotheory
+
+import (
+	"fmt"
+	"math"
+)
+
+// Capacity — пропускная способность канала и достигающее её распределение на входе
+type Capacity struct {
+	Value      float64      // C = max I(X; Y) в выбранных единицах
+	Input      Distribution // оптимальное распределение входных символов
+	Iterations int          // число выполненных итераций
+}
+
+// ChannelCapacity вычисляет пропускную способность дискретного канала без
+// памяти алгоритмом Блейхута — Аримото. Итерации прекращаются, когда разность
+// верхней и нижней оценок C становится меньше tolerance (в натах); при
+// tolerance <= 0 используется 1e-9. Если за 100000 итераций точность не
+// достигнута, возвращается ошибка.
+func ChannelCapacity(channel StochasticMatrix, unit Unit, tolerance float64) (Capacity, error) {
+	if channel.Rows() == 0 {
+		return Capacity{}, validationError("матрица канала", -1, -1, ErrEmpty, "")
+	}
+	if tolerance <= 0 {
+		tolerance = 1e-9
+	}
+	const maxIterations = 100000
+
+	n := channel.Rows()
+	p := make([]float64, n)
+	for i := range p {
+		p[i] = 1 / float64(n)
+	}
+	d := make([]float64, n)
+
+	lower, gap, iterations := 0.0, math.Inf(1), 0
+	for iterations < maxIterations {
+		iterations++
+		output, _ := OutputDistribution(Distribution{probs: p}, channel)
+
+		// d_i = D(p(y | x_i) || p(y)) в натах
+		maxD := math.Inf(-1)
+		for i := range p {
+			d[i] = 0
+			for j := 0; j < channel.Cols(); j++ {
+				if w := channel.At(i, j); w > 0 {
+					d[i] += w * math.Log(w/output.At(j))
+				}
+			}
+			maxD = math.Max(maxD, d[i])
+		}
+
+		// Нижняя оценка log Σ p_i e^{d_i}, верхняя — max d_i
+		sum := 0.0
+		for i := range p {
+			sum += p[i] * math.Exp(d[i]-maxD)
+		}
+		lower = maxD + math.Log(sum)
+		if gap = maxD - lower; gap < tolerance {
+			break
+		}
+		for i := range p {
+			p[i] *= math.Exp(d[i]-maxD) / sum
+		}
+	}
+	if gap >= tolerance {
+		return Capacity{}, fmt.Errorf("пропускная способность не найдена за %d итераций: разность оценок %g нат, требуется меньше %g",
+			maxIterations, gap, tolerance)
+	}
+	return Capacity{
+		Value:      unit.fromNats(math.Max(0, lower)),
+		Input:      Distribution{probs: p},
+		Iterations: iterations,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package infotheory
+
+import "math"
+
+// SymmetricChannel строит матрицу дискретного канала с n = len(probsRight)
+// символами: символ x_i передаётся верно с вероятностью probsRight[i], а
+// ошибочные символы равновероятны
+func SymmetricChannel(probsRight []float64) (StochasticMatrix, error) {
+	n := len(probsRight)
+	if n == 0 {
+		return StochasticMatrix{}, validationError("вероятности безошибочной передачи", -1, -1, ErrEmpty, "")
+	}
+	rows := make([][]float64, n)
+	for i, p := range probsRight {
+		if math.IsNaN(p) || p < 0 || p > 1 {
+			return StochasticMatrix{}, validationError("вероятности безошибочной передачи", i, -1, ErrOutOfRange,
+				"%f вне диапазона [0,1]", p)
+		}
+		if n == 1 && p != 1 {
+			return StochasticMatrix{}, validationError("вероятности безошибочной передачи", i, -1, ErrOutOfRange,
+				"канал из одного символа всегда передаёт его верно")
+		}
+		rows[i] = make([]float64, n)
+		for j := range rows[i] {
+			if j == i {
+				rows[i][j] = p
+			} else {
+				rows[i][j] = (1 - p) / float64(n-1)
+			}
+		}
+	}
+	return NewStochasticMatrix(rows)
+}
+
+// checkChannelInput проверяет, что размер алфавита источника совпадает
+// с числом входных символов канала
+func checkChannelInput(input Distribution, channel StochasticMatrix) error {
+	if input.Len() != channel.Rows() {
+		return DimensionError("число входных символов канала", input.Len(), channel.Rows())
+	}
+	return nil
+}
+
+// OutputDistribution вычисляет вероятности выходных символов
+// p(y_j) = Σ p(x_i) p(y_j | x_i)
+func OutputDistribution(input Distribution, channel StochasticMatrix) (Distribution, error) {
+	if err := checkChannelInput(input, channel); err != nil {
+		return Distribution{}, err
+	}
+	out := make([]float64, channel.Cols())
+	for i := 0; i < channel.Rows(); i++ {
+		for j := range out {
+			out[j] += input.At(i) * channel.At(i, j)
+		}
+	}
+	return Distribution{probs: out}, nil
+}
+
+// JointDistribution вычисляет совместные вероятности p(x_i, y_j) = p(x_i) p(y_j | x_i)
+func JointDistribution(input Distribution, channel StochasticMatrix) ([][]float64, error) {
+	if err := checkChannelInput(input, channel); err != nil {
+		return nil, err
+	}
+	joint := make([][]float64, channel.Rows())
+	for i := range joint {
+		joint[i] = make([]float64, channel.Cols())
+		for j := range joint[i] {
+			joint[i][j] = input.At(i) * channel.At(i, j)
+		}
+	}
+	return joint, nil
+}
+
+// Equivocation вычисляет ненадёжность канала H(X | Y) — неопределённость
+// переданного символа, остающуюся после приёма
+func Equivocation(input Distribution, channel StochasticMatrix, unit Unit) (float64, error) {
+	joint, err := JointDistribution(input, channel)
+	if err != nil {
+		return 0, err
+	}
+	output, err := OutputDistribution(input, channel)
+	if err != nil {
+		return 0, err
+	}
+	h := 0.0
+	for i := range joint {
+		for j, pxy := range joint[i] {
+			if pxy > 0 {
+				h -= pxy * math.Log(pxy/output.At(j))
+			}
+		}
+	}
+	return unit.fromNats(h), nil
+}
+
+// MutualInformation вычисляет количество информации I(X; Y) = H(X) - H(X | Y)
+func MutualInformation(input Distribution, channel StochasticMatrix, unit Unit) (float64, error) {
+	equivocation, err := Equivocation(input, channel, unit)
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(0, input.Entropy(unit)-equivocation), nil
+}
@@ -0,0 +1,94 @@
+package infotheory
+
+// Distribution — проверенное распределение вероятностей на конечном алфавите.
+// Создаётся только функцией NewDistribution, поэтому все его значения
+// неотрицательны, а их сумма равна 1 с точностью probTolerance.
+type Distribution struct {
+	probs []float64
+}
+
+// NewDistribution проверяет вероятности и создаёт распределение
+func NewDistribution(probs []float64) (Distribution, error) {
+	if err := checkDistribution(probs); err != nil {
+		return Distribution{}, err
+	}
+	return Distribution{probs: append([]float64(nil), probs...)}, nil
+}
+
+// Len возвращает размер алфавита
+func (d Distribution) Len() int {
+	return len(d.probs)
+}
+
+// At возвращает вероятность i-го символа
+func (d Distribution) At(i int) float64 {
+	return d.probs[i]
+}
+
+// Probs возвращает копию вероятностей
+func (d Distribution) Probs() []float64 {
+	return append([]float64(nil), d.probs...)
+}
+
+// Entropy вычисляет энтропию распределения в единицах unit
+func (d Distribution) Entropy(unit Unit) float64 {
+	return unit.fromNats(shannonNats(d.probs))
+}
+
+// StochasticMatrix — проверенная стохастическая по строкам матрица:
+// строка i задаёт распределение p(y | x_i). Используется для матриц
+// переходных вероятностей каналов и марковских цепей.
+type StochasticMatrix struct {
+	rows [][]float64
+	cols int
+}
+
+// NewStochasticMatrix проверяет, что все строки имеют одинаковую длину,
+// а каждая строка — распределение вероятностей
+func NewStochasticMatrix(rows [][]float64) (StochasticMatrix, error) {
+	if len(rows) == 0 {
+		return StochasticMatrix{}, validationError("стохастическая матрица", -1, -1, ErrEmpty, "")
+	}
+	cols := len(rows[0])
+	m := StochasticMatrix{rows: make([][]float64, len(rows)), cols: cols}
+	for i, row := range rows {
+		if len(row) != cols {
+			return StochasticMatrix{}, validationError("стохастическая матрица", i, -1, ErrDimension,
+				"ожидалось %d элементов, получено %d", cols, len(row))
+		}
+		if err := checkProbabilities("стохастическая матрица", row, i); err != nil {
+			return StochasticMatrix{}, err
+		}
+		m.rows[i] = append([]float64(nil), row...)
+	}
+	return m, nil
+}
+
+// Rows возвращает число строк (входных символов)
+func (m StochasticMatrix) Rows() int {
+	return len(m.rows)
+}
+
+// Cols возвращает число столбцов (выходных символов)
+func (m StochasticMatrix) Cols() int {
+	return m.cols
+}
+
+// At возвращает элемент (i, j)
+func (m StochasticMatrix) At(i, j int) float64 {
+	return m.rows[i][j]
+}
+
+// Row возвращает строку i как распределение
+func (m StochasticMatrix) Row(i int) Distribution {
+	return Distribution{probs: m.rows[i]}
+}
+
+// Matrix возвращает копию элементов матрицы
+func (m StochasticMatrix) Matrix() [][]float64 {
+	res := make([][]float64, len(m.rows))
+	for i, row := range m.rows {
+		res[i] = append([]float64(nil), row...)
+	}
+	return res
+}
@@ -0,0 +1,279 @@
+package infotheory
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// SourceSpec описывает закон распределения вероятностей источника сообщений.
+// Строковая запись имеет вид "вид[:параметр]", например "dirichlet:0.5",
+// "zipf:1.2" или "custom:0.5,0.25,0.25".
+type SourceSpec struct {
+	Kind   string    // вид распределения
+	Param  float64   // параметр распределения (α, s или p)
+	Values []float64 // вероятности для вида custom
+}
+
+// DefaultSource — исходный способ лабораторных работ: равномерные случайные
+// числа, нормированные к единице
+var DefaultSource = SourceSpec{Kind: "random"}
+
+// SourceKinds перечисляет поддерживаемые виды распределений
+var SourceKinds = []string{"random", "uniform", "degenerate", "dirichlet:α", "zipf:s", "geometric:p", "binomial:p", "custom:p1,p2,..."}
+
+// ParseSourceSpec разбирает строковую запись закона распределения
+func ParseSourceSpec(text string) (SourceSpec, error) {
+	kind, arg, hasArg := strings.Cut(strings.TrimSpace(text), ":")
+	spec := SourceSpec{Kind: strings.ToLower(kind)}
+
+	switch spec.Kind {
+	case "random", "uniform", "degenerate":
+		if hasArg {
+			return SourceSpec{}, fmt.Errorf("распределение %s не имеет параметров", spec.Kind)
+		}
+		return spec, nil
+	case "custom":
+		if !hasArg {
+			return SourceSpec{}, fmt.Errorf("для распределения custom нужно указать вероятности")
+		}
+		for _, field := range strings.Split(arg, ",") {
+			v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return SourceSpec{}, fmt.Errorf("некорректная вероятность %q: %w", field, err)
+			}
+			spec.Values = append(spec.Values, v)
+		}
+		if err := checkDistribution(spec.Values); err != nil {
+			return SourceSpec{}, err
+		}
+		return spec, nil
+	case "dirichlet", "zipf", "geometric", "binomial":
+		if !hasArg {
+			return SourceSpec{}, fmt.Errorf("для распределения %s нужно указать параметр", spec.Kind)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil {
+			return SourceSpec{}, fmt.Errorf("некорректный параметр %q: %w", arg, err)
+		}
+		spec.Param = v
+		if err := spec.checkParam(); err != nil {
+			return SourceSpec{}, err
+		}
+		return spec, nil
+	}
+	return SourceSpec{}, fmt.Errorf("неизвестное распределение %q (ожидается одно из: %s)",
+		kind, strings.Join(SourceKinds, ", "))
+}
+
+// checkParam проверяет допустимость параметра распределения
+func (s SourceSpec) checkParam() error {
+	switch s.Kind {
+	case "dirichlet":
+		if !(s.Param > 0) || math.IsInf(s.Param, 0) {
+			return validationError("параметр α распределения Дирихле", -1, -1, ErrOutOfRange, "должен быть положительным, получено %g", s.Param)
+		}
+	case "zipf":
+		if !(s.Param >= 0) || math.IsInf(s.Param, 0) {
+			return validationError("показатель s закона Ципфа", -1, -1, ErrOutOfRange, "должен быть неотрицательным, получено %g", s.Param)
+		}
+	case "geometric":
+		if !(s.Param > 0 && s.Param <= 1) {
+			return validationError("параметр p геометрического распределения", -1, -1, ErrOutOfRange, "должен лежать в (0, 1], получено %g", s.Param)
+		}
+	case "binomial":
+		if !(s.Param >= 0 && s.Param <= 1) {
+			return validationError("параметр p биномиального распределения", -1, -1, ErrOutOfRange, "должен лежать в [0, 1], получено %g", s.Param)
+		}
+	}
+	return nil
+}
+
+// String возвращает строковую запись закона распределения
+func (s SourceSpec) String() string {
+	switch s.Kind {
+	case "dirichlet", "zipf", "geometric", "binomial":
+		return s.Kind + ":" + strconv.FormatFloat(s.Param, 'g', -1, 64)
+	case "custom":
+		strs := make([]string, len(s.Values))
+		for i, v := range s.Values {
+			strs[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return "custom:" + strings.Join(strs, ",")
+	}
+	return s.Kind
+}
+
+// Generate возвращает вероятности n сообщений источника. Для случайных
+// законов (random, dirichlet) используется генератор rng.
+func (s SourceSpec) Generate(n int, rng *rand.Rand) ([]float64, error) {
+	if n <= 0 {
+		return nil, validationError("число вероятностей", -1, -1, ErrOutOfRange, "должно быть больше 0, получено %d", n)
+	}
+	if err := s.checkParam(); err != nil {
+		return nil, err
+	}
+	switch s.Kind {
+	case "random":
+		return RandomDistribution(n, rng), nil
+	case "uniform":
+		return UniformDistribution(n), nil
+	case "degenerate":
+		return DegenerateDistribution(n), nil
+	case "dirichlet":
+		return DirichletDistribution(n, s.Param, rng), nil
+	case "zipf":
+		return ZipfDistribution(n, s.Param), nil
+	case "geometric":
+		return GeometricDistribution(n, s.Param), nil
+	case "binomial":
+		return BinomialDistribution(n, s.Param), nil
+	case "custom":
+		if len(s.Values) != n {
+			return nil, DimensionError("число заданных вероятностей", n, len(s.Values))
+		}
+		if err := checkDistribution(s.Values); err != nil {
+			return nil, err
+		}
+		return append([]float64(nil), s.Values...), nil
+	}
+	return nil, fmt.Errorf("неизвестное распределение %q", s.Kind)
+}
+
+// RandomDistribution генерирует равномерные случайные числа и нормирует их
+func RandomDistribution(n int, rng *rand.Rand) []float64 {
+	probs := make([]float64, n)
+	sum := 0.0
+	for i := range probs {
+		probs[i] = rng.Float64()
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs
+}
+
+// UniformDistribution возвращает равновероятный источник (H = log2 n)
+func UniformDistribution(n int) []float64 {
+	probs := make([]float64, n)
+	for i := range probs {
+		probs[i] = 1 / float64(n)
+	}
+	return probs
+}
+
+// DegenerateDistribution возвращает вырожденный источник, всегда выдающий
+// первое сообщение (H = 0)
+func DegenerateDistribution(n int) []float64 {
+	probs := make([]float64, n)
+	probs[0] = 1
+	return probs
+}
+
+// DirichletDistribution генерирует случайное распределение с законом
+// Дирихле(α, ..., α). Малые α дают сильно перекошенные распределения
+// (энтропия близка к 0), большие — почти равномерные (энтропия близка к log2 n).
+func DirichletDistribution(n int, alpha float64, rng *rand.Rand) []float64 {
+	// Вычисления ведутся в логарифмах: при α << 1 сами гамма-величины
+	// могут оказаться меньше наименьшего представимого числа
+	logs := make([]float64, n)
+	maxLog := math.Inf(-1)
+	for i := range logs {
+		logs[i] = logGammaSample(alpha, rng)
+		maxLog = math.Max(maxLog, logs[i])
+	}
+	return normalizeLogs(logs, maxLog)
+}
+
+// ZipfDistribution возвращает закон Ципфа p(i) ∝ 1 / i^s, i = 1..n
+func ZipfDistribution(n int, s float64) []float64 {
+	logs := make([]float64, n)
+	for i := range logs {
+		logs[i] = -s * math.Log(float64(i+1))
+	}
+	return normalizeLogs(logs, 0)
+}
+
+// GeometricDistribution возвращает усечённое геометрическое распределение
+// p(i) ∝ (1 - p)^i, i = 0..n-1
+func GeometricDistribution(n int, p float64) []float64 {
+	if p == 1 {
+		return DegenerateDistribution(n)
+	}
+	logs := make([]float64, n)
+	for i := range logs {
+		logs[i] = float64(i) * math.Log1p(-p)
+	}
+	return normalizeLogs(logs, 0)
+}
+
+// BinomialDistribution возвращает биномиальное распределение B(n-1, p)
+// на сообщениях 0..n-1
+func BinomialDistribution(n int, p float64) []float64 {
+	trials := n - 1
+	probs := make([]float64, n)
+	switch p {
+	case 0:
+		probs[0] = 1
+		return probs
+	case 1:
+		probs[trials] = 1
+		return probs
+	}
+	logs := make([]float64, n)
+	maxLog := math.Inf(-1)
+	for i := range logs {
+		logs[i] = lgamma(float64(trials+1)) - lgamma(float64(i+1)) - lgamma(float64(trials-i+1)) +
+			float64(i)*math.Log(p) + float64(trials-i)*math.Log1p(-p)
+		maxLog = math.Max(maxLog, logs[i])
+	}
+	return normalizeLogs(logs, maxLog)
+}
+
+// normalizeLogs переводит логарифмы ненормированных весов в вероятности;
+// shift вычитается из логарифмов для устойчивости вычислений
+func normalizeLogs(logs []float64, shift float64) []float64 {
+	probs := make([]float64, len(logs))
+	sum := 0.0
+	for i, l := range logs {
+		probs[i] = math.Exp(l - shift)
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs
+}
+
+// logGammaSample возвращает логарифм случайной величины с гамма-распределением
+// Gamma(shape, 1). Используется метод Марсальи–Цанга; для shape < 1 —
+// соотношение Gamma(a) = Gamma(a + 1) · U^(1/a).
+func logGammaSample(shape float64, rng *rand.Rand) float64 {
+	if shape < 1 {
+		u := rng.Float64()
+		for u == 0 {
+			u = rng.Float64()
+		}
+		return logGammaSample(shape+1, rng) + math.Log(u)/shape
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if u == 0 {
+			continue
+		}
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return math.Log(d * v)
+		}
+	}
+}
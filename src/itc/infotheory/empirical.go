@@ -0,0 +1,198 @@
+package infotheory
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenUnit определяет, на какие символы разбивается текст при оценке энтропии
+type TokenUnit int
+
+const (
+	UnitBytes TokenUnit = iota // отдельные байты
+	UnitRunes                  // символы UTF-8 (в том числе кириллица)
+	UnitWords                  // слова, приведённые к нижнему регистру
+)
+
+// String возвращает название единицы разбиения
+func (u TokenUnit) String() string {
+	switch u {
+	case UnitBytes:
+		return "bytes"
+	case UnitRunes:
+		return "runes"
+	case UnitWords:
+		return "words"
+	}
+	return fmt.Sprintf("TokenUnit(%d)", int(u))
+}
+
+// ParseTokenUnit преобразует название единицы разбиения в TokenUnit
+func ParseTokenUnit(name string) (TokenUnit, error) {
+	switch strings.ToLower(name) {
+	case "bytes", "byte":
+		return UnitBytes, nil
+	case "runes", "rune", "chars":
+		return UnitRunes, nil
+	case "words", "word":
+		return UnitWords, nil
+	}
+	return 0, fmt.Errorf("неизвестная единица разбиения %q (ожидается bytes, runes или words)", name)
+}
+
+// OrderEntropy — эмпирическая энтропия порядка Order
+type OrderEntropy struct {
+	Order        int     // число предшествующих символов в условии
+	BlockEntropy float64 // H(X1..X(k+1)) — энтропия блоков длины k+1
+	Conditional  float64 // H(X(k+1) | X1..Xk) — условная энтропия
+	Redundancy   float64 // 1 - Conditional / MaxEntropy
+	Blocks       int     // число различных блоков длины k+1
+}
+
+// EmpiricalAnalysis — результат оценки энтропии реального текста или файла
+type EmpiricalAnalysis struct {
+	Unit       TokenUnit
+	Tokens     int // общее число символов
+	Alphabet   int // размер алфавита, относительно которого считается избыточность
+	Observed   int // число различных символов, встретившихся в тексте
+	MaxEntropy float64
+	Orders     []OrderEntropy
+}
+
+// Tokenize разбивает данные на символы выбранного вида. Каждый различный
+// символ получает свой номер, поэтому результат — последовательность номеров
+// и число различных символов.
+func Tokenize(data []byte, unit TokenUnit) ([]int, int, error) {
+	ids := make(map[string]int)
+	var seq []int
+	add := func(token string) {
+		id, ok := ids[token]
+		if !ok {
+			id = len(ids)
+			ids[token] = id
+		}
+		seq = append(seq, id)
+	}
+
+	switch unit {
+	case UnitBytes:
+		seq = make([]int, 0, len(data))
+		for _, b := range data {
+			add(string([]byte{b}))
+		}
+	case UnitRunes:
+		for offset := 0; offset < len(data); {
+			r, size := utf8.DecodeRune(data[offset:])
+			if r == utf8.RuneError && size <= 1 {
+				return nil, 0, fmt.Errorf("некорректная последовательность UTF-8 в позиции %d", offset)
+			}
+			add(string(r))
+			offset += size
+		}
+	case UnitWords:
+		if !utf8.Valid(data) {
+			return nil, 0, fmt.Errorf("текст содержит некорректные последовательности UTF-8")
+		}
+		words := strings.FieldsFunc(string(data), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '\''
+		})
+		for _, w := range words {
+			add(strings.ToLower(w))
+		}
+	default:
+		return nil, 0, fmt.Errorf("неизвестная единица разбиения %v", unit)
+	}
+	return seq, len(ids), nil
+}
+
+// blockEntropy вычисляет энтропию блоков длины length в битах и число различных блоков
+func blockEntropy(seq []int, length int) (float64, int) {
+	total := len(seq) - length + 1
+	if length == 0 || total <= 0 {
+		return 0, 0
+	}
+	counts := make(map[string]int)
+	key := make([]byte, 0, length*binary.MaxVarintLen64)
+	for i := 0; i < total; i++ {
+		key = key[:0]
+		for _, id := range seq[i : i+length] {
+			key = binary.AppendUvarint(key, uint64(id))
+		}
+		counts[string(key)]++
+	}
+	probs := make([]float64, 0, len(counts))
+	for _, c := range counts {
+		probs = append(probs, float64(c)/float64(total))
+	}
+	return Entropy(probs), len(counts)
+}
+
+// AnalyzeSequence вычисляет эмпирические энтропии порядков 0..maxOrder.
+// Условная энтропия порядка k оценивается как разность блочных энтропий
+// H(X1..X(k+1)) - H(X1..Xk). Избыточность считается относительно
+// MaxEntropy(alphabet); если alphabet <= 0, берётся число различных символов.
+func AnalyzeSequence(seq []int, observed, alphabet, maxOrder int) (EmpiricalAnalysis, error) {
+	if len(seq) == 0 {
+		return EmpiricalAnalysis{}, validationError("последовательность символов", -1, -1, ErrEmpty, "")
+	}
+	if maxOrder < 0 {
+		return EmpiricalAnalysis{}, validationError("порядок энтропии", -1, -1, ErrOutOfRange, "не может быть отрицательным: %d", maxOrder)
+	}
+	if alphabet <= 0 {
+		alphabet = observed
+	}
+	if alphabet < observed {
+		return EmpiricalAnalysis{}, validationError("размер алфавита", -1, -1, ErrOutOfRange, "%d меньше числа различных символов %d", alphabet, observed)
+	}
+
+	res := EmpiricalAnalysis{
+		Tokens:     len(seq),
+		Alphabet:   alphabet,
+		Observed:   observed,
+		MaxEntropy: MaxEntropy(alphabet),
+	}
+	prev := 0.0
+	for k := 0; k <= maxOrder && k < len(seq); k++ {
+		block, blocks := blockEntropy(seq, k+1)
+		order := OrderEntropy{
+			Order:        k,
+			BlockEntropy: block,
+			Conditional:  block - prev,
+			Blocks:       blocks,
+		}
+		// Из-за разного числа блоков разной длины разность может стать
+		// чуть отрицательной на коротких текстах
+		if order.Conditional < 0 {
+			order.Conditional = 0
+		}
+		if res.MaxEntropy > 0 {
+			order.Redundancy = 1 - order.Conditional/res.MaxEntropy
+		}
+		res.Orders = append(res.Orders, order)
+		prev = block
+	}
+	return res, nil
+}
+
+// AnalyzeReader читает все данные из r и оценивает энтропии порядков 0..maxOrder
+func AnalyzeReader(r io.Reader, unit TokenUnit, alphabet, maxOrder int) (EmpiricalAnalysis, error) {
+	data, err := io.ReadAll(bufio.NewReader(r))
+	if err != nil {
+		return EmpiricalAnalysis{}, fmt.Errorf("ошибка чтения данных: %w", err)
+	}
+	seq, observed, err := Tokenize(data, unit)
+	if err != nil {
+		return EmpiricalAnalysis{}, err
+	}
+	res, err := AnalyzeSequence(seq, observed, alphabet, maxOrder)
+	if err != nil {
+		return EmpiricalAnalysis{}, err
+	}
+	res.Unit = unit
+	return res, nil
+}
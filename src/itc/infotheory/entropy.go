@@ -0,0 +1,43 @@
+// Package infotheory содержит общие для лабораторных работ функции теории
+// информации: энтропию, модели источников сообщений и их характеристики.
+package infotheory
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Entropy вычисляет энтропию Шеннона распределения probs в битах
+func Entropy(probs []float64) float64 {
+	var h float64
+	for _, p := range probs {
+		if p > 0 {
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// MaxEntropy возвращает максимальную энтропию источника из n символов (log2 n)
+func MaxEntropy(n int) float64 {
+	return math.Log2(float64(n))
+}
+
+// sampleIndex выбирает индекс i с вероятностью probs[i]
+func sampleIndex(probs []float64, rng *rand.Rand) int {
+	u := rng.Float64()
+	acc := 0.0
+	for i, p := range probs {
+		acc += p
+		if u < acc {
+			return i
+		}
+	}
+	// Из-за погрешности округления сумма может быть чуть меньше 1
+	for i := len(probs) - 1; i >= 0; i-- {
+		if probs[i] > 0 {
+			return i
+		}
+	}
+	return len(probs) - 1
+}
@@ -0,0 +1,338 @@
+package infotheory
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// EstimatorFunc оценивает энтропию (в битах) по частотам символов в выборке
+type EstimatorFunc func(counts []int) (float64, error)
+
+// Interval — оценка энтропии с доверительным интервалом
+type Interval struct {
+	Estimate float64 // оценка по исходной выборке
+	Lower    float64 // нижняя граница интервала
+	Upper    float64 // верхняя граница интервала
+	StdErr   float64 // стандартное отклонение бутстрэп-оценок
+	Level    float64 // доверительная вероятность
+}
+
+// HalfWidth возвращает половину ширины интервала (для записи H ± Δ)
+func (iv Interval) HalfWidth() float64 {
+	return (iv.Upper - iv.Lower) / 2
+}
+
+// Sample генерирует выборку из size символов с распределением probs
+func Sample(probs []float64, size int, rng *rand.Rand) []int {
+	samples := make([]int, size)
+	for i := range samples {
+		samples[i] = sampleIndex(probs, rng)
+	}
+	return samples
+}
+
+// Counts подсчитывает частоты символов 0..alphabet-1 в выборке
+func Counts(samples []int, alphabet int) ([]int, error) {
+	counts := make([]int, alphabet)
+	for i, s := range samples {
+		if s < 0 || s >= alphabet {
+			return nil, validationError("выборка", i, -1, ErrOutOfRange, "символ %d вне алфавита из %d символов", s, alphabet)
+		}
+		counts[s]++
+	}
+	return counts, nil
+}
+
+// sampleSize проверяет частоты и возвращает объём выборки
+func sampleSize(counts []int) (int, error) {
+	total := 0
+	for i, c := range counts {
+		if c < 0 {
+			return 0, validationError("частоты", i, -1, ErrNegative, "%d", c)
+		}
+		total += c
+	}
+	if total == 0 {
+		return 0, validationError("выборка", -1, -1, ErrEmpty, "")
+	}
+	return total, nil
+}
+
+// pluginNats — оценка «подстановкой» частот вместо вероятностей в натах
+func pluginNats(counts []int, total int) float64 {
+	h := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(total)
+			h -= p * math.Log(p)
+		}
+	}
+	return h
+}
+
+// PluginEntropy — наивная оценка: энтропия эмпирического распределения.
+// Систематически занижает энтропию на малых выборках.
+func PluginEntropy(counts []int) (float64, error) {
+	total, err := sampleSize(counts)
+	if err != nil {
+		return 0, err
+	}
+	return pluginNats(counts, total) / math.Ln2, nil
+}
+
+// MillerMadowEntropy добавляет к наивной оценке поправку Миллера–Мэдоу
+// (m - 1) / (2N), где m — число встретившихся символов
+func MillerMadowEntropy(counts []int) (float64, error) {
+	total, err := sampleSize(counts)
+	if err != nil {
+		return 0, err
+	}
+	observed := 0
+	for _, c := range counts {
+		if c > 0 {
+			observed++
+		}
+	}
+	h := pluginNats(counts, total) + float64(observed-1)/(2*float64(total))
+	return h / math.Ln2, nil
+}
+
+// ChaoShenEntropy — оценка Чао–Шэня: вероятности сжимаются на оценку
+// покрытия выборки Гуда–Тьюринга, а слагаемые взвешиваются по Хорвицу–Томпсону
+func ChaoShenEntropy(counts []int) (float64, error) {
+	total, err := sampleSize(counts)
+	if err != nil {
+		return 0, err
+	}
+	singletons := 0
+	for _, c := range counts {
+		if c == 1 {
+			singletons++
+		}
+	}
+	if singletons == total {
+		singletons = total - 1
+	}
+	coverage := 1 - float64(singletons)/float64(total)
+
+	h := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		pa := coverage * float64(c) / float64(total)
+		h -= pa * math.Log(pa) / (1 - math.Pow(1-pa, float64(total)))
+	}
+	return h / math.Ln2, nil
+}
+
+// GrassbergerEntropy — оценка Грассбергера (2003):
+// H = ln N - (1/N) Σ n_i G(n_i), G(n) = ψ(n) + (-1)^n (ψ((n+1)/2) - ψ(n/2)) / 2
+func GrassbergerEntropy(counts []int) (float64, error) {
+	total, err := sampleSize(counts)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		n := float64(c)
+		sign := 1.0
+		if c%2 == 1 {
+			sign = -1
+		}
+		g := digamma(n) + sign*(digamma((n+1)/2)-digamma(n/2))/2
+		sum += n * g
+	}
+	h := math.Log(float64(total)) - sum/float64(total)
+	return h / math.Ln2, nil
+}
+
+// NSBEntropy возвращает оценку Немана–Шафи–Биалека для алфавита из alphabet
+// символов. Апостериорное среднее энтропии при априорном распределении
+// Дирихле(β) усредняется по β с весом, при котором априорное распределение
+// самой энтропии ξ(β) = ψ(Kβ+1) - ψ(β+1) почти равномерно.
+func NSBEntropy(alphabet int) EstimatorFunc {
+	return func(counts []int) (float64, error) {
+		total, err := sampleSize(counts)
+		if err != nil {
+			return 0, err
+		}
+		if alphabet < len(counts) {
+			return 0, validationError("размер алфавита", -1, -1, ErrDimension, "%d меньше числа частот %d", alphabet, len(counts))
+		}
+		if alphabet == 1 {
+			return 0, nil
+		}
+
+		K := float64(alphabet)
+		N := float64(total)
+		zeros := alphabet - len(counts)
+		for _, c := range counts {
+			if c == 0 {
+				zeros++
+			}
+		}
+
+		// Логарифм правдоподобия выборки при параметре β (с точностью до константы)
+		logLikelihood := func(beta float64) float64 {
+			l := lgamma(K*beta) - lgamma(N+K*beta)
+			for _, c := range counts {
+				if c > 0 {
+					l += lgamma(float64(c)+beta) - lgamma(beta)
+				}
+			}
+			return l
+		}
+		// Апостериорное среднее энтропии (в натах) при фиксированном β
+		meanEntropy := func(beta float64) float64 {
+			a := N + K*beta
+			h := digamma(a + 1)
+			for _, c := range counts {
+				if c > 0 {
+					n := float64(c) + beta
+					h -= n / a * digamma(n+1)
+				}
+			}
+			h -= float64(zeros) * beta / a * digamma(beta+1)
+			return h
+		}
+		xi := func(beta float64) float64 {
+			return digamma(K*beta+1) - digamma(beta+1)
+		}
+
+		// Интегрирование по ξ методом трапеций на логарифмической сетке по β
+		const points = 4000
+		logMin, logMax := math.Log(1e-8), math.Log(1e6)
+		betas := make([]float64, points)
+		logL := make([]float64, points)
+		maxLogL := math.Inf(-1)
+		for i := range betas {
+			betas[i] = math.Exp(logMin + (logMax-logMin)*float64(i)/(points-1))
+			logL[i] = logLikelihood(betas[i])
+			if logL[i] > maxLogL {
+				maxLogL = logL[i]
+			}
+		}
+
+		var num, den float64
+		prevXi := xi(betas[0])
+		prevW := math.Exp(logL[0] - maxLogL)
+		prevH := meanEntropy(betas[0])
+		for i := 1; i < points; i++ {
+			curXi := xi(betas[i])
+			w := math.Exp(logL[i] - maxLogL)
+			h := meanEntropy(betas[i])
+			dxi := curXi - prevXi
+			num += dxi * (w*h + prevW*prevH) / 2
+			den += dxi * (w + prevW) / 2
+			prevXi, prevW, prevH = curXi, w, h
+		}
+		if den <= 0 || math.IsNaN(num/den) {
+			return 0, fmt.Errorf("не удалось вычислить оценку NSB")
+		}
+		return num / den / math.Ln2, nil
+	}
+}
+
+// EstimatorNames перечисляет поддерживаемые оценки энтропии
+var EstimatorNames = []string{"plugin", "mm", "cs", "grassberger", "nsb"}
+
+// ParseEstimator возвращает оценку энтропии по её названию; alphabet
+// требуется оценке NSB, которой нужно знать полный размер алфавита
+func ParseEstimator(name string, alphabet int) (EstimatorFunc, error) {
+	switch strings.ToLower(name) {
+	case "plugin", "ml":
+		return PluginEntropy, nil
+	case "mm", "miller-madow":
+		return MillerMadowEntropy, nil
+	case "cs", "chao-shen":
+		return ChaoShenEntropy, nil
+	case "grassberger", "g":
+		return GrassbergerEntropy, nil
+	case "nsb":
+		return NSBEntropy(alphabet), nil
+	}
+	return nil, fmt.Errorf("неизвестная оценка энтропии %q (ожидается одна из: %s)",
+		name, strings.Join(EstimatorNames, ", "))
+}
+
+// BootstrapInterval строит доверительный интервал оценки энтропии методом
+// бутстрэпа: из эмпирического распределения повторно извлекаются выборки того
+// же объёма. Используется «обращённый» процентильный интервал
+// [2Ĥ - q(1-α/2); 2Ĥ - q(α/2)], который учитывает смещение оценки: бутстрэп-
+// выборки смещены относительно эмпирического распределения так же, как
+// исходная выборка относительно источника.
+func BootstrapInterval(counts []int, estimator EstimatorFunc, resamples int, level float64, rng *rand.Rand) (Interval, error) {
+	total, err := sampleSize(counts)
+	if err != nil {
+		return Interval{}, err
+	}
+	if resamples < 2 {
+		return Interval{}, validationError("число бутстрэп-выборок", -1, -1, ErrOutOfRange, "должно быть не меньше 2, получено %d", resamples)
+	}
+	if level <= 0 || level >= 1 {
+		return Interval{}, validationError("доверительная вероятность", -1, -1, ErrOutOfRange, "%g вне интервала (0, 1)", level)
+	}
+
+	estimate, err := estimator(counts)
+	if err != nil {
+		return Interval{}, err
+	}
+
+	probs := make([]float64, len(counts))
+	for i, c := range counts {
+		probs[i] = float64(c) / float64(total)
+	}
+
+	estimates := make([]float64, resamples)
+	resampled := make([]int, len(counts))
+	for b := range estimates {
+		for i := range resampled {
+			resampled[i] = 0
+		}
+		for i := 0; i < total; i++ {
+			resampled[sampleIndex(probs, rng)]++
+		}
+		estimates[b], err = estimator(resampled)
+		if err != nil {
+			return Interval{}, err
+		}
+	}
+	sort.Float64s(estimates)
+
+	mean := 0.0
+	for _, e := range estimates {
+		mean += e
+	}
+	mean /= float64(resamples)
+	variance := 0.0
+	for _, e := range estimates {
+		variance += (e - mean) * (e - mean)
+	}
+	variance /= float64(resamples - 1)
+
+	alpha := (1 - level) / 2
+	return Interval{
+		Estimate: estimate,
+		Lower:    math.Max(0, 2*estimate-quantile(estimates, 1-alpha)),
+		Upper:    2*estimate - quantile(estimates, alpha),
+		StdErr:   math.Sqrt(variance),
+		Level:    level,
+	}, nil
+}
+
+// quantile возвращает квантиль уровня q отсортированного массива
+// с линейной интерполяцией между соседними элементами
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	frac := pos - float64(lo)
+	return sorted[lo]*(1-frac) + sorted[hi]*frac
+}
@@ -0,0 +1,199 @@
+package infotheory
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// MarkovSource — источник с памятью: марковская цепь порядка Order над алфавитом
+// из Alphabet символов. Состояние цепи — последние Order символов, а
+// Transitions[s][a] — вероятность появления символа a после состояния s.
+//
+// Состояние s кодируется числом в системе счисления с основанием Alphabet,
+// старший разряд которого соответствует самому раннему символу.
+type MarkovSource struct {
+	Order       int
+	Alphabet    int
+	Transitions [][]float64
+}
+
+// MarkovStats — характеристики марковского источника в стационарном режиме
+type MarkovStats struct {
+	Stationary         []float64 // стационарное распределение состояний
+	SymbolProbs        []float64 // безусловные вероятности символов
+	EntropyRate        float64   // энтропия источника H∞ (бит/символ)
+	MemorylessEntropy  float64   // энтропия источника без памяти с теми же p(a)
+	MaxEntropy         float64   // log2(Alphabet)
+	Redundancy         float64   // 1 - H∞ / MemorylessEntropy — избыточность за счёт памяти
+	RelativeRedundancy float64   // 1 - H∞ / MaxEntropy — полная избыточность
+}
+
+// NewMarkovSource проверяет матрицу переходов и создаёт источник порядка order
+func NewMarkovSource(order, alphabet int, transitions [][]float64) (*MarkovSource, error) {
+	if alphabet <= 0 {
+		return nil, validationError("размер алфавита", -1, -1, ErrOutOfRange, "должен быть больше 0, получено %d", alphabet)
+	}
+	states, err := markovStates(order, alphabet)
+	if err != nil {
+		return nil, err
+	}
+	if len(transitions) != states {
+		return nil, DimensionError("число строк матрицы переходов", states, len(transitions))
+	}
+	matrix, err := NewStochasticMatrix(transitions)
+	if err != nil {
+		return nil, err
+	}
+	if matrix.Cols() != alphabet {
+		return nil, DimensionError("число столбцов матрицы переходов", alphabet, matrix.Cols())
+	}
+
+	return &MarkovSource{Order: order, Alphabet: alphabet, Transitions: matrix.Matrix()}, nil
+}
+
+// RandomMarkovSource создаёт источник со случайной матрицей переходов:
+// каждая строка генерируется так же, как вероятности источника без памяти
+func RandomMarkovSource(order, alphabet int, rng *rand.Rand) (*MarkovSource, error) {
+	if alphabet <= 0 {
+		return nil, validationError("размер алфавита", -1, -1, ErrOutOfRange, "должен быть больше 0, получено %d", alphabet)
+	}
+	states, err := markovStates(order, alphabet)
+	if err != nil {
+		return nil, err
+	}
+	transitions := make([][]float64, states)
+	for s := range transitions {
+		transitions[s] = RandomDistribution(alphabet, rng)
+	}
+	return NewMarkovSource(order, alphabet, transitions)
+}
+
+// markovStates возвращает число состояний цепи alphabet^order
+func markovStates(order, alphabet int) (int, error) {
+	if order < 0 {
+		return 0, validationError("порядок марковского источника", -1, -1, ErrOutOfRange, "не может быть отрицательным: %d", order)
+	}
+	states := 1
+	for i := 0; i < order; i++ {
+		states *= alphabet
+		if states > 1<<22 {
+			return 0, validationError("число состояний марковского источника", -1, -1, ErrOutOfRange,
+				"слишком много состояний для порядка %d и алфавита %d", order, alphabet)
+		}
+	}
+	return states, nil
+}
+
+// States возвращает число состояний цепи
+func (m *MarkovSource) States() int {
+	return len(m.Transitions)
+}
+
+// nextState возвращает состояние после появления символа a в состоянии s
+func (m *MarkovSource) nextState(s, a int) int {
+	return (s*m.Alphabet + a) % m.States()
+}
+
+// Stationary находит стационарное распределение состояний степенным методом.
+// Используется «ленивая» цепь (P + I) / 2: её стационарное распределение
+// совпадает с исходным, но итерации сходятся и для периодических цепей.
+func (m *MarkovSource) Stationary() ([]float64, error) {
+	states := m.States()
+	pi := make([]float64, states)
+	for s := range pi {
+		pi[s] = 1 / float64(states)
+	}
+	next := make([]float64, states)
+
+	const maxIterations = 100000
+	for it := 0; it < maxIterations; it++ {
+		for s := range next {
+			next[s] = pi[s] / 2
+		}
+		for s, row := range m.Transitions {
+			if pi[s] == 0 {
+				continue
+			}
+			for a, p := range row {
+				next[m.nextState(s, a)] += pi[s] * p / 2
+			}
+		}
+
+		diff := 0.0
+		for s := range pi {
+			diff += math.Abs(next[s] - pi[s])
+		}
+		pi, next = next, pi
+		if diff < 1e-13 {
+			return pi, nil
+		}
+	}
+	return nil, fmt.Errorf("стационарное распределение не найдено за %d итераций", maxIterations)
+}
+
+// SymbolProbabilities вычисляет безусловные вероятности символов по
+// стационарному распределению состояний
+func (m *MarkovSource) SymbolProbabilities(stationary []float64) []float64 {
+	probs := make([]float64, m.Alphabet)
+	for s, row := range m.Transitions {
+		for a, p := range row {
+			probs[a] += stationary[s] * p
+		}
+	}
+	return probs
+}
+
+// EntropyRate вычисляет энтропию источника с памятью
+// H∞ = Σ π(s) · H(X | s) в битах на символ
+func (m *MarkovSource) EntropyRate(stationary []float64) float64 {
+	h := 0.0
+	for s, row := range m.Transitions {
+		h += stationary[s] * Entropy(row)
+	}
+	return h
+}
+
+// Analyze вычисляет стационарное распределение, энтропию источника и его
+// избыточность по сравнению с источником без памяти
+func (m *MarkovSource) Analyze() (MarkovStats, error) {
+	pi, err := m.Stationary()
+	if err != nil {
+		return MarkovStats{}, err
+	}
+	stats := MarkovStats{
+		Stationary:  pi,
+		SymbolProbs: m.SymbolProbabilities(pi),
+		EntropyRate: m.EntropyRate(pi),
+		MaxEntropy:  MaxEntropy(m.Alphabet),
+	}
+	stats.MemorylessEntropy = Entropy(stats.SymbolProbs)
+	if stats.MemorylessEntropy > 0 {
+		stats.Redundancy = 1 - stats.EntropyRate/stats.MemorylessEntropy
+	}
+	if stats.MaxEntropy > 0 {
+		stats.RelativeRedundancy = 1 - stats.EntropyRate/stats.MaxEntropy
+	}
+	return stats, nil
+}
+
+// Generate генерирует последовательность из length символов. Начальное
+// состояние выбирается по стационарному распределению, поэтому
+// последовательность сразу находится в установившемся режиме.
+func (m *MarkovSource) Generate(length int, rng *rand.Rand) ([]int, error) {
+	if length < 0 {
+		return nil, validationError("длина последовательности", -1, -1, ErrOutOfRange, "не может быть отрицательной: %d", length)
+	}
+	pi, err := m.Stationary()
+	if err != nil {
+		return nil, err
+	}
+	seq := make([]int, length)
+	state := sampleIndex(pi, rng)
+	for i := range seq {
+		a := sampleIndex(m.Transitions[state], rng)
+		seq[i] = a
+		state = m.nextState(state, a)
+	}
+	return seq, nil
+}
@@ -0,0 +1,223 @@
+package infotheory
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Unit — единица измерения количества информации (основание логарифма)
+type Unit int
+
+const (
+	Bits     Unit = iota // log2
+	Nats                 // ln
+	Hartleys             // log10
+)
+
+// String возвращает название единицы измерения
+func (u Unit) String() string {
+	switch u {
+	case Bits:
+		return "bits"
+	case Nats:
+		return "nats"
+	case Hartleys:
+		return "hartleys"
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+// fromNats переводит величину из натов в единицу u
+func (u Unit) fromNats(x float64) float64 {
+	switch u {
+	case Nats:
+		return x
+	case Hartleys:
+		return x / math.Ln10
+	}
+	return x / math.Ln2
+}
+
+// ParseUnit преобразует название единицы измерения в Unit
+func ParseUnit(name string) (Unit, error) {
+	switch strings.ToLower(name) {
+	case "bits", "bit", "2":
+		return Bits, nil
+	case "nats", "nat", "e":
+		return Nats, nil
+	case "hartleys", "hartley", "dits", "bans", "10":
+		return Hartleys, nil
+	}
+	return 0, fmt.Errorf("неизвестная единица измерения %q (ожидается bits, nats или hartleys)", name)
+}
+
+// ShannonEntropy вычисляет энтропию Шеннона H(X) = -Σ p log p в единицах unit
+func ShannonEntropy(probs []float64, unit Unit) (float64, error) {
+	if err := checkDistribution(probs); err != nil {
+		return 0, err
+	}
+	return unit.fromNats(shannonNats(probs)), nil
+}
+
+// shannonNats вычисляет энтропию Шеннона в натах без проверки входных данных
+func shannonNats(probs []float64) float64 {
+	h := 0.0
+	for _, p := range probs {
+		if p > 0 {
+			h -= p * math.Log(p)
+		}
+	}
+	return h
+}
+
+// RenyiEntropy вычисляет энтропию Реньи порядка alpha:
+// H_α = log(Σ p^α) / (1 - α). Частные случаи: α = 0 — энтропия Хартли,
+// α = 1 — энтропия Шеннона, α = +Inf — минимальная энтропия.
+func RenyiEntropy(probs []float64, alpha float64, unit Unit) (float64, error) {
+	if err := checkDistribution(probs); err != nil {
+		return 0, err
+	}
+	if alpha < 0 || math.IsNaN(alpha) {
+		return 0, validationError("порядок энтропии Реньи", -1, -1, ErrOutOfRange, "должен быть неотрицательным, получено %g", alpha)
+	}
+
+	switch {
+	case alpha == 0:
+		support := 0
+		for _, p := range probs {
+			if p > 0 {
+				support++
+			}
+		}
+		return unit.fromNats(math.Log(float64(support))), nil
+	case alpha == 1:
+		return unit.fromNats(shannonNats(probs)), nil
+	case math.IsInf(alpha, 1):
+		maxP := 0.0
+		for _, p := range probs {
+			maxP = math.Max(maxP, p)
+		}
+		return unit.fromNats(-math.Log(maxP)), nil
+	}
+
+	sum := 0.0
+	for _, p := range probs {
+		if p > 0 {
+			sum += math.Pow(p, alpha)
+		}
+	}
+	return unit.fromNats(math.Log(sum) / (1 - alpha)), nil
+}
+
+// HartleyEntropy вычисляет энтропию Хартли — логарифм числа возможных символов
+func HartleyEntropy(probs []float64, unit Unit) (float64, error) {
+	return RenyiEntropy(probs, 0, unit)
+}
+
+// MinEntropy вычисляет минимальную энтропию -log max p(x)
+func MinEntropy(probs []float64, unit Unit) (float64, error) {
+	return RenyiEntropy(probs, math.Inf(1), unit)
+}
+
+// TsallisEntropy вычисляет энтропию Цаллиса S_q = (1 - Σ p^q) / (q - 1).
+// Величина безразмерна; при q = 1 она совпадает с энтропией Шеннона в натах.
+func TsallisEntropy(probs []float64, q float64) (float64, error) {
+	if err := checkDistribution(probs); err != nil {
+		return 0, err
+	}
+	if math.IsNaN(q) || math.IsInf(q, 0) {
+		return 0, validationError("параметр энтропии Цаллиса", -1, -1, ErrInvalidValue, "должен быть конечным, получено %g", q)
+	}
+	if q == 1 {
+		return shannonNats(probs), nil
+	}
+	sum := 0.0
+	for _, p := range probs {
+		if p > 0 {
+			sum += math.Pow(p, q)
+		}
+	}
+	return (1 - sum) / (q - 1), nil
+}
+
+// KLDivergence вычисляет расхождение Кульбака–Лейблера D(p || q) = Σ p log(p/q).
+// Если q(x) = 0 при p(x) > 0, расхождение бесконечно.
+func KLDivergence(p, q []float64, unit Unit) (float64, error) {
+	if err := checkPair(p, q); err != nil {
+		return 0, err
+	}
+	d := 0.0
+	for i := range p {
+		if p[i] == 0 {
+			continue
+		}
+		if q[i] == 0 {
+			return math.Inf(1), nil
+		}
+		d += p[i] * math.Log(p[i]/q[i])
+	}
+	return unit.fromNats(d), nil
+}
+
+// CrossEntropy вычисляет перекрёстную энтропию H(p, q) = -Σ p log q = H(p) + D(p || q)
+func CrossEntropy(p, q []float64, unit Unit) (float64, error) {
+	if err := checkPair(p, q); err != nil {
+		return 0, err
+	}
+	h := 0.0
+	for i := range p {
+		if p[i] == 0 {
+			continue
+		}
+		if q[i] == 0 {
+			return math.Inf(1), nil
+		}
+		h -= p[i] * math.Log(q[i])
+	}
+	return unit.fromNats(h), nil
+}
+
+// JensenShannonDivergence вычисляет расхождение Йенсена–Шеннона
+// JS(p, q) = (D(p || m) + D(q || m)) / 2, где m = (p + q) / 2
+func JensenShannonDivergence(p, q []float64, unit Unit) (float64, error) {
+	if err := checkPair(p, q); err != nil {
+		return 0, err
+	}
+	d := 0.0
+	for i := range p {
+		m := (p[i] + q[i]) / 2
+		if p[i] > 0 {
+			d += p[i] * math.Log(p[i]/m) / 2
+		}
+		if q[i] > 0 {
+			d += q[i] * math.Log(q[i]/m) / 2
+		}
+	}
+	return unit.fromNats(d), nil
+}
+
+// TotalVariation вычисляет расстояние полной вариации (1/2) Σ |p - q|
+func TotalVariation(p, q []float64) (float64, error) {
+	if err := checkPair(p, q); err != nil {
+		return 0, err
+	}
+	d := 0.0
+	for i := range p {
+		d += math.Abs(p[i] - q[i])
+	}
+	return d / 2, nil
+}
+
+// HellingerDistance вычисляет расстояние Хеллингера
+// sqrt(1 - Σ sqrt(p q)), принимающее значения от 0 до 1
+func HellingerDistance(p, q []float64) (float64, error) {
+	if err := checkPair(p, q); err != nil {
+		return 0, err
+	}
+	bc := 0.0
+	for i := range p {
+		bc += math.Sqrt(p[i] * q[i])
+	}
+	return math.Sqrt(math.Max(0, 1-bc)), nil
+}
@@ -0,0 +1,28 @@
+package infotheory
+
+import "math"
+
+// digamma вычисляет ψ(x) = Γ'(x)/Γ(x) для x > 0: аргумент увеличивается
+// рекуррентным соотношением ψ(x) = ψ(x+1) - 1/x, затем применяется
+// асимптотический ряд
+func digamma(x float64) float64 {
+	if x <= 0 {
+		return math.NaN()
+	}
+	result := 0.0
+	for x < 6 {
+		result -= 1 / x
+		x++
+	}
+	inv := 1 / x
+	inv2 := inv * inv
+	result += math.Log(x) - 0.5*inv -
+		inv2*(1.0/12-inv2*(1.0/120-inv2*(1.0/252-inv2*(1.0/240-inv2*(1.0/132)))))
+	return result
+}
+
+// lgamma возвращает ln Γ(x) для x > 0
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
@@ -0,0 +1,146 @@
+package infotheory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// probTolerance — допустимое отклонение суммы вероятностей от 1
+const probTolerance = 1e-9
+
+// Ошибки проверки входных данных. Конкретная ошибка возвращается в виде
+// *ValidationError и распознаётся с помощью errors.Is и errors.As.
+var (
+	ErrEmpty         = errors.New("данные пусты")
+	ErrNegative      = errors.New("отрицательное значение")
+	ErrInvalidValue  = errors.New("недопустимое значение")
+	ErrOutOfRange    = errors.New("значение вне допустимого диапазона")
+	ErrNotNormalized = errors.New("сумма вероятностей не равна 1")
+	ErrDimension     = errors.New("несогласованные размеры")
+)
+
+// ValidationError описывает, какое именно значение не прошло проверку
+type ValidationError struct {
+	What   string // проверяемый объект: "распределение", "матрица переходов", ...
+	Row    int    // номер элемента или строки (-1, если не относится к элементу)
+	Col    int    // номер столбца (-1, если не относится к столбцу)
+	Detail string // дополнительные сведения
+	Err    error  // одна из ошибок ErrEmpty, ErrNegative, ...
+}
+
+// Error возвращает текст ошибки
+func (e *ValidationError) Error() string {
+	msg := e.What
+	switch {
+	case e.Row >= 0 && e.Col >= 0:
+		msg += fmt.Sprintf("[%d][%d]", e.Row, e.Col)
+	case e.Row >= 0:
+		msg += fmt.Sprintf("[%d]", e.Row)
+	}
+	msg += ": " + e.Err.Error()
+	if e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+	return msg
+}
+
+// Unwrap возвращает причину ошибки для errors.Is
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validationError создаёт *ValidationError с форматированным описанием
+func validationError(what string, row, col int, err error, format string, args ...any) *ValidationError {
+	return &ValidationError{What: what, Row: row, Col: col, Err: err, Detail: fmt.Sprintf(format, args...)}
+}
+
+// NewValidationError создаёт ошибку проверки объекта what с причиной err
+// (одной из ErrEmpty, ErrNegative, ...) и пояснением detail
+func NewValidationError(what string, err error, detail string) error {
+	return &ValidationError{What: what, Row: -1, Col: -1, Err: err, Detail: detail}
+}
+
+// DimensionError сообщает о несовпадении размера объекта what с ожидаемым
+func DimensionError(what string, want, got int) error {
+	return validationError(what, -1, -1, ErrDimension, "ожидалось %d, получено %d", want, got)
+}
+
+// CheckSquare проверяет, что matrix — квадратная матрица размера n x n.
+// Проверяются все строки, а не только первая.
+func CheckSquare(what string, matrix [][]float64, n int) error {
+	if n <= 0 {
+		return validationError(what, -1, -1, ErrEmpty, "размер должен быть больше 0")
+	}
+	if len(matrix) != n {
+		return validationError(what, -1, -1, ErrDimension, "ожидалось %d строк, получено %d", n, len(matrix))
+	}
+	for i, row := range matrix {
+		if len(row) != n {
+			return validationError(what, i, -1, ErrDimension, "ожидалось %d элементов, получено %d", n, len(row))
+		}
+	}
+	return nil
+}
+
+// CheckNonNegative проверяет, что все значения конечны и неотрицательны
+func CheckNonNegative(what string, values []float64) error {
+	if len(values) == 0 {
+		return validationError(what, -1, -1, ErrEmpty, "")
+	}
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return validationError(what, i, -1, ErrInvalidValue, "%v", v)
+		}
+		if v < 0 {
+			return validationError(what, i, -1, ErrNegative, "%g", v)
+		}
+	}
+	return nil
+}
+
+// checkProbabilities проверяет, что probs — распределение вероятностей.
+// row — номер строки матрицы, если проверяется её строка, иначе -1.
+func checkProbabilities(what string, probs []float64, row int) error {
+	if len(probs) == 0 {
+		return validationError(what, row, -1, ErrEmpty, "")
+	}
+	sum := 0.0
+	for i, p := range probs {
+		r, c := i, -1
+		if row >= 0 {
+			r, c = row, i
+		}
+		if math.IsNaN(p) || math.IsInf(p, 0) {
+			return validationError(what, r, c, ErrInvalidValue, "%v", p)
+		}
+		if p < 0 {
+			return validationError(what, r, c, ErrNegative, "%g", p)
+		}
+		sum += p
+	}
+	if math.Abs(sum-1) > probTolerance {
+		return validationError(what, row, -1, ErrNotNormalized, "сумма равна %.12g", sum)
+	}
+	return nil
+}
+
+// checkDistribution проверяет, что probs — распределение вероятностей:
+// непустой массив неотрицательных чисел с суммой 1
+func checkDistribution(probs []float64) error {
+	return checkProbabilities("распределение", probs, -1)
+}
+
+// checkPair проверяет два распределения на одном и том же алфавите
+func checkPair(p, q []float64) error {
+	if err := checkDistribution(p); err != nil {
+		return err
+	}
+	if err := checkDistribution(q); err != nil {
+		return err
+	}
+	if len(p) != len(q) {
+		return DimensionError("размер алфавита второго распределения", len(p), len(q))
+	}
+	return nil
+}
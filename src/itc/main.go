@@ -0,0 +1,125 @@
+// Команда itc объединяет лабораторные работы по теории информации и кодированию
+// в одну программу с подкомандами. Все параметры, которые в лабораторных были
+// зашиты в код, задаются флагами; справку по любой подкоманде выводит флаг --help.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command — подкоманда itc. У группы команд (hamming, secded) вместо run
+// заполнен список subcommands.
+type command struct {
+	name        string
+	summary     string
+	run         func(path string, args []string) error
+	subcommands []*command
+}
+
+// commands — все подкоманды itc в порядке вывода в справке
+var commands = []*command{
+	{name: "entropy", summary: "энтропия случайных источников (лабораторная работа 1)", run: runEntropy},
+	{name: "sweep", summary: "зависимость энтропии от закона распределения", run: runSweep},
+	{name: "markov", summary: "энтропия и избыточность марковских источников", run: runMarkov},
+	{name: "generalized", summary: "энтропии Реньи и Цаллиса, расхождение с равномерным", run: runGeneralized},
+	{name: "estimate", summary: "оценки энтропии по выборке с доверительным интервалом", run: runEstimate},
+	{name: "empirical", summary: "эмпирическая энтропия файла или стандартного ввода", run: runEmpirical},
+	{name: "channel", summary: "дискретный канал с помехами (лабораторная работа 2)", run: runChannel},
+	{name: "capacity", summary: "пропускная способность и скорость передачи (лабораторная работа 3)", run: runCapacity},
+	{name: "hamming", summary: "код Хэмминга в систематической и позиционной формах (лабораторные работы 4 и 5)", subcommands: codeCommands(hammingFamily)},
+	{name: "secded", summary: "расширенный код Хэмминга SECDED (лабораторная работа 5)", subcommands: codeCommands(secdedFamily)},
+	{name: "qhamming", summary: "коды Хэмминга над GF(q) для простого q и степени простого", subcommands: qhammingCommands},
+	{name: "golay", summary: "коды Голея (23,12) и (24,12), исправляющие три ошибки", subcommands: golayCommands()},
+	{name: "rm", summary: "коды Рида — Маллера RM(r,m): преобразование Адамара и мажоритарное декодирование", subcommands: codeCommands(reedMullerFamily)},
+	{name: "ldpc", summary: "LDPC-коды: построение Галлагера и PEG, формат alist, декодеры sum-product и min-sum", subcommands: ldpcCommands()},
+	{name: "polar", summary: "полярные коды: построение по BEC или BSC, декодеры SC и SCL с CRC", subcommands: polarCommands()},
+	{name: "turbo", summary: "турбокоды: RSC-кодеры с перемежителем, итеративное декодирование Log-MAP и Max-Log-MAP", subcommands: turboCommands()},
+	{name: "fountain", summary: "фонтанные коды LT и Raptor: распределение Солитона, декодирование очисткой, избыток приёма", subcommands: fountainCommands},
+	{name: "compose", summary: "составные коды: произведение кодов и конкатенация с внешним кодом Рида — Соломона", subcommands: composeCommands},
+	{name: "bounds", summary: "границы Хэмминга, Синглтона, Плоткина, Элайеса и Гилберта — Варшамова, проверка (n,k,d)", subcommands: boundsCommands},
+	{name: "plot", summary: "графики энтропии, количества информации, пропускной способности и BER в SVG или PNG", subcommands: plotCommands},
+	{name: "bench", summary: "скорость кодирования и декодирования: []int против упакованных BitVec", run: runBench},
+	{name: "tables", summary: "таблицы синдромов и проверочных бит, генерация исходного текста на Go или C", run: runTables},
+	{name: "hdl", summary: "модули кодера и декодера на Verilog или VHDL с самопроверяющимся тестовым модулем", run: runHDL},
+}
+
+// errNoCommand — команда вызвана без подкоманды; справка уже выведена
+var errNoCommand = errors.New("не указана команда")
+
+// newFlagSet создаёт набор флагов подкоманды с единообразной справкой
+func newFlagSet(path, summary string) *flag.FlagSet {
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Использование: %s [флаги]\n\n%s\n", path, summary)
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(out, "\nФлаги:")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// printUsage выводит список подкоманд группы
+func printUsage(out io.Writer, path, summary string, cmds []*command) {
+	fmt.Fprintf(out, "Использование: %s <команда> [флаги]\n\n%s\n\nКоманды:\n", path, summary)
+	for _, c := range cmds {
+		fmt.Fprintf(out, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(out, "\nСправка по команде: %s <команда> --help\n", path)
+}
+
+// dispatch находит подкоманду по первому аргументу и запускает её
+func dispatch(path, summary string, cmds []*command, args []string) error {
+	if len(args) == 0 {
+		printUsage(os.Stderr, path, summary, cmds)
+		return errNoCommand
+	}
+	name := args[0]
+	switch name {
+	case "-h", "-help", "--help", "help":
+		if name == "help" && len(args) > 1 {
+			return dispatch(path, summary, cmds, []string{args[1], "--help"})
+		}
+		printUsage(os.Stdout, path, summary, cmds)
+		return nil
+	}
+	for _, c := range cmds {
+		if c.name != name {
+			continue
+		}
+		subPath := path + " " + c.name
+		if c.subcommands != nil {
+			return dispatch(subPath, c.summary, c.subcommands, args[1:])
+		}
+		return c.run(subPath, args[1:])
+	}
+	printUsage(os.Stderr, path, summary, cmds)
+	return fmt.Errorf("неизвестная команда %q", name)
+}
+
+func main() {
+	err := dispatch("itc", "Теория информации и кодирования: эксперименты лабораторных работ.", commands, os.Args[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		// Справка по флагам уже выведена
+	case errors.Is(err, errNoCommand):
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+}
+
+// joinNames перечисляет допустимые значения флага через запятую
+func joinNames(names []string) string {
+	return strings.Join(names, ", ")
+}
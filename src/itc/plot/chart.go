@@ -0,0 +1,335 @@
+// Package plot строит графики результатов экспериментов без внешних программ:
+// линейные графики с осями (в том числе логарифмическими), сеткой, подписями
+// и легендой в форматах PNG и SVG. Рисование отделено от формата изображения
+// интерфейсом canvas.
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Series — одна кривая графика
+type Series struct {
+	Name string
+	X, Y []float64
+}
+
+// Chart — линейный график из нескольких кривых
+type Chart struct {
+	Title  string
+	XLabel string
+	YLabel string
+	LogX   bool // логарифмическая шкала по оси абсцисс
+	LogY   bool // логарифмическая шкала по оси ординат
+	Series []Series
+}
+
+// Размер изображения по умолчанию в пикселях
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 480
+)
+
+// palette — цвета кривых по порядку
+var palette = []color.RGBA{
+	{0x1f, 0x77, 0xb4, 0xff},
+	{0xd6, 0x27, 0x28, 0xff},
+	{0x2c, 0xa0, 0x2c, 0xff},
+	{0xff, 0x7f, 0x0e, 0xff},
+	{0x94, 0x67, 0xbd, 0xff},
+	{0x8c, 0x56, 0x4b, 0xff},
+	{0xe3, 0x77, 0xc2, 0xff},
+	{0x7f, 0x7f, 0x7f, 0xff},
+}
+
+var (
+	black     = color.RGBA{0, 0, 0, 0xff}
+	gridColor = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	white     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// anchor — выравнивание текста относительно точки привязки
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// canvas — поверхность рисования в пикселях, ось y направлена вниз
+type canvas interface {
+	// fillRect закрашивает прямоугольник
+	fillRect(x, y, w, h float64, c color.Color)
+	// line рисует отрезок толщиной width
+	line(x1, y1, x2, y2, width float64, c color.Color)
+	// circle рисует закрашенный круг
+	circle(x, y, r float64, c color.Color)
+	// text выводит строку; y — базовая линия
+	text(x, y float64, s string, a anchor, c color.Color)
+	// textWidth возвращает ширину строки
+	textWidth(s string) float64
+}
+
+// fontSize — размер шрифта подписей в пикселях
+const fontSize = 13
+
+// axis — диапазон оси с делениями
+type axis struct {
+	min, max float64
+	ticks    []float64
+	decimals int
+	log      bool
+}
+
+// niceStep возвращает шаг делений 1, 2 или 5 × 10^k, при котором на отрезке
+// span помещается около count делений
+func niceStep(span float64, count int) float64 {
+	raw := span / float64(count)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5} {
+		if raw <= m*mag {
+			return m * mag
+		}
+	}
+	return 10 * mag
+}
+
+// newAxis строит ось, покрывающую [lo, hi] целым числом делений
+func newAxis(lo, hi float64) axis {
+	if lo == hi {
+		d := math.Max(math.Abs(lo)/2, 1)
+		lo, hi = lo-d, hi+d
+	}
+	step := niceStep(hi-lo, 6)
+	a := axis{min: math.Floor(lo/step) * step, max: math.Ceil(hi/step) * step}
+	a.decimals = max(0, int(-math.Floor(math.Log10(step))))
+	for i := 0; ; i++ {
+		v := a.min + float64(i)*step
+		if v > a.max+step/2 {
+			break
+		}
+		a.ticks = append(a.ticks, v)
+	}
+	return a
+}
+
+// newLogAxis строит логарифмическую ось, покрывающую [lo, hi] целым числом
+// декад; на коротких осях добавляются деления 2 и 5 внутри декады
+func newLogAxis(lo, hi float64) axis {
+	first, last := math.Floor(math.Log10(lo)), math.Ceil(math.Log10(hi))
+	if first == last {
+		first--
+		last++
+	}
+	a := axis{min: math.Pow(10, first), max: math.Pow(10, last), log: true}
+	for k := first; k <= last; k++ {
+		decade := math.Pow(10, k)
+		a.ticks = append(a.ticks, decade)
+		if last-first <= 2 && k < last {
+			a.ticks = append(a.ticks, 2*decade, 5*decade)
+		}
+	}
+	return a
+}
+
+// label форматирует подпись деления
+func (a axis) label(v float64) string {
+	if a.log {
+		if v >= 1e-3 && v < 1e5 {
+			return fmt.Sprintf("%g", v)
+		}
+		return fmt.Sprintf("%.0e", v)
+	}
+	if math.Abs(v) < 1e-12 {
+		v = 0
+	}
+	return fmt.Sprintf("%.*f", a.decimals, v)
+}
+
+// scale переводит значение на оси в координату из отрезка [from, to]
+func (a axis) scale(v, from, to float64) float64 {
+	if a.log {
+		return from + math.Log10(v/a.min)/math.Log10(a.max/a.min)*(to-from)
+	}
+	return from + (v-a.min)/(a.max-a.min)*(to-from)
+}
+
+// usable сообщает, что точку можно нанести на график: координаты конечны,
+// а на логарифмических осях ещё и положительны
+func (c *Chart) usable(x, y float64) bool {
+	if math.IsNaN(x) || math.IsInf(x, 0) || math.IsNaN(y) || math.IsInf(y, 0) {
+		return false
+	}
+	return (!c.LogX || x > 0) && (!c.LogY || y > 0)
+}
+
+// Validate проверяет, что график можно построить
+func (c *Chart) Validate() error {
+	if len(c.Series) == 0 {
+		return fmt.Errorf("график %q: нет ни одной кривой", c.Title)
+	}
+	points := 0
+	for _, s := range c.Series {
+		if len(s.X) != len(s.Y) {
+			return fmt.Errorf("график %q, кривая %q: %d значений x и %d значений y", c.Title, s.Name, len(s.X), len(s.Y))
+		}
+		for i := range s.X {
+			if c.usable(s.X[i], s.Y[i]) {
+				points++
+			}
+		}
+	}
+	if points == 0 {
+		return fmt.Errorf("график %q: нет ни одной точки, которую можно нанести на оси", c.Title)
+	}
+	return nil
+}
+
+// axes вычисляет диапазоны осей по всем точкам графика
+func (c *Chart) axes() (axis, axis) {
+	xLo, xHi := math.Inf(1), math.Inf(-1)
+	yLo, yHi := math.Inf(1), math.Inf(-1)
+	for _, s := range c.Series {
+		for i := range s.X {
+			if !c.usable(s.X[i], s.Y[i]) {
+				continue
+			}
+			xLo, xHi = math.Min(xLo, s.X[i]), math.Max(xHi, s.X[i])
+			yLo, yHi = math.Min(yLo, s.Y[i]), math.Max(yHi, s.Y[i])
+		}
+	}
+	x, y := newAxis(xLo, xHi), newAxis(yLo, yHi)
+	if c.LogX {
+		x = newLogAxis(xLo, xHi)
+	}
+	if c.LogY {
+		y = newLogAxis(yLo, yHi)
+	}
+	return x, y
+}
+
+// draw рисует график на поверхности размером width x height
+func (c *Chart) draw(cv canvas, width, height float64) {
+	xAxis, yAxis := c.axes()
+
+	cv.fillRect(0, 0, width, height, white)
+	top := 12.0
+	if c.Title != "" {
+		top += fontSize + 8
+		cv.text(width/2, top-8, c.Title, anchorMiddle, black)
+	}
+	if c.YLabel != "" {
+		top += fontSize + 6
+	}
+	left := 12.0
+	for _, v := range yAxis.ticks {
+		left = math.Max(left, cv.textWidth(yAxis.label(v))+18)
+	}
+	right := width - 20
+	bottom := height - 2*fontSize - 16
+	if c.XLabel == "" {
+		bottom += fontSize + 6
+	}
+	if c.YLabel != "" {
+		cv.text(left, top-8, c.YLabel, anchorStart, black)
+	}
+
+	px := func(v float64) float64 { return xAxis.scale(v, left, right) }
+	py := func(v float64) float64 { return yAxis.scale(v, bottom, top) }
+
+	for _, v := range xAxis.ticks {
+		x := px(v)
+		cv.line(x, top, x, bottom, 1, gridColor)
+		cv.line(x, bottom, x, bottom+5, 1, black)
+		cv.text(x, bottom+fontSize+6, xAxis.label(v), anchorMiddle, black)
+	}
+	for _, v := range yAxis.ticks {
+		y := py(v)
+		cv.line(left, y, right, y, 1, gridColor)
+		cv.line(left-5, y, left, y, 1, black)
+		cv.text(left-8, y+fontSize/3, yAxis.label(v), anchorEnd, black)
+	}
+	cv.line(left, top, left, bottom, 1, black)
+	cv.line(left, bottom, right, bottom, 1, black)
+	cv.line(left, top, right, top, 1, black)
+	cv.line(right, top, right, bottom, 1, black)
+	if c.XLabel != "" {
+		cv.text((left+right)/2, height-8, c.XLabel, anchorMiddle, black)
+	}
+
+	var points [][2]float64
+	for i, s := range c.Series {
+		col := palette[i%len(palette)]
+		prev := false
+		var x0, y0 float64
+		for j := range s.X {
+			if !c.usable(s.X[j], s.Y[j]) {
+				prev = false
+				continue
+			}
+			x, y := px(s.X[j]), py(s.Y[j])
+			if prev {
+				cv.line(x0, y0, x, y, 2, col)
+			}
+			cv.circle(x, y, 3, col)
+			points = append(points, [2]float64{x, y})
+			x0, y0, prev = x, y, true
+		}
+	}
+	c.drawLegend(cv, left, top, right, bottom, points)
+}
+
+// drawLegend выводит легенду в том углу области графика, где её перекрывает
+// меньше всего точек
+func (c *Chart) drawLegend(cv canvas, left, top, right, bottom float64, points [][2]float64) {
+	named := 0
+	textWidth := 0.0
+	for _, s := range c.Series {
+		if s.Name != "" {
+			named++
+			textWidth = math.Max(textWidth, cv.textWidth(s.Name))
+		}
+	}
+	if named == 0 {
+		return
+	}
+	const (
+		sample = 24.0
+		row    = fontSize + 6
+	)
+	w := textWidth + sample + 24
+	h := float64(named)*row + 8
+	corners := [][2]float64{{right - w - 8, top + 8}, {left + 8, top + 8}, {right - w - 8, bottom - h - 8}, {left + 8, bottom - h - 8}}
+	x, y := corners[0][0], corners[0][1]
+	best := len(points) + 1
+	for _, corner := range corners {
+		covered := 0
+		for _, p := range points {
+			if p[0] >= corner[0]-4 && p[0] <= corner[0]+w+4 && p[1] >= corner[1]-4 && p[1] <= corner[1]+h+4 {
+				covered++
+			}
+		}
+		if covered < best {
+			x, y, best = corner[0], corner[1], covered
+		}
+	}
+	cv.fillRect(x, y, w, h, white)
+	cv.line(x, y, x+w, y, 1, black)
+	cv.line(x, y+h, x+w, y+h, 1, black)
+	cv.line(x, y, x, y+h, 1, black)
+	cv.line(x+w, y, x+w, y+h, 1, black)
+	ly := y + 4 + row/2
+	for i, s := range c.Series {
+		if s.Name == "" {
+			continue
+		}
+		col := palette[i%len(palette)]
+		cv.line(x+8, ly, x+8+sample, ly, 2, col)
+		cv.circle(x+8+sample/2, ly, 3, col)
+		cv.text(x+16+sample, ly+fontSize/3, s.Name, anchorStart, black)
+		ly += row
+	}
+}
@@ -0,0 +1,42 @@
+package plot
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format — формат изображения графика
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// FormatFromPath определяет формат изображения по расширению файла
+func FormatFromPath(path string) (Format, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".png":
+		return FormatPNG, nil
+	case ".svg":
+		return FormatSVG, nil
+	default:
+		return "", fmt.Errorf("неизвестный формат изображения %q (ожидается .svg или .png)", ext)
+	}
+}
+
+// Write выводит график в формате f
+func (c *Chart) Write(w io.Writer, f Format, width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("размер изображения должен быть положительным, получено %d x %d", width, height)
+	}
+	switch f {
+	case FormatPNG:
+		return c.WritePNG(w, width, height)
+	case FormatSVG:
+		return c.WriteSVG(w, width, height)
+	}
+	return fmt.Errorf("неизвестный формат изображения %q", string(f))
+}
@@ -0,0 +1,118 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// loadFace загружает шрифт Go Regular: он встроен в программу и содержит
+// кириллицу, поэтому подписи не зависят от шрифтов системы
+var loadFace = sync.OnceValues(func() (font.Face, error) {
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: fontSize, DPI: 72, Hinting: font.HintingFull})
+})
+
+// pngCanvas рисует на растровом изображении со сглаживанием
+type pngCanvas struct {
+	img  *image.RGBA
+	face font.Face
+	r    *vector.Rasterizer
+}
+
+func newPNGCanvas(width, height int) (*pngCanvas, error) {
+	face, err := loadFace()
+	if err != nil {
+		return nil, err
+	}
+	return &pngCanvas{
+		img:  image.NewRGBA(image.Rect(0, 0, width, height)),
+		face: face,
+		r:    vector.NewRasterizer(width, height),
+	}, nil
+}
+
+// fill закрашивает многоугольник с вершинами (xs[i], ys[i])
+func (p *pngCanvas) fill(xs, ys []float64, c color.Color) {
+	b := p.img.Bounds()
+	p.r.Reset(b.Dx(), b.Dy())
+	p.r.DrawOp = draw.Over
+	p.r.MoveTo(float32(xs[0]), float32(ys[0]))
+	for i := 1; i < len(xs); i++ {
+		p.r.LineTo(float32(xs[i]), float32(ys[i]))
+	}
+	p.r.ClosePath()
+	p.r.Draw(p.img, b, image.NewUniform(c), image.Point{})
+}
+
+func (p *pngCanvas) fillRect(x, y, w, h float64, c color.Color) {
+	p.fill([]float64{x, x + w, x + w, x}, []float64{y, y, y + h, y + h}, c)
+}
+
+func (p *pngCanvas) line(x1, y1, x2, y2, width float64, c color.Color) {
+	dx, dy := x2-x1, y2-y1
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	// Смещение на половину толщины перпендикулярно отрезку
+	nx, ny := -dy/length*width/2, dx/length*width/2
+	p.fill([]float64{x1 + nx, x2 + nx, x2 - nx, x1 - nx}, []float64{y1 + ny, y2 + ny, y2 - ny, y1 - ny}, c)
+}
+
+func (p *pngCanvas) circle(x, y, r float64, c color.Color) {
+	const segments = 16
+	xs := make([]float64, segments)
+	ys := make([]float64, segments)
+	for i := range xs {
+		a := 2 * math.Pi * float64(i) / segments
+		xs[i], ys[i] = x+r*math.Cos(a), y+r*math.Sin(a)
+	}
+	p.fill(xs, ys, c)
+}
+
+func (p *pngCanvas) textWidth(s string) float64 {
+	return float64(font.MeasureString(p.face, s)) / 64
+}
+
+func (p *pngCanvas) text(x, y float64, s string, a anchor, c color.Color) {
+	switch a {
+	case anchorMiddle:
+		x -= p.textWidth(s) / 2
+	case anchorEnd:
+		x -= p.textWidth(s)
+	}
+	d := font.Drawer{
+		Dst:  p.img,
+		Src:  image.NewUniform(c),
+		Face: p.face,
+		Dot:  fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6(y * 64)},
+	}
+	d.DrawString(s)
+}
+
+// WritePNG выводит график изображением PNG размером width x height пикселей
+func (c *Chart) WritePNG(w io.Writer, width, height int) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	cv, err := newPNGCanvas(width, height)
+	if err != nil {
+		return err
+	}
+	c.draw(cv, float64(width), float64(height))
+	return png.Encode(w, cv.img)
+}
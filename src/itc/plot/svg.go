@@ -0,0 +1,67 @@
+package plot
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// svgCanvas выводит элементы SVG. Ширина текста измеряется по встроенному
+// шрифту Go Regular, который указан первым в font-family.
+type svgCanvas struct {
+	w    *bufio.Writer
+	face font.Face
+}
+
+// svgColor возвращает цвет в виде #rrggbb
+func svgColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+func (s *svgCanvas) fillRect(x, y, w, h float64, c color.Color) {
+	fmt.Fprintf(s.w, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, w, h, svgColor(c))
+}
+
+func (s *svgCanvas) line(x1, y1, x2, y2, width float64, c color.Color) {
+	fmt.Fprintf(s.w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%g" stroke-linecap="round"/>`+"\n",
+		x1, y1, x2, y2, svgColor(c), width)
+}
+
+func (s *svgCanvas) circle(x, y, r float64, c color.Color) {
+	fmt.Fprintf(s.w, `<circle cx="%.1f" cy="%.1f" r="%g" fill="%s"/>`+"\n", x, y, r, svgColor(c))
+}
+
+func (s *svgCanvas) textWidth(text string) float64 {
+	return float64(font.MeasureString(s.face, text)) / 64
+}
+
+func (s *svgCanvas) text(x, y float64, text string, a anchor, c color.Color) {
+	anchors := [...]string{anchorStart: "start", anchorMiddle: "middle", anchorEnd: "end"}
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	fmt.Fprintf(s.w, `<text x="%.1f" y="%.1f" text-anchor="%s" fill="%s">%s</text>`+"\n",
+		x, y, anchors[a], svgColor(c), escaped.String())
+}
+
+// WriteSVG выводит график векторным изображением SVG размером width x height
+func (c *Chart) WriteSVG(w io.Writer, width, height int) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	face, err := loadFace()
+	if err != nil {
+		return err
+	}
+	cv := &svgCanvas{w: bufio.NewWriter(w), face: face}
+	fmt.Fprintf(cv.w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Go, Arial, sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, fontSize)
+	c.draw(cv, float64(width), float64(height))
+	fmt.Fprintln(cv.w, "</svg>")
+	return cv.w.Flush()
+}
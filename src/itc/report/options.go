@@ -0,0 +1,120 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Options настраивают вид таблиц независимо от формата вывода
+type Options struct {
+	Precision int               // знаков после запятой у вещественных чисел; < 0 — формат столбца
+	Widths    map[string]int    // ширина столбца по его ключу
+	Headers   map[string]string // заголовок столбца по его ключу
+}
+
+// DefaultOptions — таблицы выводятся так, как их описал эксперимент
+var DefaultOptions = Options{Precision: -1}
+
+// ParseAssignments разбирает список вида "key=value,key2=value2"
+func ParseAssignments(s string) (map[string]string, error) {
+	res := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return res, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("ожидается ключ=значение, получено %q", part)
+		}
+		res[key] = strings.TrimSpace(value)
+	}
+	return res, nil
+}
+
+// ParseWidths разбирает список ширин столбцов вида "key=10,key2=8"
+func ParseWidths(s string) (map[string]int, error) {
+	assignments, err := ParseAssignments(s)
+	if err != nil {
+		return nil, err
+	}
+	widths := make(map[string]int, len(assignments))
+	for key, value := range assignments {
+		w, err := strconv.Atoi(value)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("ширина столбца %s должна быть неотрицательным целым числом, получено %q", key, value)
+		}
+		widths[key] = w
+	}
+	return widths, nil
+}
+
+// NewWriterWithOptions создаёт Writer для формата f с настройками opts
+func NewWriterWithOptions(w io.Writer, f Format, opts Options) (Writer, error) {
+	inner, err := NewWriter(w, f)
+	if err != nil {
+		return nil, err
+	}
+	return WithOptions(inner, opts), nil
+}
+
+// WithOptions применяет настройки opts к таблицам, передаваемым в w
+func WithOptions(w Writer, opts Options) Writer {
+	if opts.Precision < 0 && len(opts.Widths) == 0 && len(opts.Headers) == 0 {
+		return w
+	}
+	return &configured{Writer: w, opts: opts}
+}
+
+// configured применяет Options к описанию каждой таблицы перед выводом
+type configured struct {
+	Writer
+	opts Options
+}
+
+// isFloatFormat сообщает, что формат выводит вещественное число
+func isFloatFormat(format string) bool {
+	return strings.HasPrefix(format, "%") && strings.ContainsAny(format[len(format)-1:], "fFgGeE")
+}
+
+// floatFormat возвращает формат с заданным числом знаков после запятой
+func (c *configured) floatFormat(format string, value any) string {
+	if c.opts.Precision < 0 {
+		return format
+	}
+	_, isFloat := value.(float64)
+	if isFloatFormat(format) || (format == "" && isFloat) {
+		return fmt.Sprintf("%%.%df", c.opts.Precision)
+	}
+	return format
+}
+
+func (c *configured) BeginTable(t *Table) error {
+	table := *t
+	table.Columns = make([]Column, len(t.Columns))
+	for i, col := range t.Columns {
+		if title, ok := c.opts.Headers[col.Key]; ok {
+			col.Title = title
+		}
+		if width, ok := c.opts.Widths[col.Key]; ok {
+			col.Width = width
+		}
+		col.Format = c.floatFormat(col.Format, nil)
+		table.Columns[i] = col
+	}
+	return c.Writer.BeginTable(&table)
+}
+
+func (c *configured) EndTable(summary ...Field) error {
+	fields := make([]Field, len(summary))
+	for i, f := range summary {
+		if title, ok := c.opts.Headers[f.Key]; ok {
+			f.Title = title
+		}
+		f.Format = c.floatFormat(f.Format, f.Value)
+		fields[i] = f
+	}
+	return c.Writer.EndTable(fields...)
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"fmt"
+	"math"
+)
+
+// RecordedTable — таблица, сохранённая Recorder вместе со строками и итогами
+type RecordedTable struct {
+	Table   Table
+	Rows    [][]any
+	Summary []Field
+}
+
+// Cells возвращает строки таблицы, отформатированные по форматам столбцов
+func (t *RecordedTable) Cells() [][]string {
+	cells := make([][]string, len(t.Rows))
+	for i, row := range t.Rows {
+		cells[i] = make([]string, len(row))
+		for j, v := range row {
+			cells[i][j] = formatCell(v, t.Table.Columns[j].Format)
+		}
+	}
+	return cells
+}
+
+// Column возвращает номер столбца с ключом key или -1
+func (t *RecordedTable) Column(key string) int {
+	for i, c := range t.Table.Columns {
+		if c.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Floats возвращает значения столбца с ключом key как числа; нечисловые
+// значения заменяются на NaN. Второй результат false, если столбца нет.
+func (t *RecordedTable) Floats(key string) ([]float64, bool) {
+	j := t.Column(key)
+	if j < 0 {
+		return nil, false
+	}
+	values := make([]float64, len(t.Rows))
+	for i, row := range t.Rows {
+		switch v := row[j].(type) {
+		case int:
+			values[i] = float64(v)
+		case int64:
+			values[i] = float64(v)
+		case float64:
+			values[i] = v
+		default:
+			values[i] = math.NaN()
+		}
+	}
+	return values, true
+}
+
+// Text возвращает значение итоговой величины, отформатированное по её формату
+func (f Field) Text() string {
+	return formatCell(f.Value, f.Format)
+}
+
+// Recorder сохраняет таблицы в памяти, чтобы затем вывести их в документ
+// или построить по ним графики
+type Recorder struct {
+	Tables []RecordedTable
+	state  tableState
+}
+
+func (r *Recorder) BeginTable(t *Table) error {
+	if err := r.state.begin(t); err != nil {
+		return err
+	}
+	table := *t
+	table.Columns = append([]Column(nil), t.Columns...)
+	r.Tables = append(r.Tables, RecordedTable{Table: table})
+	return nil
+}
+
+func (r *Recorder) WriteRow(values ...any) error {
+	if err := r.state.row(values); err != nil {
+		return err
+	}
+	last := &r.Tables[len(r.Tables)-1]
+	last.Rows = append(last.Rows, append([]any(nil), values...))
+	return nil
+}
+
+func (r *Recorder) EndTable(summary ...Field) error {
+	if _, err := r.state.end(); err != nil {
+		return err
+	}
+	last := &r.Tables[len(r.Tables)-1]
+	last.Summary = append([]Field(nil), summary...)
+	return nil
+}
+
+func (r *Recorder) Close() error {
+	if r.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", r.state.table.Name)
+	}
+	return nil
+}
+
+// Table возвращает сохранённую таблицу с именем name или nil
+func (r *Recorder) Table(name string) *RecordedTable {
+	for i := range r.Tables {
+		if r.Tables[i].Table.Name == name {
+			return &r.Tables[i]
+		}
+	}
+	return nil
+}
+
+// Replay выводит сохранённые таблицы в w по порядку, не закрывая его
+func (r *Recorder) Replay(w Writer) error {
+	for i := range r.Tables {
+		t := &r.Tables[i]
+		if err := w.BeginTable(&t.Table); err != nil {
+			return err
+		}
+		for _, row := range t.Rows {
+			if err := w.WriteRow(row...); err != nil {
+				return err
+			}
+		}
+		if err := w.EndTable(t.Summary...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
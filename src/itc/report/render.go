@@ -0,0 +1,248 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// output накапливает первую ошибку записи, чтобы не проверять каждый вызов
+type output struct {
+	w   io.Writer
+	err error
+}
+
+func (o *output) printf(format string, args ...any) {
+	if o.err == nil {
+		_, o.err = fmt.Fprintf(o.w, format, args...)
+	}
+}
+
+// markdownWriter выводит таблицы GitHub Flavored Markdown: заголовок таблицы —
+// раздел второго уровня, итоговые величины — список после таблицы
+type markdownWriter struct {
+	output
+	state  tableState
+	widths []int
+	tables int
+}
+
+// escapeMarkdown экранирует вертикальную черту, разделяющую ячейки
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func (m *markdownWriter) BeginTable(t *Table) error {
+	if err := m.state.begin(t); err != nil {
+		return err
+	}
+	if m.tables > 0 {
+		m.printf("\n")
+	}
+	m.tables++
+	if t.Title != "" {
+		m.printf("## %s\n\n", t.Title)
+	}
+	m.widths = make([]int, len(t.Columns))
+	header := make([]string, len(t.Columns))
+	rule := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		title := escapeMarkdown(c.Title)
+		m.widths[i] = max(c.Width, utf8.RuneCountInString(title), 3)
+		header[i] = pad(title, m.widths[i], false)
+		rule[i] = strings.Repeat("-", m.widths[i])
+		if c.RightAligned() {
+			rule[i] = rule[i][1:] + ":"
+		}
+	}
+	m.printf("| %s |\n", strings.Join(header, " | "))
+	m.printf("| %s |\n", strings.Join(rule, " | "))
+	return m.err
+}
+
+func (m *markdownWriter) WriteRow(values ...any) error {
+	if err := m.state.row(values); err != nil {
+		return err
+	}
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cell := escapeMarkdown(formatCell(v, m.state.table.Columns[i].Format))
+		cells[i] = pad(cell, m.widths[i], isNumber(v))
+	}
+	m.printf("| %s |\n", strings.Join(cells, " | "))
+	return m.err
+}
+
+func (m *markdownWriter) EndTable(summary ...Field) error {
+	if _, err := m.state.end(); err != nil {
+		return err
+	}
+	if len(summary) > 0 {
+		m.printf("\n")
+	}
+	for _, f := range summary {
+		m.printf("- **%s:** %s\n", f.Title, escapeMarkdown(formatCell(f.Value, f.Format)))
+	}
+	return m.err
+}
+
+func (m *markdownWriter) Close() error {
+	if m.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", m.state.table.Name)
+	}
+	return m.err
+}
+
+// latexWriter выводит каждую таблицу окружением table с tabular внутри;
+// итоговые величины выводятся списком itemize
+type latexWriter struct {
+	output
+	state  tableState
+	tables int
+}
+
+// latexReplacer экранирует специальные символы LaTeX
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`^`, `\^{}`,
+	`_`, `\_`,
+	`%`, `\%`,
+	`~`, `\~{}`,
+)
+
+func escapeLaTeX(s string) string {
+	return latexReplacer.Replace(s)
+}
+
+func (l *latexWriter) BeginTable(t *Table) error {
+	if err := l.state.begin(t); err != nil {
+		return err
+	}
+	if l.tables > 0 {
+		l.printf("\n")
+	}
+	l.tables++
+	spec := make([]string, len(t.Columns))
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		spec[i] = "l"
+		if c.RightAligned() {
+			spec[i] = "r"
+		}
+		header[i] = escapeLaTeX(c.Title)
+	}
+	l.printf("\\begin{table}[htbp]\n\\centering\n")
+	if t.Title != "" {
+		l.printf("\\caption{%s}\n", escapeLaTeX(t.Title))
+	}
+	l.printf("\\begin{tabular}{|%s|}\n\\hline\n", strings.Join(spec, "|"))
+	l.printf("%s \\\\\n\\hline\n", strings.Join(header, " & "))
+	return l.err
+}
+
+func (l *latexWriter) WriteRow(values ...any) error {
+	if err := l.state.row(values); err != nil {
+		return err
+	}
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = escapeLaTeX(formatCell(v, l.state.table.Columns[i].Format))
+	}
+	l.printf("%s \\\\\n", strings.Join(cells, " & "))
+	return l.err
+}
+
+func (l *latexWriter) EndTable(summary ...Field) error {
+	if _, err := l.state.end(); err != nil {
+		return err
+	}
+	l.printf("\\hline\n\\end{tabular}\n\\end{table}\n")
+	if len(summary) > 0 {
+		l.printf("\\begin{itemize}\n")
+		for _, f := range summary {
+			l.printf("\\item %s: %s\n", escapeLaTeX(f.Title), escapeLaTeX(formatCell(f.Value, f.Format)))
+		}
+		l.printf("\\end{itemize}\n")
+	}
+	return l.err
+}
+
+func (l *latexWriter) Close() error {
+	if l.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", l.state.table.Name)
+	}
+	return l.err
+}
+
+// htmlWriter выводит каждую таблицу элементом <table> с подписью <caption>;
+// итоговые величины выводятся списком определений <dl>
+type htmlWriter struct {
+	output
+	state tableState
+}
+
+// htmlCell возвращает открывающий тег ячейки с выравниванием чисел вправо
+func htmlCell(tag string, right bool) string {
+	if right {
+		return "<" + tag + ` style="text-align: right">`
+	}
+	return "<" + tag + ">"
+}
+
+func (h *htmlWriter) BeginTable(t *Table) error {
+	if err := h.state.begin(t); err != nil {
+		return err
+	}
+	h.printf("<table>\n")
+	if t.Title != "" {
+		h.printf("<caption>%s</caption>\n", html.EscapeString(t.Title))
+	}
+	h.printf("<thead>\n<tr>")
+	for _, c := range t.Columns {
+		h.printf("%s%s</th>", htmlCell("th", c.RightAligned()), html.EscapeString(c.Title))
+	}
+	h.printf("</tr>\n</thead>\n<tbody>\n")
+	return h.err
+}
+
+func (h *htmlWriter) WriteRow(values ...any) error {
+	if err := h.state.row(values); err != nil {
+		return err
+	}
+	h.printf("<tr>")
+	for i, v := range values {
+		cell := formatCell(v, h.state.table.Columns[i].Format)
+		h.printf("%s%s</td>", htmlCell("td", isNumber(v)), html.EscapeString(cell))
+	}
+	h.printf("</tr>\n")
+	return h.err
+}
+
+func (h *htmlWriter) EndTable(summary ...Field) error {
+	if _, err := h.state.end(); err != nil {
+		return err
+	}
+	h.printf("</tbody>\n</table>\n")
+	if len(summary) > 0 {
+		h.printf("<dl>\n")
+		for _, f := range summary {
+			h.printf("<dt>%s</dt><dd>%s</dd>\n", html.EscapeString(f.Title), html.EscapeString(formatCell(f.Value, f.Format)))
+		}
+		h.printf("</dl>\n")
+	}
+	return h.err
+}
+
+func (h *htmlWriter) Close() error {
+	if h.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", h.state.table.Name)
+	}
+	return h.err
+}
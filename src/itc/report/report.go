@@ -0,0 +1,173 @@
+// Package report отделяет результаты экспериментов от их представления.
+// Эксперимент описывает таблицу (Table), построчно передаёт значения в Writer
+// и завершает таблицу итоговыми величинами; Writer выводит их в виде
+// текстовой таблицы, Markdown, LaTeX, HTML, таблицы в рамках (tablewriter),
+// JSON, CSV или NDJSON.
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Column — столбец таблицы результатов
+type Column struct {
+	Key    string // машинное имя для JSON, CSV и NDJSON
+	Title  string // заголовок столбца в текстовой таблице
+	Format string // формат значения в текстовой таблице (по умолчанию %v)
+	Width  int    // минимальная ширина столбца в текстовой таблице
+}
+
+// RightAligned сообщает, что столбец содержит числа и выравнивается вправо
+func (c Column) RightAligned() bool {
+	return isFloatFormat(c.Format) || strings.HasSuffix(c.Format, "d")
+}
+
+// Field — итоговая величина таблицы (среднее значение, параметр эксперимента)
+type Field struct {
+	Key    string
+	Title  string
+	Value  any
+	Format string
+}
+
+// Table описывает таблицу результатов одного эксперимента
+type Table struct {
+	Name    string // машинное имя таблицы
+	Title   string // заголовок, выводимый перед текстовой таблицей
+	Columns []Column
+}
+
+// Keys возвращает машинные имена столбцов
+func (t *Table) Keys() []string {
+	keys := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// Writer принимает результаты экспериментов. Таблицы выводятся по очереди:
+// BeginTable, затем WriteRow для каждой строки и EndTable. Close завершает
+// вывод; форматы, которые не умеют выводить данные потоком, пишут всё в Close.
+type Writer interface {
+	BeginTable(t *Table) error
+	WriteRow(values ...any) error
+	EndTable(summary ...Field) error
+	Close() error
+}
+
+// Format — формат вывода результатов
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatLaTeX    Format = "latex"
+	FormatHTML     Format = "html"
+	FormatTerminal Format = "terminal"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatNDJSON   Format = "ndjson"
+)
+
+// Formats — допустимые значения флага формата вывода
+var Formats = []string{
+	string(FormatText), string(FormatMarkdown), string(FormatLaTeX), string(FormatHTML), string(FormatTerminal),
+	string(FormatJSON), string(FormatCSV), string(FormatNDJSON),
+}
+
+// Ext возвращает расширение файла для формата f
+func (f Format) Ext() string {
+	switch f {
+	case FormatMarkdown:
+		return "md"
+	case FormatLaTeX:
+		return "tex"
+	case FormatText, FormatTerminal:
+		return "txt"
+	}
+	return string(f)
+}
+
+// ParseFormat преобразует название формата в Format
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, f) {
+			return Format(f), nil
+		}
+	}
+	return "", fmt.Errorf("неизвестный формат вывода %q (ожидается %s)", name, strings.Join(Formats, ", "))
+}
+
+// NewWriter создаёт Writer для формата f
+func NewWriter(w io.Writer, f Format) (Writer, error) {
+	switch f {
+	case FormatText:
+		return &textWriter{output: output{w: w}}, nil
+	case FormatMarkdown:
+		return &markdownWriter{output: output{w: w}}, nil
+	case FormatLaTeX:
+		return &latexWriter{output: output{w: w}}, nil
+	case FormatHTML:
+		return &htmlWriter{output: output{w: w}}, nil
+	case FormatTerminal:
+		return &terminalWriter{output: output{w: w}}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("неизвестный формат вывода %q", string(f))
+}
+
+// tableState хранит текущую таблицу и проверяет порядок вызовов Writer
+type tableState struct {
+	table *Table
+}
+
+func (s *tableState) begin(t *Table) error {
+	if s.table != nil {
+		return fmt.Errorf("таблица %q не завершена", s.table.Name)
+	}
+	s.table = t
+	return nil
+}
+
+func (s *tableState) row(values []any) error {
+	if s.table == nil {
+		return fmt.Errorf("строка результатов вне таблицы")
+	}
+	if len(values) != len(s.table.Columns) {
+		return fmt.Errorf("таблица %q: ожидалось %d значений в строке, получено %d",
+			s.table.Name, len(s.table.Columns), len(values))
+	}
+	return nil
+}
+
+func (s *tableState) end() (*Table, error) {
+	if s.table == nil {
+		return nil, fmt.Errorf("завершение таблицы, которая не начата")
+	}
+	t := s.table
+	s.table = nil
+	return t, nil
+}
+
+// plainValue приводит значение к виду, пригодному для JSON: бесконечности
+// и NaN не представимы в JSON и заменяются на nil
+func plainValue(v any) any {
+	switch x := v.(type) {
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil
+		}
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
@@ -0,0 +1,247 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// object — JSON-объект, сохраняющий порядок ключей (порядок столбцов таблицы)
+type object struct {
+	keys   []string
+	values []any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(plainValue(o.values[i]))
+		if err != nil {
+			return nil, fmt.Errorf("поле %q: %w", k, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// summaryObject собирает итоговые величины в JSON-объект
+func summaryObject(summary []Field) object {
+	o := object{}
+	for _, f := range summary {
+		o.keys = append(o.keys, f.Key)
+		o.values = append(o.values, f.Value)
+	}
+	return o
+}
+
+// jsonTable — таблица в JSON: строки — объекты с ключами столбцов
+type jsonTable struct {
+	Name    string   `json:"name"`
+	Title   string   `json:"title,omitempty"`
+	Columns []string `json:"columns"`
+	Rows    []object `json:"rows"`
+	Summary *object  `json:"summary,omitempty"`
+}
+
+// jsonWriter накапливает все таблицы и записывает их массивом при Close
+type jsonWriter struct {
+	w      io.Writer
+	state  tableState
+	tables []*jsonTable
+}
+
+func (j *jsonWriter) BeginTable(t *Table) error {
+	if err := j.state.begin(t); err != nil {
+		return err
+	}
+	j.tables = append(j.tables, &jsonTable{Name: t.Name, Title: t.Title, Columns: t.Keys(), Rows: []object{}})
+	return nil
+}
+
+func (j *jsonWriter) WriteRow(values ...any) error {
+	if err := j.state.row(values); err != nil {
+		return err
+	}
+	cur := j.tables[len(j.tables)-1]
+	cur.Rows = append(cur.Rows, object{keys: cur.Columns, values: append([]any(nil), values...)})
+	return nil
+}
+
+func (j *jsonWriter) EndTable(summary ...Field) error {
+	if _, err := j.state.end(); err != nil {
+		return err
+	}
+	if len(summary) > 0 {
+		s := summaryObject(summary)
+		j.tables[len(j.tables)-1].Summary = &s
+	}
+	return nil
+}
+
+func (j *jsonWriter) Close() error {
+	if j.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", j.state.table.Name)
+	}
+	enc := json.NewEncoder(j.w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if j.tables == nil {
+		j.tables = []*jsonTable{}
+	}
+	return enc.Encode(j.tables)
+}
+
+// ndjsonWriter записывает каждую строку отдельным JSON-объектом сразу после
+// её получения. Поле "table" указывает таблицу; итоги таблицы выводятся
+// строкой с полем "summary".
+type ndjsonWriter struct {
+	w     io.Writer
+	state tableState
+}
+
+func (n *ndjsonWriter) encode(v any) error {
+	enc := json.NewEncoder(n.w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+func (n *ndjsonWriter) BeginTable(t *Table) error {
+	return n.state.begin(t)
+}
+
+func (n *ndjsonWriter) WriteRow(values ...any) error {
+	if err := n.state.row(values); err != nil {
+		return err
+	}
+	t := n.state.table
+	return n.encode(object{
+		keys:   append([]string{"table"}, t.Keys()...),
+		values: append([]any{t.Name}, values...),
+	})
+}
+
+func (n *ndjsonWriter) EndTable(summary ...Field) error {
+	t, err := n.state.end()
+	if err != nil {
+		return err
+	}
+	if len(summary) == 0 {
+		return nil
+	}
+	return n.encode(object{
+		keys:   []string{"table", "summary"},
+		values: []any{t.Name, summaryObject(summary)},
+	})
+}
+
+func (n *ndjsonWriter) Close() error {
+	if n.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", n.state.table.Name)
+	}
+	return nil
+}
+
+// csvWriter выводит каждую таблицу блоком CSV: строка с ключами столбцов,
+// затем строки значений. Блоки разделяются пустой строкой; итоги таблицы
+// выводятся отдельным блоком "key,value".
+type csvWriter struct {
+	w      *csv.Writer
+	state  tableState
+	tables int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// csvValue форматирует значение без потери точности
+func csvValue(v any) string {
+	switch x := plainValue(v).(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// separate отделяет очередной блок пустой строкой
+func (c *csvWriter) separate() error {
+	if c.tables > 0 {
+		if err := c.w.Write(nil); err != nil {
+			return err
+		}
+	}
+	c.tables++
+	return nil
+}
+
+func (c *csvWriter) BeginTable(t *Table) error {
+	if err := c.state.begin(t); err != nil {
+		return err
+	}
+	if err := c.separate(); err != nil {
+		return err
+	}
+	return c.w.Write(t.Keys())
+}
+
+func (c *csvWriter) WriteRow(values ...any) error {
+	if err := c.state.row(values); err != nil {
+		return err
+	}
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = csvValue(v)
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	// Потоковый вывод: строка попадает в выходной поток сразу
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) EndTable(summary ...Field) error {
+	if _, err := c.state.end(); err != nil {
+		return err
+	}
+	if len(summary) > 0 {
+		if err := c.separate(); err != nil {
+			return err
+		}
+		if err := c.w.Write([]string{"key", "value"}); err != nil {
+			return err
+		}
+		for _, f := range summary {
+			if err := c.w.Write([]string{f.Key, csvValue(f.Value)}); err != nil {
+				return err
+			}
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	if c.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", c.state.table.Name)
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,95 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// terminalWriter выводит таблицы в рамках с помощью tablewriter. Ширина
+// столбцов вычисляется по всем строкам, поэтому таблица выводится целиком
+// при EndTable.
+type terminalWriter struct {
+	output
+	state  tableState
+	rows   [][]string
+	tables int
+}
+
+func (t *terminalWriter) BeginTable(table *Table) error {
+	if err := t.state.begin(table); err != nil {
+		return err
+	}
+	t.rows = t.rows[:0]
+	return nil
+}
+
+func (t *terminalWriter) WriteRow(values ...any) error {
+	if err := t.state.row(values); err != nil {
+		return err
+	}
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = formatCell(v, t.state.table.Columns[i].Format)
+	}
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+func (t *terminalWriter) EndTable(summary ...Field) error {
+	table, err := t.state.end()
+	if err != nil {
+		return err
+	}
+	if t.tables > 0 {
+		t.printf("\n")
+	}
+	t.tables++
+	if table.Title != "" {
+		t.printf("%s\n", table.Title)
+	}
+
+	header := make([]any, len(table.Columns))
+	aligns := make([]tw.Align, len(table.Columns))
+	widths := tw.NewMapper[int, int]()
+	for i, c := range table.Columns {
+		header[i] = c.Title
+		aligns[i] = tw.AlignLeft
+		if c.RightAligned() {
+			aligns[i] = tw.AlignRight
+		}
+		if c.Width > 0 {
+			widths.Set(i, max(c.Width, len([]rune(c.Title)))+2)
+		}
+	}
+	tbl := tablewriter.NewTable(t.w,
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+		tablewriter.WithRowAlignmentConfig(tw.CellAlignment{PerColumn: aligns}),
+		tablewriter.WithColumnWidths(widths),
+	)
+	tbl.Header(header...)
+	for _, row := range t.rows {
+		if err := tbl.Append(row); err != nil {
+			return err
+		}
+	}
+	if err := tbl.Render(); err != nil {
+		return err
+	}
+
+	if len(summary) > 0 {
+		t.printf("\n")
+	}
+	for _, f := range summary {
+		t.printf("%s: %s\n", f.Title, formatCell(f.Value, f.Format))
+	}
+	return t.err
+}
+
+func (t *terminalWriter) Close() error {
+	if t.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", t.state.table.Name)
+	}
+	return t.err
+}
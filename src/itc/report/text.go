@@ -0,0 +1,108 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// textWriter выводит результаты таблицами в стиле Markdown по мере поступления строк
+type textWriter struct {
+	output
+	state  tableState
+	widths []int
+	tables int
+}
+
+// pad дополняет строку пробелами до ширины width; числа выравниваются вправо
+func pad(s string, width int, right bool) string {
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return s
+	}
+	if right {
+		return strings.Repeat(" ", n) + s
+	}
+	return s + strings.Repeat(" ", n)
+}
+
+// formatCell форматирует значение ячейки по формату столбца. Формат
+// массива чисел применяется к каждому элементу.
+func formatCell(v any, format string) string {
+	if format == "" {
+		format = "%v"
+	}
+	if values, ok := v.([]float64); ok {
+		strs := make([]string, len(values))
+		for i, x := range values {
+			strs[i] = fmt.Sprintf(format, x)
+		}
+		return "[" + strings.Join(strs, ", ") + "]"
+	}
+	return fmt.Sprintf(format, v)
+}
+
+// isNumber сообщает, нужно ли выравнивать значение по правому краю
+func isNumber(v any) bool {
+	switch v.(type) {
+	case int, int64, float64:
+		return true
+	}
+	return false
+}
+
+func (t *textWriter) BeginTable(table *Table) error {
+	if err := t.state.begin(table); err != nil {
+		return err
+	}
+	if t.tables > 0 {
+		t.printf("\n")
+	}
+	t.tables++
+	if table.Title != "" {
+		t.printf("%s\n", table.Title)
+	}
+	t.widths = make([]int, len(table.Columns))
+	header := make([]string, len(table.Columns))
+	rule := make([]string, len(table.Columns))
+	for i, c := range table.Columns {
+		t.widths[i] = max(c.Width, utf8.RuneCountInString(c.Title))
+		header[i] = pad(c.Title, t.widths[i], false)
+		rule[i] = strings.Repeat("-", t.widths[i]+2)
+	}
+	t.printf("| %s |\n", strings.Join(header, " | "))
+	t.printf("|%s|\n", strings.Join(rule, "|"))
+	return t.err
+}
+
+func (t *textWriter) WriteRow(values ...any) error {
+	if err := t.state.row(values); err != nil {
+		return err
+	}
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = pad(formatCell(v, t.state.table.Columns[i].Format), t.widths[i], isNumber(v))
+	}
+	t.printf("| %s |\n", strings.Join(cells, " | "))
+	return t.err
+}
+
+func (t *textWriter) EndTable(summary ...Field) error {
+	if _, err := t.state.end(); err != nil {
+		return err
+	}
+	if len(summary) > 0 {
+		t.printf("\n")
+	}
+	for _, f := range summary {
+		t.printf("%s: %s\n", f.Title, formatCell(f.Value, f.Format))
+	}
+	return t.err
+}
+
+func (t *textWriter) Close() error {
+	if t.state.table != nil {
+		return fmt.Errorf("таблица %q не завершена", t.state.table.Name)
+	}
+	return t.err
+}
@@ -2,4 +2,21 @@ module lab_1
 
 go 1.25.1
 
-require gonum.org/v1/gonum v0.16.0
+require (
+	gonum.org/v1/gonum v0.16.0
+	itc v0.0.0
+)
+
+require (
+	github.com/fatih/color v1.15.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/olekukonko/errors v1.1.0 // indirect
+	github.com/olekukonko/ll v0.0.9 // indirect
+	github.com/olekukonko/tablewriter v1.1.0 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+)
+
+replace itc => ../itc
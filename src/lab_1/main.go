@@ -1,40 +1,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
+	"os"
 	"strings"
 	"time"
 
 	"gonum.org/v1/gonum/stat"
+
+	"itc/infotheory"
+	"itc/report"
 )
 
 func entropy(probabilities []float64) float64 {
-	var h float64
-	for _, p := range probabilities {
-		if p > 0 {
-			h += p * math.Log2(p)
-		}
-	}
-	return -h
+	return infotheory.Entropy(probabilities)
 }
 
+// source — закон распределения вероятностей источника (флаг -source)
+var source = infotheory.DefaultSource
+
+// rng — генератор случайных чисел для всех экспериментов
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// out — вывод результатов экспериментов в формате, заданном флагом -format
+var out report.Writer
+
 func generateProbabilities(n int) ([]float64, error) {
-	if n <= 0 {
-		return nil, fmt.Errorf("число вероятностей должно быть больше 0")
-	}
-	rand.Seed(time.Now().UnixNano())
-	probs := make([]float64, n)
-	sum := 0.0
-	for i := 0; i < n; i++ {
-		probs[i] = rand.Float64()
-		sum += probs[i]
-	}
-	for i := 0; i < n; i++ {
-		probs[i] /= sum
-	}
-	return probs, nil
+	return source.Generate(n, rng)
 }
 
 func maxEntropy(n int) float64 {
@@ -44,35 +40,352 @@ func maxEntropy(n int) float64 {
 func runExperiment(n int, experimentNum int) (float64, float64, error) {
 	probs, err := generateProbabilities(n)
 	if err != nil {
-		fmt.Printf("Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
 		return 0, 0, err
 	}
 	avgEntropy := entropy(probs)
 	maxEnt := maxEntropy(n)
 
-	// Преобразуем массив вероятностей в строку с округлением
-	probStrs := make([]string, len(probs))
-	for i, p := range probs {
-		probStrs[i] = fmt.Sprintf("%.4f", p)
+	// Табличный вывод; вероятности округляются только в текстовой таблице
+	if err := out.WriteRow(experimentNum, n, probs, avgEntropy, maxEnt); err != nil {
+		return 0, 0, err
 	}
-	probJoined := "[" + strings.Join(probStrs, ", ") + "]"
-
-	// Табличный вывод
-	fmt.Printf("| %3d | %3d | %-110s | %10.4f | %10.4f |\n",
-		experimentNum, n, probJoined, avgEntropy, maxEnt)
 
 	return avgEntropy, maxEnt, nil
 }
 
+// formatSequence преобразует последовательность символов в строку, ограничивая её длину
+func formatSequence(seq []int, limit int) string {
+	strs := make([]string, 0, limit)
+	for i, a := range seq {
+		if i == limit {
+			strs = append(strs, "...")
+			break
+		}
+		strs = append(strs, fmt.Sprintf("%d", a))
+	}
+	return strings.Join(strs, " ")
+}
+
+// runMarkovExperiment строит случайный марковский источник порядка order над
+// алфавитом из n символов и выводит его энтропию и избыточность
+func runMarkovExperiment(n, order, experimentNum int) (infotheory.MarkovStats, error) {
+	chain, err := infotheory.RandomMarkovSource(order, n, rng)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return infotheory.MarkovStats{}, err
+	}
+	stats, err := chain.Analyze()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return infotheory.MarkovStats{}, err
+	}
+	seq, err := chain.Generate(40, rng)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return infotheory.MarkovStats{}, err
+	}
+
+	err = out.WriteRow(experimentNum, n, order, stats.EntropyRate, stats.MemorylessEntropy, stats.MaxEntropy,
+		stats.Redundancy, formatSequence(seq, 20))
+	return stats, err
+}
+
+// runEmpiricalAnalysis оценивает энтропии порядков 0..maxOrder для файла
+// или стандартного ввода (path == "-") и выводит их в виде таблицы
+func runEmpiricalAnalysis(path, unitName string, alphabet, maxOrder int) error {
+	unit, err := infotheory.ParseTokenUnit(unitName)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	res, err := infotheory.AnalyzeReader(r, unit, alphabet, maxOrder)
+	if err != nil {
+		return err
+	}
+
+	table := empiricalTable
+	table.Title = fmt.Sprintf("Источник: %s, единица: %s", path, res.Unit)
+	if err := out.BeginTable(&table); err != nil {
+		return err
+	}
+	for _, o := range res.Orders {
+		if err := out.WriteRow(o.Order, o.Blocks, o.BlockEntropy, o.Conditional, o.Redundancy); err != nil {
+			return err
+		}
+	}
+	return out.EndTable(
+		report.Field{Key: "tokens", Title: "Символов", Value: res.Tokens},
+		report.Field{Key: "observed", Title: "Различных символов", Value: res.Observed},
+		report.Field{Key: "alphabet", Title: "Алфавит", Value: res.Alphabet},
+		report.Field{Key: "max_entropy", Title: "Макс. H, бит", Value: res.MaxEntropy, Format: "%.4f"},
+	)
+}
+
+// runEstimationExperiment генерирует выборку объёма samples из источника с n
+// символами и сравнивает истинную энтропию с её оценками по выборке.
+// Для оценки estimatorName выводится бутстрэп-интервал H ± Δ.
+func runEstimationExperiment(n, samples, resamples int, estimatorName string, experimentNum int) error {
+	probs, err := generateProbabilities(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return err
+	}
+	counts, err := infotheory.Counts(infotheory.Sample(probs, samples, rng), n)
+	if err != nil {
+		return err
+	}
+
+	estimators := []infotheory.EstimatorFunc{
+		infotheory.PluginEntropy,
+		infotheory.MillerMadowEntropy,
+		infotheory.ChaoShenEntropy,
+		infotheory.GrassbergerEntropy,
+		infotheory.NSBEntropy(n),
+	}
+	estimates := make([]float64, len(estimators))
+	for i, est := range estimators {
+		if estimates[i], err = est(counts); err != nil {
+			fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+			return err
+		}
+	}
+
+	selected, err := infotheory.ParseEstimator(estimatorName, n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return err
+	}
+	interval, err := infotheory.BootstrapInterval(counts, selected, resamples, 0.95, rng)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return err
+	}
+
+	return out.WriteRow(experimentNum, n, samples, entropy(probs),
+		estimates[0], estimates[1], estimates[2], estimates[3], estimates[4],
+		interval.Estimate, interval.HalfWidth(), interval.Lower, interval.Upper)
+}
+
+// runGeneralizedExperiment выводит энтропии Реньи, Цаллиса и расхождение
+// с равномерным распределением для случайного источника из n символов
+func runGeneralizedExperiment(n int, unit infotheory.Unit, experimentNum int) error {
+	probs, err := generateProbabilities(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return err
+	}
+	uniform := make([]float64, n)
+	for i := range uniform {
+		uniform[i] = 1 / float64(n)
+	}
+
+	orders := []float64{1, 0, 0.5, 2, math.Inf(1)}
+	values := make([]float64, 0, len(orders)+2)
+	for _, alpha := range orders {
+		h, err := infotheory.RenyiEntropy(probs, alpha, unit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+			return err
+		}
+		values = append(values, h)
+	}
+	tsallis, err := infotheory.TsallisEntropy(probs, 2)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return err
+	}
+	kl, err := infotheory.KLDivergence(probs, uniform, unit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Эксперимент %d: Ошибка: %v\n", experimentNum, err)
+		return err
+	}
+	values = append(values, tsallis, kl)
+
+	row := []any{experimentNum, n}
+	for _, v := range values {
+		row = append(row, v)
+	}
+	return out.WriteRow(row...)
+}
+
+// runSkewnessSweep показывает, как энтропия источника из n символов меняется
+// от 0 до log2(n) при изменении параметра закона распределения
+func runSkewnessSweep(n int) error {
+	sweep := []string{
+		"degenerate",
+		"dirichlet:0.01", "dirichlet:0.1", "dirichlet:0.5", "dirichlet:1", "dirichlet:10", "dirichlet:100",
+		"zipf:4", "zipf:2", "zipf:1", "zipf:0.5",
+		"geometric:0.9", "geometric:0.5", "geometric:0.1",
+		"binomial:0.5",
+		"random",
+		"uniform",
+	}
+	maxEnt := maxEntropy(n)
+	for _, text := range sweep {
+		spec, err := infotheory.ParseSourceSpec(text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Распределение %s: Ошибка: %v\n", text, err)
+			continue
+		}
+		probs, err := spec.Generate(n, rng)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Распределение %s: Ошибка: %v\n", text, err)
+			continue
+		}
+		h := entropy(probs)
+		if err := out.WriteRow(spec.String(), n, h, maxEnt, h/maxEnt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Таблицы результатов; заголовки с параметрами задаются перед выводом
+var (
+	entropyTable = report.Table{
+		Name: "entropy",
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "probabilities", Title: "Вероятности", Format: "%.4f", Width: 110},
+			{Key: "entropy", Title: "Средн. H", Format: "%.4f", Width: 10},
+			{Key: "max_entropy", Title: "Макс. H", Format: "%.4f", Width: 10},
+		},
+	}
+	sweepTable = report.Table{
+		Name: "sweep",
+		Columns: []report.Column{
+			{Key: "source", Title: "Распределение", Width: 16},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "entropy", Title: "H", Format: "%.4f", Width: 10},
+			{Key: "max_entropy", Title: "Макс. H", Format: "%.4f", Width: 10},
+			{Key: "ratio", Title: "H/Hmax", Format: "%.4f", Width: 7},
+		},
+	}
+	markovTable = report.Table{
+		Name:  "markov",
+		Title: "Марковские источники: H∞ — энтропия источника, H0 — энтропия без учёта памяти",
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "order", Title: "k", Width: 3},
+			{Key: "entropy_rate", Title: "H∞", Format: "%.4f", Width: 10},
+			{Key: "memoryless_entropy", Title: "H0", Format: "%.4f", Width: 10},
+			{Key: "max_entropy", Title: "Макс. H", Format: "%.4f", Width: 10},
+			{Key: "redundancy", Title: "Избыточность", Format: "%.4f", Width: 12},
+			{Key: "sequence", Title: "Последовательность", Width: 50},
+		},
+	}
+	generalizedTable = report.Table{
+		Name: "generalized",
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "shannon", Title: "Шеннон", Format: "%.4f", Width: 9},
+			{Key: "hartley", Title: "Хартли H0", Format: "%.4f", Width: 9},
+			{Key: "renyi_0_5", Title: "H_1/2", Format: "%.4f", Width: 9},
+			{Key: "renyi_2", Title: "H_2", Format: "%.4f", Width: 9},
+			{Key: "min_entropy", Title: "H∞ (мин.)", Format: "%.4f", Width: 9},
+			{Key: "tsallis_2", Title: "S2", Format: "%.4f", Width: 9},
+			{Key: "kl_uniform", Title: "D(p||u)", Format: "%.4f", Width: 9},
+		},
+	}
+	estimationTable = report.Table{
+		Name: "estimate",
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Exp", Width: 3},
+			{Key: "n", Title: "n", Width: 3},
+			{Key: "samples", Title: "N", Width: 4},
+			{Key: "entropy", Title: "H", Format: "%.4f", Width: 8},
+			{Key: "plugin", Title: "Plug-in", Format: "%.4f", Width: 8},
+			{Key: "miller_madow", Title: "MM", Format: "%.4f", Width: 8},
+			{Key: "chao_shen", Title: "CS", Format: "%.4f", Width: 8},
+			{Key: "grassberger", Title: "G", Format: "%.4f", Width: 8},
+			{Key: "nsb", Title: "NSB", Format: "%.4f", Width: 8},
+			{Key: "estimate", Title: "Оценка H", Format: "%.4f", Width: 8},
+			{Key: "half_width", Title: "± Δ", Format: "%.4f", Width: 6},
+			{Key: "lower", Title: "Нижняя", Format: "%.4f", Width: 6},
+			{Key: "upper", Title: "Верхняя", Format: "%.4f", Width: 6},
+		},
+	}
+	empiricalTable = report.Table{
+		Name: "empirical",
+		Columns: []report.Column{
+			{Key: "order", Title: "Порядок", Width: 7},
+			{Key: "blocks", Title: "Блоков", Width: 7},
+			{Key: "block_entropy", Title: "H блоков, бит", Format: "%.4f", Width: 13},
+			{Key: "conditional_entropy", Title: "H(X|k), бит", Format: "%.4f", Width: 11},
+			{Key: "redundancy", Title: "Избыточность", Format: "%.4f", Width: 12},
+		},
+	}
+)
+
+// fatal выводит ошибку и завершает программу
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "Ошибка:", err)
+	os.Exit(1)
+}
+
 func main() {
-	// Заголовок таблицы
-	fmt.Println("| Exp |  n  | Вероятности                                                                                                    |  Средн. H  |  Макс. H   |")
-	fmt.Println("|-----|-----|----------------------------------------------------------------------------------------------------------------|------------|------------|")
+	file := flag.String("file", "", "файл для оценки эмпирической энтропии (\"-\" — стандартный ввод)")
+	unit := flag.String("unit", "bytes", "единица разбиения: bytes, runes или words")
+	order := flag.Int("order", 3, "максимальный порядок условной энтропии")
+	alphabet := flag.Int("alphabet", 0, "размер алфавита для расчёта избыточности (0 — число различных символов)")
+	samples := flag.Int("samples", 50, "объём выборки для оценки энтропии")
+	resamples := flag.Int("bootstrap", 200, "число бутстрэп-выборок для доверительного интервала")
+	base := flag.String("base", "bits", "единица измерения обобщённых энтропий: bits, nats или hartleys")
+	estimator := flag.String("estimator", "nsb", "оценка с доверительным интервалом: "+strings.Join(infotheory.EstimatorNames, ", "))
+	sourceText := flag.String("source", infotheory.DefaultSource.String(), "закон распределения источника: "+strings.Join(infotheory.SourceKinds, ", "))
+	formatName := flag.String("format", string(report.FormatText), "формат вывода: "+strings.Join(report.Formats, ", "))
+	flag.Parse()
+
+	format, err := report.ParseFormat(*formatName)
+	if err != nil {
+		fatal(err)
+	}
+	if out, err = report.NewWriter(os.Stdout, format); err != nil {
+		fatal(err)
+	}
+
+	if *file != "" {
+		if err := runEmpiricalAnalysis(*file, *unit, *alphabet, *order); err != nil {
+			fatal(err)
+		}
+		if err := out.Close(); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if source, err = infotheory.ParseSourceSpec(*sourceText); err != nil {
+		fatal(err)
+	}
+	infoUnit, err := infotheory.ParseUnit(*base)
+	if err != nil {
+		fatal(err)
+	}
+	if _, err := infotheory.ParseEstimator(*estimator, 1); err != nil {
+		fatal(err)
+	}
 
 	ns := []int{8, 9, 10, 11, 12, 13}
 	avgAvgEntropy := []float64{}
 	avgMaxEnt := []float64{}
 
+	if err := out.BeginTable(&entropyTable); err != nil {
+		fatal(err)
+	}
 	for i, n := range ns {
 		avgEntropy, maxEnt, err := runExperiment(n, i+1)
 		if err == nil {
@@ -80,8 +393,71 @@ func main() {
 			avgMaxEnt = append(avgMaxEnt, maxEnt)
 		}
 	}
+	err = out.EndTable(report.Field{Key: "mean_entropy", Title: "Список Средн. H", Value: stat.Mean(avgAvgEntropy, nil)})
+	if err != nil {
+		fatal(err)
+	}
 
-	// Вывод списков
-	fmt.Println("\nСписок Средн. H:", stat.Mean(avgAvgEntropy, nil))
+	// Энтропия при разной неравномерности распределения
+	sweepN := ns[len(ns)-1]
+	sweepTable.Title = fmt.Sprintf("Зависимость энтропии от закона распределения (n = %d)", sweepN)
+	if err := out.BeginTable(&sweepTable); err != nil {
+		fatal(err)
+	}
+	if err := runSkewnessSweep(sweepN); err != nil {
+		fatal(err)
+	}
+	if err := out.EndTable(); err != nil {
+		fatal(err)
+	}
 
+	// Источники с памятью (марковские цепи порядка k)
+	if err := out.BeginTable(&markovTable); err != nil {
+		fatal(err)
+	}
+	orders := []int{1, 2}
+	exp := 1
+	for _, order := range orders {
+		for _, n := range ns {
+			if _, err := runMarkovExperiment(n, order, exp); err != nil {
+				fatal(err)
+			}
+			exp++
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		fatal(err)
+	}
+
+	// Обобщённые энтропии
+	generalizedTable.Title = fmt.Sprintf("Обобщённые энтропии (%s): H_α — энтропия Реньи порядка α, S2 — энтропия Цаллиса, D(p||u) — расхождение с равномерным", infoUnit)
+	if err := out.BeginTable(&generalizedTable); err != nil {
+		fatal(err)
+	}
+	for i, n := range ns {
+		if err := runGeneralizedExperiment(n, infoUnit, i+1); err != nil {
+			fatal(err)
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		fatal(err)
+	}
+
+	// Оценки энтропии по выборке ограниченного объёма
+	estimationTable.Title = fmt.Sprintf("Оценки энтропии по выборке (интервал 95%% для оценки %s)", *estimator)
+	if err := out.BeginTable(&estimationTable); err != nil {
+		fatal(err)
+	}
+	for i, n := range ns {
+		if err := runEstimationExperiment(n, *samples, *resamples, *estimator, i+1); err != nil {
+			fatal(err)
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		fatal(err)
+	}
+
+	if err := out.Close(); err != nil {
+		fatal(err)
+	}
 }
@@ -2,7 +2,7 @@ module Lab_2
 
 go 1.25.1
 
-require github.com/olekukonko/tablewriter v1.1.0
+require itc v0.0.0
 
 require (
 	github.com/fatih/color v1.15.0 // indirect
@@ -11,6 +11,9 @@ require (
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/olekukonko/errors v1.1.0 // indirect
 	github.com/olekukonko/ll v0.0.9 // indirect
+	github.com/olekukonko/tablewriter v1.1.0 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
 	golang.org/x/sys v0.12.0 // indirect
 )
+
+replace itc => ../itc
@@ -1,28 +1,26 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
 	"math/rand"
+	"os"
+	"strings"
 	"time"
+
+	"itc/infotheory"
+	"itc/report"
 )
 
+// Закон распределения вероятностей источника (флаг -source)
+var source = infotheory.DefaultSource
+
+var sourceRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
 // Вероятности дискретных сообщений
 func generateProbabilities(n int) ([]float64, error) {
-	if n <= 0 {
-		return nil, fmt.Errorf("число вероятностей должно быть больше 0")
-	}
-	rand.Seed(time.Now().UnixNano())
-	probs := make([]float64, n)
-	sum := 0.0
-	for i := 0; i < n; i++ {
-		probs[i] = rand.Float64()
-		sum += probs[i]
-	}
-	for i := 0; i < n; i++ {
-		probs[i] /= sum
-	}
-	return probs, nil
+	return source.Generate(n, sourceRand)
 }
 
 // Вероятности достоверности сообщения
@@ -41,56 +39,52 @@ func generateProbCorrect(n int, start, end float64) ([]float64, error) {
 
 // Матрица вероятностей - если на входе Xi а на выходе Yj
 func generateConditionalMatrix(n int, probsRight []float64) ([][]float64, error) {
-	if n <= 0 {
-		return nil, fmt.Errorf("размер матрицы должен быть больше 0")
-	}
 	if len(probsRight) != n {
-		return nil, fmt.Errorf("длина массива probsRight должна быть равна %d", n)
+		return nil, infotheory.DimensionError("длина массива probsRight", n, len(probsRight))
 	}
-
-	matrix := make([][]float64, n)
-	for i := range matrix {
-		matrix[i] = make([]float64, n)
+	matrix, err := infotheory.SymmetricChannel(probsRight)
+	if err != nil {
+		return nil, err
 	}
-
-	for i := 0; i < n; i++ {
-		if probsRight[i] < 0 || probsRight[i] > 1 {
-			return nil, fmt.Errorf("вероятность probsRight[%d] = %f вне диапазона [0,1]", i, probsRight[i])
-		}
-		matrix[i][i] = probsRight[i]
-		if n > 1 {
-			remainingProb := (1.0 - probsRight[i]) / float64(n-1)
-			for j := 0; j < n; j++ {
-				if j != i {
-					matrix[i][j] = remainingProb
-				}
-			}
-		}
-	}
-	return matrix, nil
+	return matrix.Matrix(), nil
 }
 
 // Вероятности появления выходных символов Xi с учётом возможных ошибок
 func calculateOutputProbabilities(n int, inputProbs []float64, condMatrix [][]float64) ([]float64, error) {
-	if n <= 0 || len(inputProbs) != n || len(condMatrix) != n || len(condMatrix[0]) != n {
-		return nil, fmt.Errorf("некорректные размеры данных")
+	input, channel, err := channelModel(n, inputProbs, condMatrix)
+	if err != nil {
+		return nil, err
 	}
+	output, err := infotheory.OutputDistribution(input, channel)
+	if err != nil {
+		return nil, err
+	}
+	return output.Probs(), nil
+}
 
-	outputProbs := make([]float64, n)
-	for j := 0; j < n; j++ {
-		sum := 0.0
-		for i := 0; i < n; i++ {
-			sum += inputProbs[i] * condMatrix[i][j]
-		}
-		outputProbs[j] = sum
+// Проверка распределения вероятностей и матрицы канала размера n x n
+func channelModel(n int, probs []float64, condMatrix [][]float64) (infotheory.Distribution, infotheory.StochasticMatrix, error) {
+	if err := infotheory.CheckSquare("матрица канала", condMatrix, n); err != nil {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, err
+	}
+	if len(probs) != n {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, infotheory.DimensionError("число вероятностей", n, len(probs))
 	}
-	return outputProbs, nil
+	dist, err := infotheory.NewDistribution(probs)
+	if err != nil {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, err
+	}
+	channel, err := infotheory.NewStochasticMatrix(condMatrix)
+	if err != nil {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, err
+	}
+	return dist, channel, nil
 }
 
 // Это таблица, которая показывает, как часто конкретная пара "входной символ + выходной символ" встречается вместе.
 func calculateJointProbabilityMatrix(n int, outputProbs []float64, condMatrix [][]float64) ([][]float64, error) {
-	if n <= 0 || len(outputProbs) != n || len(condMatrix) != n || len(condMatrix[0]) != n {
-		return nil, fmt.Errorf("некорректные размеры данных")
+	if _, _, err := channelModel(n, outputProbs, condMatrix); err != nil {
+		return nil, err
 	}
 
 	jointMatrix := make([][]float64, n)
@@ -105,22 +99,53 @@ func calculateJointProbabilityMatrix(n int, outputProbs []float64, condMatrix []
 
 // Стадартная функция энтропии
 func calculateEntropy(n int, probs []float64) (float64, error) {
-	if n <= 0 || len(probs) != n {
-		return 0, fmt.Errorf("некорректные входные данные")
+	if len(probs) != n {
+		return 0, infotheory.DimensionError("число вероятностей", n, len(probs))
 	}
-	entropy := 0.0
-	for i := 0; i < n; i++ {
-		if probs[i] > 0 {
-			entropy -= probs[i] * math.Log2(probs[i])
-		}
+	return infotheory.ShannonEntropy(probs, infotheory.Bits)
+}
+
+// Расхождения между распределениями входных и выходных символов канала
+type divergences struct {
+	KL, Cross, JS, TV, Hellinger float64
+}
+
+func calculateDivergences(inputProbs, outputProbs []float64, unit infotheory.Unit) (divergences, error) {
+	var d divergences
+	var err error
+	if d.KL, err = infotheory.KLDivergence(inputProbs, outputProbs, unit); err != nil {
+		return d, err
 	}
-	return entropy, nil
+	if d.Cross, err = infotheory.CrossEntropy(inputProbs, outputProbs, unit); err != nil {
+		return d, err
+	}
+	if d.JS, err = infotheory.JensenShannonDivergence(inputProbs, outputProbs, unit); err != nil {
+		return d, err
+	}
+	if d.TV, err = infotheory.TotalVariation(inputProbs, outputProbs); err != nil {
+		return d, err
+	}
+	if d.Hellinger, err = infotheory.HellingerDistance(inputProbs, outputProbs); err != nil {
+		return d, err
+	}
+	return d, nil
 }
 
 // Условная энтропия выходного сообщения
 func calculateConditionalEntropy(n int, jointMatrix [][]float64, outputProbs []float64) (float64, error) {
-	if n <= 0 || len(jointMatrix) != n || len(jointMatrix[0]) != n || len(outputProbs) != n {
-		return 0, fmt.Errorf("некорректные размеры данных")
+	if err := infotheory.CheckSquare("совместная матрица", jointMatrix, n); err != nil {
+		return 0, err
+	}
+	for _, row := range jointMatrix {
+		if err := infotheory.CheckNonNegative("совместная матрица", row); err != nil {
+			return 0, err
+		}
+	}
+	if len(outputProbs) != n {
+		return 0, infotheory.DimensionError("число выходных вероятностей", n, len(outputProbs))
+	}
+	if _, err := infotheory.NewDistribution(outputProbs); err != nil {
+		return 0, err
 	}
 
 	entropy := 0.0
@@ -135,60 +160,135 @@ func calculateConditionalEntropy(n int, jointMatrix [][]float64, outputProbs []f
 	return entropy, nil
 }
 
-func runExperiment(itr, n int) {
-	// Заголовок таблицы
-	fmt.Println("+----------+---------------------+-------------------------------+------------------------------------+")
-	fmt.Println("| Итерация | Энтропия H(X), бит  | Условная энтропия H(X|Y), бит | Количество информации I(X;Y), бит  |")
-	fmt.Println("+----------+---------------------+-------------------------------+------------------------------------+")
+// Таблицы результатов: энтропии канала и расхождения между X и Y
+var (
+	channelTable = report.Table{
+		Name: "channel",
+		Columns: []report.Column{
+			{Key: "iteration", Title: "Итерация", Width: 8},
+			{Key: "entropy", Title: "Энтропия H(X), бит", Format: "%.4f", Width: 19},
+			{Key: "conditional_entropy", Title: "Условная энтропия H(X|Y), бит", Format: "%.4f", Width: 29},
+			{Key: "information", Title: "Количество информации I(X;Y), бит", Format: "%.4f", Width: 34},
+		},
+	}
+	divergenceTable = report.Table{
+		Name: "divergences",
+		Columns: []report.Column{
+			{Key: "iteration", Title: "Итерация", Width: 8},
+			{Key: "kl", Title: "D(X||Y)", Format: "%.4f", Width: 10},
+			{Key: "cross_entropy", Title: "CE(X||Y)", Format: "%.4f", Width: 10},
+			{Key: "jensen_shannon", Title: "JS(X,Y)", Format: "%.4f", Width: 10},
+			{Key: "total_variation", Title: "TV", Format: "%.4f", Width: 10},
+			{Key: "hellinger", Title: "Хеллингер", Format: "%.4f", Width: 10},
+		},
+	}
+)
+
+func runExperiment(out report.Writer, itr, n int, unit infotheory.Unit) error {
+	if err := out.BeginTable(&channelTable); err != nil {
+		return err
+	}
 
 	// Выполнение итераций и сбор данных
+	allDivergences := make([]divergences, 0, itr)
 	for i := 0; i < itr; i++ {
 		probs, err := generateProbabilities(n)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("итерация %d: %w", i+1, err)
 		}
 
 		probsRight, err := generateProbCorrect(n, 0.7, 1)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("итерация %d: %w", i+1, err)
 		}
 
 		matrix, err := generateConditionalMatrix(n, probsRight)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("итерация %d: %w", i+1, err)
 		}
 
 		outputProbs, err := calculateOutputProbabilities(n, probs, matrix)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("итерация %d: %w", i+1, err)
 		}
 
 		jointProbs, err := calculateJointProbabilityMatrix(n, outputProbs, matrix)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("итерация %d: %w", i+1, err)
 		}
 
 		entropy, err := calculateEntropy(n, probs)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("итерация %d: %w", i+1, err)
 		}
 
 		conditionalEntropy, err := calculateConditionalEntropy(n, jointProbs, outputProbs)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("итерация %d: %w", i+1, err)
+		}
+
+		divs, err := calculateDivergences(probs, outputProbs, unit)
+		if err != nil {
+			return fmt.Errorf("итерация %d: %w", i+1, err)
 		}
+		allDivergences = append(allDivergences, divs)
 
-		// Форматирование строки таблицы
-		fmt.Printf("| %8d | %19.4f | %29.4f | %34.4f |\n",
-			i+1, entropy, conditionalEntropy, entropy-conditionalEntropy)
+		if err := out.WriteRow(i+1, entropy, conditionalEntropy, entropy-conditionalEntropy); err != nil {
+			return err
+		}
+	}
+	if err := out.EndTable(); err != nil {
+		return err
 	}
 
-	// Нижняя граница таблицы
-	fmt.Println("+----------+---------------------+-------------------------------+------------------------------------+")
+	// Насколько распределение на выходе канала отличается от распределения на входе
+	divergenceTable.Title = fmt.Sprintf("Расхождения между распределениями X и Y (%s)", unit)
+	if err := out.BeginTable(&divergenceTable); err != nil {
+		return err
+	}
+	for i, d := range allDivergences {
+		if err := out.WriteRow(i+1, d.KL, d.Cross, d.JS, d.TV, d.Hellinger); err != nil {
+			return err
+		}
+	}
+	return out.EndTable()
 }
 
 func main() {
+	base := flag.String("base", "bits", "единица измерения расхождений: bits, nats или hartleys")
+	sourceText := flag.String("source", infotheory.DefaultSource.String(), "закон распределения источника: "+strings.Join(infotheory.SourceKinds, ", "))
+	formatName := flag.String("format", string(report.FormatText), "формат вывода: "+strings.Join(report.Formats, ", "))
+	flag.Parse()
+
+	unit, err := infotheory.ParseUnit(*base)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+	if source, err = infotheory.ParseSourceSpec(*sourceText); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+
+	format, err := report.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+	out, err := report.NewWriter(os.Stdout, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+
 	n := 53
 	itr := 6
-	runExperiment(itr, n)
+	if err := runExperiment(out, itr, n, unit); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+	if err := out.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
 }
@@ -1,41 +1,33 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
 	"math/rand"
+	"os"
+	"strings"
 	"time"
+
+	"itc/infotheory"
+	"itc/report"
 )
 
+// Закон распределения вероятностей источника (флаг -source)
+var source = infotheory.DefaultSource
+
+var sourceRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
 // Вероятности дискретных сообщений
 func generateProbabilities(n int) ([]float64, error) {
-	if n <= 0 {
-		return nil, fmt.Errorf("число вероятностей должно быть больше 0")
-	}
-	rand.Seed(time.Now().UnixNano())
-	probs := make([]float64, n)
-	sum := 0.0
-	for i := 0; i < n; i++ {
-		probs[i] = rand.Float64()
-		sum += probs[i]
-	}
-	for i := 0; i < n; i++ {
-		probs[i] /= sum
-	}
-	return probs, nil
+	return source.Generate(n, sourceRand)
 }
 
 func CalculateEntropy(n int, probs []float64) (float64, error) {
-	if len(probs) == 0 || n <= 0 {
-		return 0.0, fmt.Errorf("ошибка в входных данных")
+	if len(probs) != n {
+		return 0.0, infotheory.DimensionError("число вероятностей", n, len(probs))
 	}
-	entropy := 0.0
-	for i := 0; i < n; i++ {
-		if probs[i] > 0 {
-			entropy -= probs[i] * math.Log2(probs[i])
-		}
-	}
-	return entropy, nil
+	return infotheory.ShannonEntropy(probs, infotheory.Bits)
 }
 
 func generateDuration(n int, start, end float64) ([]float64, error) {
@@ -52,8 +44,17 @@ func generateDuration(n int, start, end float64) ([]float64, error) {
 }
 
 func generateMiddleDuration(n int, probs []float64, massiveDuraions []float64) (float64, error) {
-	if n <= 0 {
-		return 0.0, fmt.Errorf("число сообщений для расчета средней длительности должно быть больше 0")
+	if len(probs) != n {
+		return 0.0, infotheory.DimensionError("число вероятностей", n, len(probs))
+	}
+	if len(massiveDuraions) != n {
+		return 0.0, infotheory.DimensionError("число длительностей", n, len(massiveDuraions))
+	}
+	if _, err := infotheory.NewDistribution(probs); err != nil {
+		return 0.0, err
+	}
+	if err := infotheory.CheckNonNegative("длительности", massiveDuraions); err != nil {
+		return 0.0, err
 	}
 	middleDuration := 0.0
 	for i := 0; i < n; i++ {
@@ -90,60 +91,59 @@ func generateProbCorrectNoNoise(n int) ([]float64, error) {
 
 // Матрица вероятностей - если на входе Xi а на выходе Yj
 func generateConditionalMatrix(n int, probsRight []float64) ([][]float64, error) {
-	if n <= 0 {
-		return nil, fmt.Errorf("размер матрицы должен быть больше 0")
-	}
 	if len(probsRight) != n {
-		return nil, fmt.Errorf("длина массива probsRight должна быть равна %d", n)
+		return nil, infotheory.DimensionError("длина массива probsRight", n, len(probsRight))
 	}
-
-	matrix := make([][]float64, n)
-	for i := range matrix {
-		matrix[i] = make([]float64, n)
-	}
-
-	for i := 0; i < n; i++ {
-		if probsRight[i] < 0 || probsRight[i] > 1 {
-			return nil, fmt.Errorf("вероятность probsRight[%d] = %f вне диапазона [0,1]", i, probsRight[i])
-		}
-		matrix[i][i] = probsRight[i]
-		if n > 1 {
-			remainingProb := (1.0 - probsRight[i]) / float64(n-1)
-			for j := 0; j < n; j++ {
-				if j != i {
-					matrix[i][j] = remainingProb
-				}
-			}
-		}
+	matrix, err := infotheory.SymmetricChannel(probsRight)
+	if err != nil {
+		return nil, err
 	}
-	return matrix, nil
+	return matrix.Matrix(), nil
 }
 
 // Вероятности появления выходных символов Xi с учётом возможных ошибок
 func calculateOutputProbabilities(n int, inputProbs []float64, condMatrix [][]float64) ([]float64, error) {
-	if n <= 0 || len(inputProbs) != n || len(condMatrix) != n || len(condMatrix[0]) != n {
-		return nil, fmt.Errorf("некорректные размеры данных")
+	input, channel, err := channelModel(n, inputProbs, condMatrix)
+	if err != nil {
+		return nil, err
+	}
+	output, err := infotheory.OutputDistribution(input, channel)
+	if err != nil {
+		return nil, err
 	}
+	return output.Probs(), nil
+}
 
-	outputProbs := make([]float64, n)
-	for j := 0; j < n; j++ {
-		sum := 0.0
-		for i := 0; i < n; i++ {
-			sum += inputProbs[i] * condMatrix[i][j]
-		}
-		outputProbs[j] = sum
+// Проверка распределения вероятностей и матрицы канала размера n x n
+func channelModel(n int, probs []float64, condMatrix [][]float64) (infotheory.Distribution, infotheory.StochasticMatrix, error) {
+	if err := infotheory.CheckSquare("матрица канала", condMatrix, n); err != nil {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, err
 	}
-	return outputProbs, nil
+	if len(probs) != n {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, infotheory.DimensionError("число вероятностей", n, len(probs))
+	}
+	dist, err := infotheory.NewDistribution(probs)
+	if err != nil {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, err
+	}
+	channel, err := infotheory.NewStochasticMatrix(condMatrix)
+	if err != nil {
+		return infotheory.Distribution{}, infotheory.StochasticMatrix{}, err
+	}
+	return dist, channel, nil
 }
 
 func calculateBandwidthCapacity(n int, middleDuration float64, conditionEntropy float64) (float64, error) {
+	if middleDuration <= 0 {
+		return 0, infotheory.NewValidationError("средняя длительность", infotheory.ErrOutOfRange, fmt.Sprintf("должна быть больше 0, получено %g", middleDuration))
+	}
 	return math.Log2(float64(n)-conditionEntropy) / middleDuration, nil
 }
 
 // Это таблица, которая показывает, как часто конкретная пара "входной символ + выходной символ" встречается вместе.
 func calculateJointProbabilityMatrix(n int, outputProbs []float64, condMatrix [][]float64) ([][]float64, error) {
-	if n <= 0 || len(outputProbs) != n || len(condMatrix) != n || len(condMatrix[0]) != n {
-		return nil, fmt.Errorf("некорректные размеры данных")
+	if _, _, err := channelModel(n, outputProbs, condMatrix); err != nil {
+		return nil, err
 	}
 
 	jointMatrix := make([][]float64, n)
@@ -158,8 +158,19 @@ func calculateJointProbabilityMatrix(n int, outputProbs []float64, condMatrix []
 
 // Условная энтропия выходного сообщения
 func calculateConditionalEntropy(n int, jointMatrix [][]float64, outputProbs []float64) (float64, error) {
-	if n <= 0 || len(jointMatrix) != n || len(jointMatrix[0]) != n || len(outputProbs) != n {
-		return 0, fmt.Errorf("некорректные размеры данных")
+	if err := infotheory.CheckSquare("совместная матрица", jointMatrix, n); err != nil {
+		return 0, err
+	}
+	for _, row := range jointMatrix {
+		if err := infotheory.CheckNonNegative("совместная матрица", row); err != nil {
+			return 0, err
+		}
+	}
+	if len(outputProbs) != n {
+		return 0, infotheory.DimensionError("число выходных вероятностей", n, len(outputProbs))
+	}
+	if _, err := infotheory.NewDistribution(outputProbs); err != nil {
+		return 0, err
 	}
 
 	entropy := 0.0
@@ -175,115 +186,195 @@ func calculateConditionalEntropy(n int, jointMatrix [][]float64, outputProbs []f
 }
 
 func calculateBaudRate(entropy float64, conditionalEntropy float64, middleDuration float64) (float64, error) {
+	if middleDuration <= 0 {
+		return 0, infotheory.NewValidationError("средняя длительность", infotheory.ErrOutOfRange, fmt.Sprintf("должна быть больше 0, получено %g", middleDuration))
+	}
 	return (entropy - conditionalEntropy) / middleDuration, nil
 }
 
-func RunTests(n int) string {
-	// Структура для хранения результатов
-	type Result struct {
-		Entropy            float64
-		ConditionalEntropy float64
-		MiddleDuration     float64
-		BandwidthCapacity  float64
-		BaudRate           float64
+// Результаты одного эксперимента
+type testResult struct {
+	Entropy            float64
+	ConditionalEntropy float64
+	MiddleDuration     float64
+	BandwidthCapacity  float64
+	BaudRate           float64
+}
+
+// runTrial выполняет один эксперимент; generateRight задаёт вероятности безошибочной передачи
+func runTrial(n int, generateRight func() ([]float64, error)) (testResult, error) {
+	probs, err := generateProbabilities(n)
+	if err != nil {
+		return testResult{}, err
+	}
+	entropy, err := CalculateEntropy(n, probs)
+	if err != nil {
+		return testResult{}, err
+	}
+	massiveDuration, err := generateDuration(n, 0, float64(n))
+	if err != nil {
+		return testResult{}, err
 	}
+	probsRight, err := generateRight()
+	if err != nil {
+		return testResult{}, err
+	}
+	matrix, err := generateConditionalMatrix(n, probsRight)
+	if err != nil {
+		return testResult{}, err
+	}
+	outputProbs, err := calculateOutputProbabilities(n, probs, matrix)
+	if err != nil {
+		return testResult{}, err
+	}
+	jointProbs, err := calculateJointProbabilityMatrix(n, outputProbs, matrix)
+	if err != nil {
+		return testResult{}, err
+	}
+	conditionEntropy, err := calculateConditionalEntropy(n, jointProbs, outputProbs)
+	if err != nil {
+		return testResult{}, err
+	}
+	middleDuration, err := generateMiddleDuration(n, probs, massiveDuration)
+	if err != nil {
+		return testResult{}, err
+	}
+	bandwidthCapacity, err := calculateBandwidthCapacity(n, middleDuration, conditionEntropy)
+	if err != nil {
+		return testResult{}, err
+	}
+	baudRate, err := calculateBaudRate(entropy, conditionEntropy, middleDuration)
+	if err != nil {
+		return testResult{}, err
+	}
+
+	return testResult{
+		Entropy:            entropy,
+		ConditionalEntropy: conditionEntropy,
+		MiddleDuration:     middleDuration,
+		BandwidthCapacity:  bandwidthCapacity,
+		BaudRate:           baudRate,
+	}, nil
+}
+
+// testReport — результаты тестов канала с помехами и без помех
+type testReport struct {
+	Q         float64 // верхняя граница вероятности безошибочной передачи в канале с помехами
+	WithNoise []testResult
+	NoNoise   []testResult
+}
 
+func RunTests(n int) (testReport, error) {
 	// Массивы для хранения результатов
-	resultsWithNoise := make([]Result, 6)
-	resultsNoNoise := make([]Result, 6)
+	resultsWithNoise := make([]testResult, 6)
+	resultsNoNoise := make([]testResult, 6)
 	q := 1 - (1 / float64(n*2))
 
 	// Тест с помехами
 	for i := 0; i < 6; i++ {
-		probs, _ := generateProbabilities(n)
-		entropy, _ := CalculateEntropy(n, probs)
-		massiveDuration, _ := generateDuration(n, 0, float64(n))
-		probsRight, _ := generateProbCorrect(n, 0, q)
-		matrix, _ := generateConditionalMatrix(n, probsRight)
-		outputProbs, _ := calculateOutputProbabilities(n, probs, matrix)
-		jointProbs, _ := calculateJointProbabilityMatrix(n, outputProbs, matrix)
-		conditionEntropy, _ := calculateConditionalEntropy(n, jointProbs, outputProbs)
-		middleDuration, _ := generateMiddleDuration(n, probs, massiveDuration)
-		bandwidthCapacity, _ := calculateBandwidthCapacity(n, middleDuration, conditionEntropy)
-		baudRate, _ := calculateBaudRate(entropy, conditionEntropy, middleDuration)
-
-		resultsWithNoise[i] = Result{
-			Entropy:            entropy,
-			ConditionalEntropy: conditionEntropy,
-			MiddleDuration:     middleDuration,
-			BandwidthCapacity:  bandwidthCapacity,
-			BaudRate:           baudRate,
+		res, err := runTrial(n, func() ([]float64, error) {
+			return generateProbCorrect(n, 0, q)
+		})
+		if err != nil {
+			return testReport{}, fmt.Errorf("тест с помехами, эксперимент %d: %w", i+1, err)
 		}
+		resultsWithNoise[i] = res
 	}
 
 	// Тест без помех
 	for i := 0; i < 6; i++ {
-		probs, _ := generateProbabilities(n)
-		entropy, _ := CalculateEntropy(n, probs)
-		massiveDuration, _ := generateDuration(n, 0, float64(n))
-		probsRight, _ := generateProbCorrectNoNoise(n)
-		matrix, _ := generateConditionalMatrix(n, probsRight)
-		outputProbs, _ := calculateOutputProbabilities(n, probs, matrix)
-		jointProbs, _ := calculateJointProbabilityMatrix(n, outputProbs, matrix)
-		conditionEntropy, _ := calculateConditionalEntropy(n, jointProbs, outputProbs)
-		middleDuration, _ := generateMiddleDuration(n, probs, massiveDuration)
-		bandwidthCapacity, _ := calculateBandwidthCapacity(n, middleDuration, conditionEntropy)
-		baudRate, _ := calculateBaudRate(entropy, conditionEntropy, middleDuration)
-
-		resultsNoNoise[i] = Result{
-			Entropy:            entropy,
-			ConditionalEntropy: conditionEntropy,
-			MiddleDuration:     middleDuration,
-			BandwidthCapacity:  bandwidthCapacity,
-			BaudRate:           baudRate,
+		res, err := runTrial(n, func() ([]float64, error) {
+			return generateProbCorrectNoNoise(n)
+		})
+		if err != nil {
+			return testReport{}, fmt.Errorf("тест без помех, эксперимент %d: %w", i+1, err)
 		}
+		resultsNoNoise[i] = res
 	}
 
-	// Вычисление средних значений для канала с помехами
-	var avgBandwidthWithNoise, avgBaudRateWithNoise float64
-	for _, res := range resultsWithNoise {
-		avgBandwidthWithNoise += res.BandwidthCapacity
-		avgBaudRateWithNoise += res.BaudRate
-	}
-	avgBandwidthWithNoise /= 6.0
-	avgBaudRateWithNoise /= 6.0
+	return testReport{Q: q, WithNoise: resultsWithNoise, NoNoise: resultsNoNoise}, nil
+}
 
-	// Вычисление средних значений для канала без помех
-	var avgBandwidthNoNoise, avgBaudRateNoNoise float64
-	for _, res := range resultsNoNoise {
-		avgBandwidthNoNoise += res.BandwidthCapacity
-		avgBaudRateNoNoise += res.BaudRate
+// writeResults выводит таблицу экспериментов и средние значения C и R
+func writeResults(out report.Writer, name, title string, results []testResult) error {
+	err := out.BeginTable(&report.Table{
+		Name:  name,
+		Title: title,
+		Columns: []report.Column{
+			{Key: "experiment", Title: "Эксперимент", Width: 11},
+			{Key: "entropy", Title: "Энтропия H(X)", Format: "%.4f", Width: 13},
+			{Key: "conditional_entropy", Title: "Условная энтропия H(Y|X)", Format: "%.4f", Width: 24},
+			{Key: "middle_duration", Title: "Средняя длительность T (с)", Format: "%.4f", Width: 25},
+			{Key: "bandwidth_capacity", Title: "Пропускная способность C (бит/с)", Format: "%.4f", Width: 32},
+			{Key: "baud_rate", Title: "Скорость передачи R (бит/с)", Format: "%.4f", Width: 28},
+		},
+	})
+	if err != nil {
+		return err
 	}
-	avgBandwidthNoNoise /= 6.0
-	avgBaudRateNoNoise /= 6.0
 
-	// Формирование таблиц в формате Markdown
-	resultStr := "# Результаты тестов\n\n"
-	resultStr += fmt.Sprintf("## Тест с помехами (вероятность безошибочной передачи: [0, %.5f])\n", q)
-	resultStr += "| Эксперимент | Энтропия H(X) | Условная энтропия H(Y|X) | Средняя длительность T (с) | Пропускная способность C (бит/с) | Скорость передачи R (бит/с) |\n"
-	resultStr += "|-------------|---------------|--------------------------|---------------------------|----------------------------------|------------------------------|\n"
-	for i, res := range resultsWithNoise {
-		resultStr += fmt.Sprintf("| %11d | %13.4f | %24.4f | %25.4f | %32.4f | %28.4f |\n",
-			i+1, res.Entropy, res.ConditionalEntropy, res.MiddleDuration, res.BandwidthCapacity, res.BaudRate)
+	var avgBandwidth, avgBaudRate float64
+	for i, res := range results {
+		err := out.WriteRow(i+1, res.Entropy, res.ConditionalEntropy, res.MiddleDuration, res.BandwidthCapacity, res.BaudRate)
+		if err != nil {
+			return err
+		}
+		avgBandwidth += res.BandwidthCapacity
+		avgBaudRate += res.BaudRate
 	}
-	resultStr += fmt.Sprintf("\n**Средняя пропускная способность C (бит/с):** %.4f\n", avgBandwidthWithNoise)
-	resultStr += fmt.Sprintf("**Средняя скорость передачи R (бит/с):** %.4f\n", avgBaudRateWithNoise)
+	avgBandwidth /= float64(len(results))
+	avgBaudRate /= float64(len(results))
 
-	resultStr += "\n## Тест без помех (вероятность безошибочной передачи: 1.0)\n"
-	resultStr += "| Эксперимент | Энтропия H(X) | Условная энтропия H(Y|X) | Средняя длительность T (с) | Пропускная способность C (бит/с) | Скорость передачи R (бит/с) |\n"
-	resultStr += "|-------------|---------------|--------------------------|---------------------------|----------------------------------|------------------------------|\n"
-	for i, res := range resultsNoNoise {
-		resultStr += fmt.Sprintf("| %11d | %13.4f | %24.4f | %25.4f | %32.4f | %28.4f |\n",
-			i+1, res.Entropy, res.ConditionalEntropy, res.MiddleDuration, res.BandwidthCapacity, res.BaudRate)
-	}
-	resultStr += fmt.Sprintf("\n**Средняя пропускная способность C (бит/с):** %.4f\n", avgBandwidthNoNoise)
-	resultStr += fmt.Sprintf("**Средняя скорость передачи R (бит/с):** %.4f\n", avgBaudRateNoNoise)
+	return out.EndTable(
+		report.Field{Key: "mean_bandwidth_capacity", Title: "Средняя пропускная способность C (бит/с)", Value: avgBandwidth, Format: "%.4f"},
+		report.Field{Key: "mean_baud_rate", Title: "Средняя скорость передачи R (бит/с)", Value: avgBaudRate, Format: "%.4f"},
+	)
+}
 
-	return resultStr
+// WriteReport выводит результаты обоих тестов
+func WriteReport(out report.Writer, r testReport) error {
+	err := writeResults(out, "noise",
+		fmt.Sprintf("Тест с помехами (вероятность безошибочной передачи: [0, %.5f])", r.Q), r.WithNoise)
+	if err != nil {
+		return err
+	}
+	err = writeResults(out, "no_noise", "Тест без помех (вероятность безошибочной передачи: 1.0)", r.NoNoise)
+	if err != nil {
+		return err
+	}
+	return out.Close()
 }
 
 func main() {
+	sourceText := flag.String("source", infotheory.DefaultSource.String(), "закон распределения источника: "+strings.Join(infotheory.SourceKinds, ", "))
+	formatName := flag.String("format", string(report.FormatText), "формат вывода: "+strings.Join(report.Formats, ", "))
+	flag.Parse()
+
+	var err error
+	if source, err = infotheory.ParseSourceSpec(*sourceText); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+
+	format, err := report.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+	out, err := report.NewWriter(os.Stdout, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+
 	n := 16
-	result := RunTests(n)
-	fmt.Println(result)
+	result, err := RunTests(n)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+	if err := WriteReport(out, result); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
 }
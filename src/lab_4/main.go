@@ -126,21 +126,42 @@ func computeSyndrome(received []int, H [][]int, n, p, k int) []int {
 	return syndrome
 }
 
-// === 6. Поиск позиции ошибки по синдрому ===
-func findErrorPosition(syndrome []int, H [][]int, p, k int) (int, bool) {
-	for pos := 0; pos < k; pos++ {
-		match := true
-		for j := 0; j < p; j++ {
-			if H[j][pos] != syndrome[j] {
-				match = false
-				break
-			}
-		}
-		if match {
-			return pos, true
-		}
+// === 6. Таблица синдромов: значение синдрома → позиция ошибки ===
+// Столбец H с номером pos — синдром однократной ошибки в позиции pos, поэтому
+// таблица строится один раз, а поиск позиции занимает O(1) вместо перебора столбцов
+func buildSyndromeTable(H [][]int, n, p int) []int {
+	table := make([]int, 1<<p)
+	for i := range table {
+		table[i] = -1
+	}
+	for pos := n - 1; pos >= 0; pos-- {
+		table[syndromeIndex(columnOf(H, pos, p))] = pos
+	}
+	return table
+}
+
+// columnOf возвращает столбец pos матрицы H
+func columnOf(H [][]int, pos, p int) []int {
+	col := make([]int, p)
+	for j := 0; j < p; j++ {
+		col[j] = H[j][pos]
 	}
-	return -1, false
+	return col
+}
+
+// syndromeIndex преобразует синдром в число (первая строка H — старший бит)
+func syndromeIndex(syndrome []int) int {
+	val := 0
+	for _, bit := range syndrome {
+		val = val<<1 | bit
+	}
+	return val
+}
+
+// === 6.1 Поиск позиции ошибки по синдрому ===
+func findErrorPosition(syndrome []int, table []int) (int, bool) {
+	pos := table[syndromeIndex(syndrome)]
+	return pos, pos >= 0
 }
 
 // === Утилита: bool → int ===
@@ -182,6 +203,7 @@ func runExperiment(exp, k int) {
 	for i := range H {
 		fmt.Println(H[i])
 	}
+	syndromeTable := buildSyndromeTable(H, n, p)
 
 	// 3. Кодирование
 	codeword := encodeMessage(infoMsg, H, n, p, k)
@@ -197,7 +219,7 @@ func runExperiment(exp, k int) {
 	printVector("Синдром:                   ", syndrome)
 
 	// 6. Обнаружение и исправление
-	foundPos, found := findErrorPosition(syndrome, H, p, k)
+	foundPos, found := findErrorPosition(syndrome, syndromeTable)
 	if found {
 		fmt.Printf("Ошибка обнаружена и исправлена в позиции: %d\n", foundPos)
 		// Исправляем
@@ -206,7 +228,7 @@ func runExperiment(exp, k int) {
 		corrected[foundPos] = 1 - corrected[foundPos]
 		printVector("Исправленное сообщение:    ", corrected)
 	} else {
-		fmt.Println("Ошибка не обнаружена (возможно, в проверочном бите)")
+		fmt.Println("Ошибка не исправлена: синдром не соответствует одиночной ошибке")
 	}
 
 	fmt.Println("----------------------------------------")
@@ -217,7 +239,7 @@ func main() {
 	rand.Seed(time.Now().UnixNano())
 
 	fmt.Printf("Код Хэмминга: k = %d информационных битов\n", k)
-	fmt.Println("Запуск 8 экспериментов с обнаружением и исправлением однократных ошибок...\n")
+	fmt.Print("Запуск 8 экспериментов с обнаружением и исправлением однократных ошибок...\n\n")
 
 	for exp := 0; exp < 8; exp++ {
 		runExperiment(exp, k)